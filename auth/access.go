@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// EventAccessSecret signs invitee access tokens, access is disabled while it is empty.
+var EventAccessSecret string
+
+// LiveOrigins are origins of pages allowed to open live channel besides the service's own host, `*` allows any.
+var LiveOrigins []string
+
+// ErrNoEventAccessSecret is reported when access tokens are requested without a configured secret.
+var ErrNoEventAccessSecret = errors.New("EVENT_ACCESS_SECRET is not set")
+
+// EventAccessToken returns token granting `email` access to event's live channel.
+func EventAccessToken(eventId string, email string) string {
+	mac := hmac.New(sha256.New, []byte(EventAccessSecret))
+	mac.Write([]byte(eventId + ":" + strings.ToLower(email)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+var VerifyEventAccess = func(eventId string, email string, token string) bool {
+	if EventAccessSecret == "" || token == "" {
+		return false
+	}
+	return hmac.Equal([]byte(EventAccessToken(eventId, email)), []byte(token))
+}
+
+// CheckOrigin allows live channel connections of clients which send no `Origin`, e.g. apps and servers,
+// of pages of the service's own host and of pages of LiveOrigins, so that other sites cannot connect on behalf of viewers.
+func CheckOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range LiveOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	parsed, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(parsed.Host, r.Host)
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyEventAccess(t *testing.T) {
+	originalSecret := EventAccessSecret
+	EventAccessSecret = "event-access-secret"
+	eventId := "90a04b08-d820-4106-8ced-2cbc940728a3"
+	token := EventAccessToken(eventId, "Valid-Email@mail.com")
+
+	t.Run("Valid token, email is case insensitive", func(t *testing.T) {
+		assert.True(t, VerifyEventAccess(eventId, "valid-email@mail.com", token))
+	})
+	t.Run("Token of other invitee", func(t *testing.T) {
+		assert.False(t, VerifyEventAccess(eventId, "other-email@mail.com", token))
+	})
+	t.Run("Token of other event", func(t *testing.T) {
+		assert.False(t, VerifyEventAccess("db6bed50-7172-4051-86ab-d1e90705c692", "valid-email@mail.com", token))
+	})
+	t.Run("Empty token", func(t *testing.T) {
+		assert.False(t, VerifyEventAccess(eventId, "valid-email@mail.com", ""))
+	})
+	t.Run("Access disabled without secret", func(t *testing.T) {
+		EventAccessSecret = ""
+		assert.False(t, VerifyEventAccess(eventId, "valid-email@mail.com", EventAccessToken(eventId, "valid-email@mail.com")))
+	})
+	EventAccessSecret = originalSecret
+}
+
+func TestCheckOrigin(t *testing.T) {
+	originalOrigins := LiveOrigins
+	LiveOrigins = []string{"https://app.local"}
+	request := func(origin string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "http://events.local/event/id/ws", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		return r
+	}
+
+	t.Run("Client without origin", func(t *testing.T) {
+		assert.True(t, CheckOrigin(request("")))
+	})
+	t.Run("Page of the service's own host", func(t *testing.T) {
+		assert.True(t, CheckOrigin(request("https://events.local")))
+	})
+	t.Run("Page of allowed origin", func(t *testing.T) {
+		assert.True(t, CheckOrigin(request("https://APP.local")))
+	})
+	t.Run("Page of other origin", func(t *testing.T) {
+		assert.False(t, CheckOrigin(request("https://evil.local")))
+		assert.False(t, CheckOrigin(request("http://app.local")))
+	})
+	t.Run("Any origin allowed", func(t *testing.T) {
+		LiveOrigins = []string{"*"}
+		assert.True(t, CheckOrigin(request("https://evil.local")))
+	})
+	LiveOrigins = originalOrigins
+}
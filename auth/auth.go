@@ -1,25 +1,50 @@
 package auth
 
 import (
+	"app/config"
+	"app/db"
+	lg "app/logging"
+	"app/models"
 	"app/utils"
 	"app/weberrors"
-	"net/http"
-	"os"
+	"crypto/subtle"
 
 	"github.com/gin-gonic/gin"
 )
 
-var AdminToken = os.Getenv("ADMIN_TOKEN")
+var AdminToken string
+
+// Configure sets tokens and secrets requests are authenticated with.
+func Configure(settings config.Auth) {
+	AdminToken = settings.AdminToken
+	EventAccessSecret = settings.EventAccessSecret
+	LiveOrigins = settings.LiveOrigins
+}
+
+// AdminActor is recorded as author of changes made with admin token
+const AdminActor = "admin"
 
 func Middleware() gin.HandlerFunc {
 	return func(gctx *gin.Context) {
-		if token := gctx.Request.Header.Get(utils.API_AUTH_HEADER_KEY); token != AdminToken {
-			gctx.AbortWithStatusJSON(http.StatusUnauthorized, weberrors.AppError{
-				ErrorName:   http.StatusText(http.StatusUnauthorized),
-				Description: "invalid admin token",
-			})
+		if !IsAdmin(gctx) {
+			lg.WithContext(gctx).Warn().Msg("request with invalid admin token rejected")
+			if err := db.AppendAudit(gctx, models.AuditAuthFailure, gctx.Request.URL.Path, "", ""); err != nil {
+				lg.WithContext(gctx).Error().Msgf("failed authentication not recorded in audit trail: %v", err)
+			}
+			weberrors.Abort(gctx, &weberrors.InvalidAdminToken)
 			return
 		}
+		lg.SetActor(gctx, AdminActor)
 		gctx.Next()
 	}
 }
+
+// IsAdmin reports whether the request carries admin token, used by routes open to everyone.
+// Tokens are compared in constant time, no request is admin while the token is not configured.
+func IsAdmin(gctx *gin.Context) bool {
+	if AdminToken == "" {
+		return false
+	}
+	token := gctx.Request.Header.Get(utils.API_AUTH_HEADER_KEY)
+	return subtle.ConstantTimeCompare([]byte(token), []byte(AdminToken)) == 1
+}
@@ -1,21 +1,29 @@
 package auth
 
 import (
+	"app/config"
+	"app/db"
+	"app/models"
 	"app/utils"
 	"app/weberrors"
+	"context"
 	"net/http"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
 
 	testFuncs "app/testing"
 )
 
 var AdminTokenTestString = "admin_token_string"
 
+var originalAppendAudit = db.AppendAudit
+
 var MiddlewareTestCases = []struct {
 	description      string
 	adminToken       string
+	acceptLanguage   string
 	expectedStatus   int
 	expectedResponse interface{}
 }{
@@ -30,7 +38,19 @@ var MiddlewareTestCases = []struct {
 		expectedStatus: http.StatusUnauthorized,
 		expectedResponse: &weberrors.AppError{
 			ErrorName:   http.StatusText(http.StatusUnauthorized),
-			Description: "invalid admin token",
+			ErrorCode:   weberrors.InvalidAdminTokenCode,
+			Description: "Invalid admin token.",
+		},
+	},
+	{
+		description:    "Invalid token - German client",
+		adminToken:     "invalid_admin_token",
+		acceptLanguage: "de-CH, en;q=0.5",
+		expectedStatus: http.StatusUnauthorized,
+		expectedResponse: &weberrors.AppError{
+			ErrorName:   http.StatusText(http.StatusUnauthorized),
+			ErrorCode:   weberrors.InvalidAdminTokenCode,
+			Description: "Ungültiges Admin-Token.",
 		},
 	},
 }
@@ -38,6 +58,10 @@ var MiddlewareTestCases = []struct {
 func TestMiddleware(t *testing.T) {
 	originalToken := AdminToken
 	AdminToken = AdminTokenTestString
+	db.AppendAudit = func(c context.Context, action string, targetId string, before string, after string) error {
+		return nil
+	}
+	defer func() { db.AppendAudit = originalAppendAudit }()
 	r := gin.New()
 	r.Use(Middleware())
 	r.Any("/", func(ctx *gin.Context) {
@@ -48,11 +72,29 @@ func TestMiddleware(t *testing.T) {
 			client := testFuncs.GetTestClient(t, r)
 			res := client.GET("/").
 				WithHeader(utils.API_AUTH_HEADER_KEY, testCase.adminToken).
+				WithHeader("Accept-Language", testCase.acceptLanguage).
 				Expect()
 			res.Status(testCase.expectedStatus)
 			res.JSON().Equal(testCase.expectedResponse)
 		})
 	}
+	t.Run("failure is audited", func(t *testing.T) {
+		var audited []string
+		db.AppendAudit = func(c context.Context, action string, targetId string, before string, after string) error {
+			audited = append(audited, action, targetId)
+			return nil
+		}
+		client := testFuncs.GetTestClient(t, r)
+		client.GET("/").
+			WithHeader(utils.API_AUTH_HEADER_KEY, "invalid_admin_token").
+			Expect().
+			Status(http.StatusUnauthorized)
+		client.GET("/").
+			WithHeader(utils.API_AUTH_HEADER_KEY, AdminTokenTestString).
+			Expect().
+			Status(http.StatusOK)
+		assert.Equal(t, []string{models.AuditAuthFailure, "/"}, audited)
+	})
 	t.Run("submit without any header", func(t *testing.T) {
 		client := testFuncs.GetTestClient(t, r)
 		res := client.GET("/").
@@ -60,5 +102,27 @@ func TestMiddleware(t *testing.T) {
 		res.Status(MiddlewareTestCases[1].expectedStatus)
 		res.JSON().Equal(MiddlewareTestCases[1].expectedResponse)
 	})
+	t.Run("empty admin token is rejected", func(t *testing.T) {
+		AdminToken = ""
+		defer func() { AdminToken = AdminTokenTestString }()
+		client := testFuncs.GetTestClient(t, r)
+		client.GET("/").
+			Expect().
+			Status(http.StatusUnauthorized)
+		client.GET("/").
+			WithHeader(utils.API_AUTH_HEADER_KEY, "").
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
 	AdminToken = originalToken
 }
+
+func TestConfigure(t *testing.T) {
+	originalToken, originalSecret := AdminToken, EventAccessSecret
+	defer func() { AdminToken, EventAccessSecret = originalToken, originalSecret }()
+
+	Configure(config.Auth{AdminToken: "configured_token", EventAccessSecret: "configured_secret"})
+
+	assert.Equal(t, "configured_token", AdminToken)
+	assert.Equal(t, "configured_secret", EventAccessSecret)
+}
@@ -0,0 +1,139 @@
+package catalog
+
+import (
+	"app/db"
+	lg "app/logging"
+	"app/models"
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/exp/slices"
+)
+
+// refreshInterval is how long changes made through other replicas may take to apply here
+var refreshInterval = 10 * time.Second
+
+// DefaultProfiles are stored when the catalog is empty, e.g. on the first start.
+var DefaultProfiles = []models.QualityProfile{
+	{Kind: models.QualityVideo, Name: "720p", Codec: "avc1.64001f", BitrateKbps: 3000, Width: 1280, Height: 720},
+	{Kind: models.QualityVideo, Name: "1080p", Codec: "avc1.640028", BitrateKbps: 6000, Width: 1920, Height: 1080},
+	{Kind: models.QualityVideo, Name: "1440p", Codec: "avc1.640032", BitrateKbps: 12000, Width: 2560, Height: 1440},
+	{Kind: models.QualityVideo, Name: "2160p", Codec: "avc1.640033", BitrateKbps: 20000, Width: 3840, Height: 2160},
+	{Kind: models.QualityAudio, Name: "Low", Codec: "mp4a.40.2", BitrateKbps: 64, Channels: 2, SampleRateHz: 44100},
+	{Kind: models.QualityAudio, Name: "Mid", Codec: "mp4a.40.2", BitrateKbps: 128, Channels: 2, SampleRateHz: 48000},
+	{Kind: models.QualityAudio, Name: "High", Codec: "mp4a.40.2", BitrateKbps: 256, Channels: 2, SampleRateHz: 48000},
+}
+
+// snapshot is replaced as a whole, so validation of one request never sees a half applied change
+type snapshot struct {
+	// profiles are sorted by kind and bitrate
+	profiles []models.QualityProfile
+	tenants  map[string]models.TenantQualities
+}
+
+var current atomic.Pointer[snapshot]
+
+func init() {
+	Set(DefaultProfiles, map[string]models.TenantQualities{})
+}
+
+// Set replaces the catalog held in memory, `profiles` have to be sorted by kind and bitrate.
+func Set(profiles []models.QualityProfile, tenants map[string]models.TenantQualities) {
+	current.Store(&snapshot{profiles: profiles, tenants: tenants})
+}
+
+// Load seeds the database with DefaultProfiles if it has no catalog yet, e.g. on the first start, and loads the catalog.
+// It is meant for startup, later changes are picked up by Refresh.
+func Load(c context.Context) error {
+	if err := db.SeedQualityProfiles(c, DefaultProfiles); err != nil {
+		return err
+	}
+	return Refresh(c)
+}
+
+// Refresh loads the catalog from the database.
+var Refresh = func(c context.Context) error {
+	profiles, err := db.GetQualityProfiles(c)
+	if err != nil {
+		return err
+	}
+	tenants, err := db.GetTenantQualities(c)
+	if err != nil {
+		return err
+	}
+	Set(profiles, tenants)
+	return nil
+}
+
+// Run keeps the catalog in sync with changes made through other replicas until `c` is done.
+func Run(c context.Context) {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		if err := Refresh(c); err != nil {
+			log.Logger.Error().Msgf("error refreshing quality catalog, keeping the previous one: %v", err)
+		}
+		select {
+		case <-c.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Profiles returns profiles of `kind`, sorted by bitrate.
+func Profiles(kind string) []models.QualityProfile {
+	profiles := []models.QualityProfile{}
+	for _, profile := range current.Load().profiles {
+		if profile.Kind == kind {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+func Profile(kind string, name string) (models.QualityProfile, bool) {
+	for _, profile := range current.Load().profiles {
+		if profile.Kind == kind && profile.Name == name {
+			return profile, true
+		}
+	}
+	return models.QualityProfile{}, false
+}
+
+// Tenants returns enabled sets of tenants which have one.
+func Tenants() map[string]models.TenantQualities {
+	return current.Load().tenants
+}
+
+// Allowed returns names of `kind` profiles enabled for tenant of `c`, sorted by bitrate.
+// Tenants without enabled set, as well as requests without tenant, may use every profile.
+func Allowed(c context.Context, kind string) []string {
+	loaded := current.Load()
+	enabled := loaded.tenants[lg.Tenant(c)]
+	enabledNames := enabled.Video
+	if kind == models.QualityAudio {
+		enabledNames = enabled.Audio
+	}
+	names := []string{}
+	for _, profile := range loaded.profiles {
+		if profile.Kind != kind {
+			continue
+		}
+		if len(enabledNames) == 0 || slices.Contains(enabledNames, profile.Name) {
+			names = append(names, profile.Name)
+		}
+	}
+	return names
+}
+
+// Default returns `preferred` if tenant of `c` may use it, otherwise its `kind` profile of the lowest bitrate.
+func Default(c context.Context, kind string, preferred string) string {
+	allowed := Allowed(c, kind)
+	if len(allowed) == 0 || slices.Contains(allowed, preferred) {
+		return preferred
+	}
+	return allowed[0]
+}
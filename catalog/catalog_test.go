@@ -0,0 +1,87 @@
+package catalog
+
+import (
+	"app/db"
+	lg "app/logging"
+	"app/models"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var tenantCatalog = map[string]models.TenantQualities{
+	"tenant-1": {Video: []string{"1080p", "720p"}},
+	"tenant-2": {Video: []string{"1440p", "4320p"}, Audio: []string{"High"}},
+}
+
+func TestAllowed(t *testing.T) {
+	defer Set(DefaultProfiles, map[string]models.TenantQualities{})
+	Set(DefaultProfiles, tenantCatalog)
+	c := context.Background()
+
+	assert.Equal(t, []string{"720p", "1080p", "1440p", "2160p"}, Allowed(c, models.QualityVideo),
+		"request without tenant may use every profile")
+	assert.Equal(t, []string{"720p", "1080p"}, Allowed(lg.WithTenant(c, "tenant-1"), models.QualityVideo))
+	assert.Equal(t, []string{"Low", "Mid", "High"}, Allowed(lg.WithTenant(c, "tenant-1"), models.QualityAudio),
+		"empty enabled list should allow every profile of the kind")
+	assert.Equal(t, []string{"1440p"}, Allowed(lg.WithTenant(c, "tenant-2"), models.QualityVideo),
+		"profiles missing in catalog should not be allowed")
+}
+
+func TestDefault(t *testing.T) {
+	defer Set(DefaultProfiles, map[string]models.TenantQualities{})
+	Set(DefaultProfiles, tenantCatalog)
+	c := context.Background()
+
+	assert.Equal(t, "720p", Default(lg.WithTenant(c, "tenant-1"), models.QualityVideo, "720p"))
+	assert.Equal(t, "1440p", Default(lg.WithTenant(c, "tenant-2"), models.QualityVideo, "720p"))
+	assert.Equal(t, "High", Default(lg.WithTenant(c, "tenant-2"), models.QualityAudio, "Low"))
+}
+
+func TestRefresh(t *testing.T) {
+	originalSeed, originalGetProfiles, originalGetTenants :=
+		db.SeedQualityProfiles, db.GetQualityProfiles, db.GetTenantQualities
+	defer func() {
+		db.SeedQualityProfiles, db.GetQualityProfiles, db.GetTenantQualities =
+			originalSeed, originalGetProfiles, originalGetTenants
+		Set(DefaultProfiles, map[string]models.TenantQualities{})
+	}()
+	stored := []models.QualityProfile{DefaultProfiles[1], DefaultProfiles[4]}
+	seeded := 0
+	db.SeedQualityProfiles = func(c context.Context, profiles []models.QualityProfile) error {
+		assert.Equal(t, DefaultProfiles, profiles)
+		seeded++
+		return nil
+	}
+	db.GetQualityProfiles = func(c context.Context) ([]models.QualityProfile, error) {
+		return stored, nil
+	}
+	db.GetTenantQualities = func(c context.Context) (map[string]models.TenantQualities, error) {
+		return tenantCatalog, nil
+	}
+
+	t.Run("Seeds catalog on load only", func(t *testing.T) {
+		assert.Nil(t, Load(context.Background()))
+		assert.Equal(t, 1, seeded)
+		assert.Nil(t, Refresh(context.Background()))
+		assert.Equal(t, 1, seeded, "refresh should not seed")
+	})
+
+	t.Run("Loads stored catalog", func(t *testing.T) {
+		assert.Nil(t, Refresh(context.Background()))
+		assert.Equal(t, []models.QualityProfile{DefaultProfiles[1]}, Profiles(models.QualityVideo))
+		assert.Equal(t, tenantCatalog, Tenants())
+		_, found := Profile(models.QualityVideo, "720p")
+		assert.False(t, found)
+	})
+
+	t.Run("Fail - keeps previous catalog", func(t *testing.T) {
+		db.GetTenantQualities = func(c context.Context) (map[string]models.TenantQualities, error) {
+			return nil, errors.New("redis connection error")
+		}
+		assert.Error(t, Refresh(context.Background()))
+		assert.Equal(t, []models.QualityProfile{DefaultProfiles[4]}, Profiles(models.QualityAudio))
+	})
+}
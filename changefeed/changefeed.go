@@ -0,0 +1,116 @@
+package changefeed
+
+import (
+	"app/db"
+	"app/models"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// a single reader per replica reads new changes from the change log and fans them out to subscribers,
+// so that streaming clients do not hold a blocking redis read each
+
+// BlockTimeout is how long the reader waits for new changes in one read
+var BlockTimeout = 15 * time.Second
+
+// subscriberBuffer is how many batches a subscriber may lag behind before it is dropped
+const subscriberBuffer = 16
+
+type feed struct {
+	mutex       sync.Mutex
+	subscribers map[chan []models.DomainEvent]struct{}
+	// stop stops the running reader, nil while there is none
+	stop context.CancelFunc
+}
+
+var changes = feed{subscribers: map[chan []models.DomainEvent]struct{}{}}
+
+// Subscribe returns channel receiving batches of changes appended after the call and function ending the subscription.
+// The reader is started by the first subscriber and stopped after the last one leaves.
+// The channel is closed when the subscriber lags behind or reading fails, clients resume from the log then.
+var Subscribe = func(c context.Context) (<-chan []models.DomainEvent, func(), error) {
+	changes.mutex.Lock()
+	defer changes.mutex.Unlock()
+	if changes.stop == nil {
+		lastId, err := db.GetLatestChangeId(c)
+		if err != nil {
+			return nil, nil, err
+		}
+		readerContext, stop := context.WithCancel(context.Background())
+		changes.stop = stop
+		go changes.read(readerContext, lastId)
+	}
+	subscriber := make(chan []models.DomainEvent, subscriberBuffer)
+	changes.subscribers[subscriber] = struct{}{}
+	return subscriber, func() { changes.unsubscribe(subscriber) }, nil
+}
+
+func (f *feed) read(c context.Context, lastId string) {
+	log.Logger.Debug().Msgf("change feed reader started after `%v`", lastId)
+	for {
+		batch, err := db.ReadChanges(c, lastId, BlockTimeout)
+		if c.Err() != nil {
+			log.Logger.Debug().Msg("change feed reader stopped")
+			return
+		}
+		if err != nil {
+			log.Logger.Error().Msgf("error reading change feed: %v", err)
+			f.closeAll(c)
+			return
+		}
+		if len(batch) == 0 {
+			continue
+		}
+		lastId = batch[len(batch)-1].Id
+		f.publish(c, batch)
+	}
+}
+
+// publish sends `batch` to all subscribers, subscribers which lag behind are dropped.
+func (f *feed) publish(c context.Context, batch []models.DomainEvent) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	// the reader may have been stopped and replaced while reading
+	if c.Err() != nil {
+		return
+	}
+	for subscriber := range f.subscribers {
+		select {
+		case subscriber <- batch:
+		default:
+			f.remove(subscriber)
+		}
+	}
+}
+
+func (f *feed) closeAll(c context.Context) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if c.Err() != nil {
+		return
+	}
+	for subscriber := range f.subscribers {
+		f.remove(subscriber)
+	}
+}
+
+func (f *feed) unsubscribe(subscriber chan []models.DomainEvent) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, ok := f.subscribers[subscriber]; ok {
+		f.remove(subscriber)
+	}
+}
+
+// remove closes `subscriber` and stops the reader when it was the last one, `f.mutex` has to be held.
+func (f *feed) remove(subscriber chan []models.DomainEvent) {
+	delete(f.subscribers, subscriber)
+	close(subscriber)
+	if len(f.subscribers) == 0 && f.stop != nil {
+		f.stop()
+		f.stop = nil
+	}
+}
@@ -0,0 +1,91 @@
+package changefeed
+
+import (
+	"app/db"
+	"app/models"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var batch = []models.DomainEvent{{Id: "1682000000000-0", Type: models.DomainEventCreated}}
+
+// mockReader makes the reader return `batch` once and then results sent to `reads`
+func mockReader(t *testing.T, reads chan error) {
+	db.GetLatestChangeId = func(c context.Context) (string, error) {
+		return "1681999999999-0", nil
+	}
+	db.ReadChanges = func(c context.Context, lastId string, block time.Duration) ([]models.DomainEvent, error) {
+		if lastId == "1681999999999-0" {
+			return batch, nil
+		}
+		assert.Equal(t, batch[0].Id, lastId)
+		select {
+		case err := <-reads:
+			return []models.DomainEvent{}, err
+		case <-c.Done():
+			return nil, c.Err()
+		}
+	}
+}
+
+func receive(t *testing.T, subscriber <-chan []models.DomainEvent) ([]models.DomainEvent, bool) {
+	select {
+	case changes, ok := <-subscriber:
+		return changes, ok
+	case <-time.After(time.Second):
+		t.Fatal("no changes received")
+		return nil, false
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	// mocks are kept for all cases as reader of the previous case may still be stopping
+	reads := make(chan error)
+	mockReader(t, reads)
+
+	t.Run("Changes are fanned out to all subscribers", func(t *testing.T) {
+		first, unsubscribeFirst, err := Subscribe(context.Background())
+		assert.Nil(t, err)
+		second, unsubscribeSecond, err := Subscribe(context.Background())
+		assert.Nil(t, err)
+
+		received, ok := receive(t, first)
+		assert.True(t, ok)
+		assert.Equal(t, batch, received)
+		received, ok = receive(t, second)
+		assert.True(t, ok)
+		assert.Equal(t, batch, received)
+
+		unsubscribeFirst()
+		unsubscribeSecond()
+		unsubscribeSecond()
+		changes.mutex.Lock()
+		assert.Nil(t, changes.stop, "reader is stopped after the last subscriber leaves")
+		changes.mutex.Unlock()
+	})
+
+	t.Run("Subscribers are closed when reading fails", func(t *testing.T) {
+		subscriber, unsubscribe, err := Subscribe(context.Background())
+		assert.Nil(t, err)
+		defer unsubscribe()
+		_, ok := receive(t, subscriber)
+		assert.True(t, ok)
+
+		reads <- errors.New("connection refused")
+
+		_, ok = receive(t, subscriber)
+		assert.False(t, ok)
+	})
+
+	t.Run("Fail - latest change id cannot be read", func(t *testing.T) {
+		db.GetLatestChangeId = func(c context.Context) (string, error) {
+			return "", db.ErrUnavailable
+		}
+		_, _, err := Subscribe(context.Background())
+		assert.ErrorIs(t, err, db.ErrUnavailable)
+	})
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"app/config"
+	"app/db"
+	"context"
+	"flag"
+	"os"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Rebuilds event documents from the event log, e.g. after data loss or manual edits in Redis.
+// Usage: go run ./cmd/replay [-from <change id>] [-config <file>]
+func main() {
+	from := flag.String("from", "0-0", "replay changes recorded after this change id")
+	settings, err := config.Load(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Logger.Fatal().Msgf("invalid configuration:\n%v", err)
+	}
+	db.Connect(settings.Redis)
+	replayed, err := db.ReplayChanges(context.Background(), *from)
+	if err != nil {
+		log.Logger.Fatal().Msgf("replay failed after %v changes: %v", replayed, err)
+	}
+	log.Logger.Info().Msgf("replayed %v changes", replayed)
+}
@@ -0,0 +1,277 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+// Config holds all settings of the service, see Load for where they come from.
+// Tags name the key in YAML/TOML file, the environment variable and the command line flag of every setting.
+type Config struct {
+	Server    Server    `yaml:"server" toml:"server"`
+	Redis     Redis     `yaml:"redis" toml:"redis"`
+	Auth      Auth      `yaml:"auth" toml:"auth"`
+	Log       Log       `yaml:"log" toml:"log"`
+	Tracing   Tracing   `yaml:"tracing" toml:"tracing"`
+	Notifier  Notifier  `yaml:"notifier" toml:"notifier"`
+	Scheduler Scheduler `yaml:"scheduler" toml:"scheduler"`
+	Streams   Streams   `yaml:"streams" toml:"streams"`
+	Build     Build     `yaml:"build" toml:"build"`
+	// Qualities and Limits, as well as log levels and sampling, are applied again when configuration is reloaded
+	Qualities Qualities `yaml:"qualities" toml:"qualities"`
+	Limits    Limits    `yaml:"limits" toml:"limits"`
+
+	// file is where settings were loaded from, empty if there is no file
+	file string
+}
+
+// File is path of the configuration file, empty if settings do not come from a file.
+func (c Config) File() string {
+	return c.file
+}
+
+type Server struct {
+	Port int `yaml:"port" toml:"port" env:"PORT" flag:"port"`
+	// ShutdownTimeout bounds draining of in-flight requests on SIGTERM/SIGINT, and then stopping of workers
+	ShutdownTimeout Duration `yaml:"shutdownTimeout" toml:"shutdownTimeout" env:"SHUTDOWN_TIMEOUT" flag:"shutdown-timeout"`
+	// DrainDelay is how long readiness fails before new connections are refused
+	DrainDelay Duration `yaml:"drainDelay" toml:"drainDelay" env:"SHUTDOWN_DRAIN_DELAY" flag:"shutdown-drain-delay"`
+}
+
+type Redis struct {
+	Host     string `yaml:"host" toml:"host" env:"REDIS_HOST" flag:"redis-host"`
+	Port     int    `yaml:"port" toml:"port" env:"REDIS_PORT" flag:"redis-port"`
+	Password string `yaml:"password" toml:"password" env:"REDIS_PASSWORD"`
+	// ChangesMaxLen approximately caps the event log, 0 keeps whole history which replay relies on
+	ChangesMaxLen int64 `yaml:"changesMaxLen" toml:"changesMaxLen" env:"CHANGES_MAX_LEN"`
+}
+
+// Addr is `host:port` of Redis server.
+func (r Redis) Addr() string {
+	return net.JoinHostPort(r.Host, strconv.Itoa(r.Port))
+}
+
+type Auth struct {
+	AdminToken string `yaml:"adminToken" toml:"adminToken" env:"ADMIN_TOKEN"`
+	// EventAccessSecret signs invitee access tokens, live channel is closed to invitees while it is empty
+	EventAccessSecret string `yaml:"eventAccessSecret" toml:"eventAccessSecret" env:"EVENT_ACCESS_SECRET"`
+	// LiveOrigins are origins of pages allowed to open live channel besides the service's own host, `*` allows any
+	LiveOrigins []string `yaml:"liveOrigins" toml:"liveOrigins" env:"LIVE_ALLOWED_ORIGINS"`
+}
+
+type Log struct {
+	// Format is `json` for log shippers or `console` for humans
+	Format string `yaml:"format" toml:"format" env:"LOG_FORMAT" flag:"log-format"`
+	Level  string `yaml:"level" toml:"level" env:"LOG_LEVEL" flag:"log-level"`
+	// PackageLevels overrides Level for packages of this module, `db=debug,webhooks=warn` in environment
+	PackageLevels map[string]string `yaml:"packageLevels" toml:"packageLevels" env:"LOG_PACKAGE_LEVELS"`
+	// SampleEvery keeps only every n-th successful request log, 0 and 1 keep all of them
+	SampleEvery uint32 `yaml:"sampleEvery" toml:"sampleEvery" env:"LOG_SAMPLE_EVERY"`
+}
+
+type Tracing struct {
+	// Exporter is `none`, `otlp` or `stdout`, OTLP exporter reads standard OTEL_EXPORTER_OTLP_* variables
+	Exporter string `yaml:"exporter" toml:"exporter" env:"OTEL_TRACES_EXPORTER"`
+}
+
+type Notifier struct {
+	// Channel is `log`, `file`, `smtp` or `webhook`
+	Channel    string `yaml:"channel" toml:"channel" env:"NOTIFIER"`
+	File       string `yaml:"file" toml:"file" env:"NOTIFIER_FILE"`
+	SMTP       SMTP   `yaml:"smtp" toml:"smtp"`
+	WebhookURL string `yaml:"webhookUrl" toml:"webhookUrl" env:"NOTIFIER_WEBHOOK_URL"`
+}
+
+type SMTP struct {
+	Addr     string `yaml:"addr" toml:"addr" env:"SMTP_ADDR"`
+	From     string `yaml:"from" toml:"from" env:"SMTP_FROM"`
+	Username string `yaml:"username" toml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" toml:"password" env:"SMTP_PASSWORD"`
+}
+
+type Scheduler struct {
+	// ReminderMinutes is how long before event start invitees are reminded
+	ReminderMinutes int `yaml:"reminderMinutes" toml:"reminderMinutes" env:"REMINDER_MINUTES"`
+}
+
+// Streams are URL templates of renditions manifests point players to,
+// `{eventId}`, `{quality}`, `{language}` and `{format}` (subtitles) are replaced.
+type Streams struct {
+	HLSVideoURL  string `yaml:"hlsVideoUrl" toml:"hlsVideoUrl" env:"HLS_VIDEO_URL"`
+	HLSAudioURL  string `yaml:"hlsAudioUrl" toml:"hlsAudioUrl" env:"HLS_AUDIO_URL"`
+	DASHVideoURL string `yaml:"dashVideoUrl" toml:"dashVideoUrl" env:"DASH_VIDEO_URL"`
+	DASHAudioURL string `yaml:"dashAudioUrl" toml:"dashAudioUrl" env:"DASH_AUDIO_URL"`
+	// HLSSubtitleURL is a media playlist of subtitle files, DASHSubtitleURL the sidecar file itself
+	HLSSubtitleURL  string `yaml:"hlsSubtitleUrl" toml:"hlsSubtitleUrl" env:"HLS_SUBTITLE_URL"`
+	DASHSubtitleURL string `yaml:"dashSubtitleUrl" toml:"dashSubtitleUrl" env:"DASH_SUBTITLE_URL"`
+}
+
+// Build describes the deployment, it is set by the pipeline.
+type Build struct {
+	Environment string `yaml:"environment" toml:"environment" env:"ENV"`
+	CommitTag   string `yaml:"commitTag" toml:"commitTag" env:"COMMIT_TAG"`
+	DeployDate  string `yaml:"deployDate" toml:"deployDate" env:"DEPLOY_DATE"`
+}
+
+// Qualities used by events which do not set any, allowed qualities are managed in the quality catalog.
+// Tenants which do not have the default enabled get their lowest enabled quality instead.
+type Qualities struct {
+	DefaultResolution string `yaml:"defaultResolution" toml:"defaultResolution" env:"DEFAULT_RESOLUTION"`
+	DefaultAudio      string `yaml:"defaultAudio" toml:"defaultAudio" env:"DEFAULT_AUDIO"`
+}
+
+// Limits of listing endpoints, e.g. audit trail or event log.
+type Limits struct {
+	DefaultPageSize int64 `yaml:"defaultPageSize" toml:"defaultPageSize" env:"DEFAULT_PAGE_SIZE"`
+	MaxPageSize     int64 `yaml:"maxPageSize" toml:"maxPageSize" env:"MAX_PAGE_SIZE"`
+}
+
+// Duration is written as Go duration string, e.g. `30s` or `1m30s`.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+var logFormats = []string{"json", "console"}
+var logLevels = []string{"trace", "debug", "info", "warn", "error", "disabled"}
+var traceExporters = []string{"none", "otlp", "stdout"}
+var notifierChannels = []string{"log", "file", "smtp", "webhook"}
+
+// Default returns settings used when nothing else is configured, good for local development.
+func Default() Config {
+	return Config{
+		Server: Server{
+			Port:            3000,
+			ShutdownTimeout: Duration(30 * time.Second),
+		},
+		Redis: Redis{
+			Host: "127.0.0.1",
+			Port: 6379,
+		},
+		Log: Log{
+			Format:        "console",
+			Level:         "info",
+			PackageLevels: map[string]string{},
+		},
+		Tracing: Tracing{Exporter: "none"},
+		Notifier: Notifier{
+			Channel: "log",
+			File:    "notifications.log",
+			SMTP: SMTP{
+				Addr: "127.0.0.1:1025",
+				From: "noreply@event-handler.local",
+			},
+		},
+		Scheduler: Scheduler{ReminderMinutes: 15},
+		Streams: Streams{
+			HLSVideoURL:     "http://localhost:8080/live/{eventId}/hls/video/{quality}.m3u8",
+			HLSAudioURL:     "http://localhost:8080/live/{eventId}/hls/audio/{language}/{quality}.m3u8",
+			DASHVideoURL:    "http://localhost:8080/live/{eventId}/dash/video/{quality}/",
+			DASHAudioURL:    "http://localhost:8080/live/{eventId}/dash/audio/{language}/{quality}/",
+			HLSSubtitleURL:  "http://localhost:8080/live/{eventId}/hls/subtitles/{format}/{language}.m3u8",
+			DASHSubtitleURL: "http://localhost:8080/live/{eventId}/dash/subtitles/{format}/{language}",
+		},
+		Build: Build{
+			CommitTag:  "unset",
+			DeployDate: "unset",
+		},
+		Qualities: Qualities{
+			DefaultResolution: "720p",
+			DefaultAudio:      "Low",
+		},
+		Limits: Limits{
+			DefaultPageSize: 100,
+			MaxPageSize:     1000,
+		},
+	}
+}
+
+// Validate reports every invalid setting, not only the first one.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(valid bool, format string, args ...interface{}) {
+		if !valid {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	oneOf := func(key string, value string, allowed []string) {
+		check(slices.Contains(allowed, value), "%v must be one of %v, got `%v`", key, allowed, value)
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port < 65536, "server.port must be between 1 and 65535, got %v", c.Server.Port)
+	check(c.Server.ShutdownTimeout > 0, "server.shutdownTimeout must be positive, got %v", time.Duration(c.Server.ShutdownTimeout))
+	check(c.Server.DrainDelay >= 0, "server.drainDelay must not be negative, got %v", time.Duration(c.Server.DrainDelay))
+
+	check(c.Redis.Host != "", "redis.host is required")
+	check(c.Redis.Port > 0 && c.Redis.Port < 65536, "redis.port must be between 1 and 65535, got %v", c.Redis.Port)
+	check(c.Redis.ChangesMaxLen >= 0, "redis.changesMaxLen must not be negative, got %v", c.Redis.ChangesMaxLen)
+
+	// with empty token every request without the header would be admin
+	check(c.Auth.AdminToken != "", "auth.adminToken is required")
+	for _, origin := range c.Auth.LiveOrigins {
+		parsed, err := url.Parse(origin)
+		check(origin == "*" || err == nil && parsed.Scheme != "" && parsed.Host != "" && parsed.Path == "",
+			"auth.liveOrigins must contain `*` or origins like `https://host:port`, got `%v`", origin)
+	}
+
+	oneOf("log.format", c.Log.Format, logFormats)
+	oneOf("log.level", c.Log.Level, logLevels)
+	for name, level := range c.Log.PackageLevels {
+		oneOf(fmt.Sprintf("log.packageLevels.%v", name), level, logLevels)
+	}
+
+	oneOf("tracing.exporter", c.Tracing.Exporter, traceExporters)
+
+	oneOf("notifier.channel", c.Notifier.Channel, notifierChannels)
+	switch c.Notifier.Channel {
+	case "file":
+		check(c.Notifier.File != "", "notifier.file is required by `file` notifier")
+	case "smtp":
+		check(c.Notifier.SMTP.Addr != "", "notifier.smtp.addr is required by `smtp` notifier")
+		check(c.Notifier.SMTP.From != "", "notifier.smtp.from is required by `smtp` notifier")
+	case "webhook":
+		check(c.Notifier.WebhookURL != "", "notifier.webhookUrl is required by `webhook` notifier")
+	}
+
+	check(c.Scheduler.ReminderMinutes >= 0, "scheduler.reminderMinutes must not be negative, got %v", c.Scheduler.ReminderMinutes)
+
+	for _, stream := range []struct {
+		key      string
+		template string
+	}{
+		{"streams.hlsVideoUrl", c.Streams.HLSVideoURL},
+		{"streams.hlsAudioUrl", c.Streams.HLSAudioURL},
+		{"streams.dashVideoUrl", c.Streams.DASHVideoURL},
+		{"streams.dashAudioUrl", c.Streams.DASHAudioURL},
+		{"streams.hlsSubtitleUrl", c.Streams.HLSSubtitleURL},
+		{"streams.dashSubtitleUrl", c.Streams.DASHSubtitleURL},
+	} {
+		check(strings.Contains(stream.template, "{eventId}"), "%v must contain `{eventId}`, got `%v`",
+			stream.key, stream.template)
+	}
+
+	check(c.Qualities.DefaultResolution != "", "qualities.defaultResolution is required")
+	check(c.Qualities.DefaultAudio != "", "qualities.defaultAudio is required")
+
+	check(c.Limits.MaxPageSize > 0, "limits.maxPageSize must be positive, got %v", c.Limits.MaxPageSize)
+	check(c.Limits.DefaultPageSize > 0 && c.Limits.DefaultPageSize <= c.Limits.MaxPageSize,
+		"limits.defaultPageSize must be between 1 and limits.maxPageSize, got %v", c.Limits.DefaultPageSize)
+	return errors.Join(errs...)
+}
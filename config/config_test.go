@@ -0,0 +1,187 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const yamlConfig = `
+server:
+  port: 8080
+  shutdownTimeout: 1m
+redis:
+  host: redis.local
+  port: 6380
+auth:
+  adminToken: file_token
+log:
+  level: warn
+  packageLevels:
+    db: debug
+`
+
+const tomlConfig = `
+[server]
+port = 8080
+shutdownTimeout = "1m"
+
+[redis]
+host = "redis.local"
+port = 6380
+
+[auth]
+adminToken = "file_token"
+
+[log]
+level = "warn"
+packageLevels = { db = "debug" }
+`
+
+func writeConfigFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func load(args ...string) (Config, error) {
+	return Load(flag.NewFlagSet("test", flag.ContinueOnError), args)
+}
+
+// clearEnv unsets variables of the environment the tests run in, e.g. REDIS_ENDPOINT
+func clearEnv(t *testing.T) {
+	t.Setenv(FileEnv, "")
+	t.Setenv(legacyRedisEndpointEnv, "")
+	config := Default()
+	visitSettings(reflect.ValueOf(&config).Elem(), func(field reflect.Value, tag reflect.StructTag) {
+		if name := tag.Get("env"); name != "" {
+			t.Setenv(name, "")
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv("ADMIN_TOKEN", "env_token")
+
+		config, err := load()
+
+		assert.Nil(t, err)
+		expected := Default()
+		expected.Auth.AdminToken = "env_token"
+		assert.Equal(t, expected, config)
+		assert.Equal(t, "127.0.0.1:6379", config.Redis.Addr())
+	})
+
+	for _, file := range []struct{ name, content string }{{"config.yaml", yamlConfig}, {"config.toml", tomlConfig}} {
+		t.Run("File "+file.name, func(t *testing.T) {
+			clearEnv(t)
+
+			config, err := load("-config", writeConfigFile(t, file.name, file.content))
+
+			assert.Nil(t, err)
+			assert.Equal(t, 8080, config.Server.Port)
+			assert.Equal(t, Duration(time.Minute), config.Server.ShutdownTimeout)
+			assert.Equal(t, "redis.local:6380", config.Redis.Addr())
+			assert.Equal(t, "file_token", config.Auth.AdminToken)
+			assert.Equal(t, "warn", config.Log.Level)
+			assert.Equal(t, map[string]string{"db": "debug"}, config.Log.PackageLevels)
+			assert.Equal(t, "console", config.Log.Format, "should keep defaults of unset keys")
+		})
+	}
+
+	t.Run("Flags override environment which overrides file", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv(FileEnv, writeConfigFile(t, "config.yml", yamlConfig))
+		t.Setenv("REDIS_HOST", "env.redis.local")
+		t.Setenv("LOG_LEVEL", "error")
+		t.Setenv("LOG_PACKAGE_LEVELS", "webhooks=trace, db=info")
+
+		config, err := load("-log-level", "debug", "-port", "9090")
+
+		assert.Nil(t, err)
+		assert.Equal(t, 9090, config.Server.Port)
+		assert.Equal(t, "debug", config.Log.Level)
+		assert.Equal(t, "env.redis.local:6380", config.Redis.Addr())
+		assert.Equal(t, map[string]string{"webhooks": "trace", "db": "info"}, config.Log.PackageLevels)
+		assert.Equal(t, "file_token", config.Auth.AdminToken)
+	})
+
+	t.Run("Legacy REDIS_ENDPOINT", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv("ADMIN_TOKEN", "env_token")
+		t.Setenv(legacyRedisEndpointEnv, "10.0.0.1:6390")
+
+		config, err := load()
+
+		assert.Nil(t, err)
+		assert.Equal(t, "10.0.0.1:6390", config.Redis.Addr())
+	})
+
+	t.Run("Fail - all errors are reported", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv("REDIS_PORT", "redis")
+		t.Setenv("NOTIFIER", "webhook")
+		t.Setenv("SHUTDOWN_TIMEOUT", "soon")
+		t.Setenv("HLS_VIDEO_URL", "https://cdn.local/live/{quality}.m3u8")
+		t.Setenv("LIVE_ALLOWED_ORIGINS", "https://app.local, app.local/live")
+
+		_, err := load("-log-format", "xml", "-port", "70000")
+
+		assert.ErrorContains(t, err, "invalid REDIS_PORT `redis`: not an integer")
+		assert.ErrorContains(t, err, "invalid SHUTDOWN_TIMEOUT `soon`")
+		assert.ErrorContains(t, err, "server.port must be between 1 and 65535, got 70000")
+		assert.ErrorContains(t, err, "log.format must be one of [json console], got `xml`")
+		assert.ErrorContains(t, err, "auth.adminToken is required")
+		assert.ErrorContains(t, err, "notifier.webhookUrl is required by `webhook` notifier")
+		assert.ErrorContains(t, err, "streams.hlsVideoUrl must contain `{eventId}`, got `https://cdn.local/live/{quality}.m3u8`")
+		assert.ErrorContains(t, err, "auth.liveOrigins must contain `*` or origins like `https://host:port`, got `app.local/live`")
+		assert.NotContains(t, err.Error(), "`https://app.local`")
+	})
+
+	t.Run("Fail - invalid qualities and limits", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv("ADMIN_TOKEN", "env_token")
+		t.Setenv("DEFAULT_PAGE_SIZE", "2000")
+
+		_, err := load("-config", writeConfigFile(t, "config.yaml", "qualities:\n  defaultAudio: \"\"\n"))
+
+		assert.ErrorContains(t, err, "qualities.defaultAudio is required")
+		assert.ErrorContains(t, err, "limits.defaultPageSize must be between 1 and limits.maxPageSize, got 2000")
+	})
+
+	t.Run("Fail - unknown key in file", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv("ADMIN_TOKEN", "env_token")
+
+		_, err := load("-config", writeConfigFile(t, "config.yaml", "server:\n  hostname: x\n"))
+
+		assert.ErrorContains(t, err, "field hostname not found")
+	})
+
+	t.Run("Fail - unsupported file", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv("ADMIN_TOKEN", "env_token")
+
+		_, err := load("-config", writeConfigFile(t, "config.json", "{}"))
+
+		assert.ErrorContains(t, err, "must be .yaml, .yml or .toml")
+	})
+
+	t.Run("Fail - unknown flag", func(t *testing.T) {
+		clearEnv(t)
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.SetOutput(io.Discard)
+
+		_, err := Load(flags, []string{"-verbose"})
+
+		assert.ErrorContains(t, err, "flag provided but not defined: -verbose")
+	})
+}
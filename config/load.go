@@ -0,0 +1,169 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the configuration file when `-config` flag is not given
+const FileEnv = "CONFIG_FILE"
+
+// legacyRedisEndpointEnv is `host:port` of Redis, kept for deployments set up before REDIS_HOST and REDIS_PORT
+const legacyRedisEndpointEnv = "REDIS_ENDPOINT"
+
+// Load reads settings from these sources, each overriding the previous one:
+// defaults, YAML or TOML file (by extension) given by `-config` flag or CONFIG_FILE,
+// environment variables and command line flags.
+// Flags of all settings that have them are registered in `flags` before `args` are parsed,
+// so commands can add flags of their own. Errors of all sources and of validation are returned together.
+func Load(flags *flag.FlagSet, args []string) (Config, error) {
+	config := Default()
+	path := flags.String("config", "", "YAML or TOML configuration file, overrides "+FileEnv)
+	// flags are applied after the file and environment, so they are only collected while parsing
+	var flagSetters []func() error
+	visitSettings(reflect.ValueOf(&config).Elem(), func(field reflect.Value, tag reflect.StructTag) {
+		name := tag.Get("flag")
+		if name == "" {
+			return
+		}
+		usage := "sets " + tag.Get("env")
+		flags.Func(name, usage, func(value string) error {
+			flagSetters = append(flagSetters, func() error {
+				return setSetting(field, value, "flag -"+name)
+			})
+			return nil
+		})
+	})
+	if err := flags.Parse(args); err != nil {
+		return config, err
+	}
+
+	var errs []error
+	if *path == "" {
+		*path = os.Getenv(FileEnv)
+	}
+	if *path != "" {
+		config.file = *path
+		errs = append(errs, loadFile(*path, &config))
+	}
+	errs = append(errs, loadEnv(&config))
+	for _, setFlag := range flagSetters {
+		errs = append(errs, setFlag())
+	}
+	errs = append(errs, config.Validate())
+	return config, errors.Join(errs...)
+}
+
+func loadFile(path string, config *Config) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("cannot read configuration file: %w", err)
+	}
+	defer file.Close()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(file)
+		decoder.KnownFields(true)
+		err = decoder.Decode(config)
+	case ".toml":
+		decoder := toml.NewDecoder(file)
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(config)
+	default:
+		return fmt.Errorf("configuration file `%v` must be .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid configuration file `%v`: %w", path, err)
+	}
+	return nil
+}
+
+func loadEnv(config *Config) error {
+	var errs []error
+	if endpoint := os.Getenv(legacyRedisEndpointEnv); endpoint != "" {
+		host, port, found := strings.Cut(endpoint, ":")
+		config.Redis.Host = host
+		if found {
+			errs = append(errs, setSetting(reflect.ValueOf(&config.Redis.Port).Elem(), port, legacyRedisEndpointEnv))
+		}
+	}
+	visitSettings(reflect.ValueOf(config).Elem(), func(field reflect.Value, tag reflect.StructTag) {
+		name := tag.Get("env")
+		if value := os.Getenv(name); name != "" && value != "" {
+			errs = append(errs, setSetting(field, value, name))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// visitSettings calls `visit` with every setting in sections of `section`.
+func visitSettings(section reflect.Value, visit func(field reflect.Value, tag reflect.StructTag)) {
+	for i := 0; i < section.NumField(); i++ {
+		field := section.Field(i)
+		tag := section.Type().Field(i).Tag
+		if field.Kind() == reflect.Struct && tag.Get("env") == "" {
+			visitSettings(field, visit)
+			continue
+		}
+		visit(field, tag)
+	}
+}
+
+// setSetting parses text `value` from `source` into `field`,
+// lists are written as `a,b` and maps as `key=value,key=value`.
+func setSetting(field reflect.Value, value string, source string) error {
+	invalid := func(err error) error {
+		return fmt.Errorf("invalid %v `%v`: %v", source, value, err)
+	}
+	if unmarshaler, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		if err := unmarshaler.UnmarshalText([]byte(value)); err != nil {
+			return invalid(err)
+		}
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return invalid(errors.New("not an integer"))
+		}
+		field.SetInt(parsed)
+	case reflect.Uint32:
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return invalid(errors.New("not a non-negative integer"))
+		}
+		field.SetUint(parsed)
+	case reflect.Slice:
+		values := []string{}
+		for _, item := range strings.Split(value, ",") {
+			values = append(values, strings.TrimSpace(item))
+		}
+		field.Set(reflect.ValueOf(values))
+	case reflect.Map:
+		pairs := map[string]string{}
+		for _, pair := range strings.Split(value, ",") {
+			key, pairValue, found := strings.Cut(strings.TrimSpace(pair), "=")
+			if !found {
+				return invalid(fmt.Errorf("`%v` is not `key=value`", pair))
+			}
+			pairs[key] = pairValue
+		}
+		field.Set(reflect.ValueOf(pairs))
+	default:
+		return fmt.Errorf("%v cannot be set, %v settings are not supported", source, field.Kind())
+	}
+	return nil
+}
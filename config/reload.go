@@ -0,0 +1,86 @@
+package config
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// watchInterval is how often the configuration file is checked for changes
+var watchInterval = 2 * time.Second
+
+// Reloadable returns `next` settings that can change without restart on top of `c`.
+func (c Config) Reloadable(next Config) Config {
+	c.Qualities = next.Qualities
+	c.Limits = next.Limits
+	c.Log.Level = next.Log.Level
+	c.Log.PackageLevels = next.Log.PackageLevels
+	c.Log.SampleEvery = next.Log.SampleEvery
+	return c
+}
+
+// Watch loads configuration again with the same command line `args` whenever its file changes or SIGHUP arrives,
+// until `c` is done. Valid configuration is passed to `apply` with only reloadable settings changed,
+// invalid one is rejected and the current configuration is kept.
+func Watch(c context.Context, current Config, args []string, apply func(Config)) {
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	defer signal.Stop(hangups)
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	lastModified := modified(current.File())
+	for {
+		select {
+		case <-c.Done():
+			return
+		case <-hangups:
+			log.Logger.Info().Msg("SIGHUP received, reloading configuration")
+		case <-ticker.C:
+			fileModified := modified(current.File())
+			if fileModified == lastModified {
+				continue
+			}
+			lastModified = fileModified
+			log.Logger.Info().Msgf("configuration file `%v` changed, reloading configuration", current.File())
+		}
+		current = reload(current, args, apply)
+	}
+}
+
+// modified identifies version of the file, it is empty if there is no file to watch
+func modified(path string) string {
+	if path == "" {
+		return ""
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("%v/%v", info.ModTime().UnixNano(), info.Size())
+}
+
+func reload(current Config, args []string, apply func(Config)) Config {
+	flags := flag.NewFlagSet("reload", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	next, err := Load(flags, args)
+	if err != nil {
+		log.Logger.Error().Msgf("configuration reload rejected, keeping current configuration:\n%v", err)
+		return current
+	}
+	reloaded := current.Reloadable(next)
+	if !reflect.DeepEqual(reloaded, next) {
+		log.Logger.Warn().Msg("configuration changes other than qualities, limits and log levels apply after restart")
+	}
+	apply(reloaded)
+	log.Logger.Info().Msg("configuration reloaded")
+	return reloaded
+}
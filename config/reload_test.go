@@ -0,0 +1,124 @@
+package config
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const reloadConfig = `
+server:
+  port: 8080
+auth:
+  adminToken: file_token
+qualities:
+  defaultResolution: 720p
+  defaultAudio: Low
+`
+
+// startWatch watches configuration loaded from `path` and returns configurations passed to apply
+func startWatch(t *testing.T, path string) (Config, <-chan Config) {
+	originalInterval := watchInterval
+	watchInterval = 5 * time.Millisecond
+	current, err := load("-config", path)
+	assert.Nil(t, err)
+	applied := make(chan Config, 10)
+	c, stop := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		Watch(c, current, []string{"-config", path}, func(config Config) { applied <- config })
+		close(stopped)
+	}()
+	t.Cleanup(func() {
+		stop()
+		<-stopped
+		watchInterval = originalInterval
+	})
+	// lets the watch note modification time of the file before tests change it
+	time.Sleep(10 * watchInterval)
+	return current, applied
+}
+
+// rewrite changes file content, modification time is moved so that the change is seen on any file system
+func rewrite(t *testing.T, path string, content string, at time.Time) {
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0o600))
+	assert.Nil(t, os.Chtimes(path, at, at))
+}
+
+func expectApplied(t *testing.T, applied <-chan Config) Config {
+	select {
+	case config := <-applied:
+		return config
+	case <-time.After(time.Second):
+		assert.Fail(t, "configuration should be applied")
+		return Config{}
+	}
+}
+
+func expectNotApplied(t *testing.T, applied <-chan Config) {
+	select {
+	case <-applied:
+		assert.Fail(t, "configuration should not be applied")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWatch(t *testing.T) {
+	t.Run("File change is applied", func(t *testing.T) {
+		clearEnv(t)
+		path := writeConfigFile(t, "config.yaml", reloadConfig)
+		_, applied := startWatch(t, path)
+
+		rewrite(t, path, reloadConfig+"limits:\n  maxPageSize: 50\n  defaultPageSize: 10\nlog:\n  level: debug\n",
+			time.Now().Add(time.Minute))
+
+		config := expectApplied(t, applied)
+		assert.Equal(t, Limits{DefaultPageSize: 10, MaxPageSize: 50}, config.Limits)
+		assert.Equal(t, "debug", config.Log.Level)
+	})
+
+	t.Run("Only reloadable settings change", func(t *testing.T) {
+		clearEnv(t)
+		path := writeConfigFile(t, "config.yaml", reloadConfig)
+		current, applied := startWatch(t, path)
+
+		rewrite(t, path, reloadConfig+"redis:\n  host: redis.local\nlimits:\n  maxPageSize: 500\n",
+			time.Now().Add(time.Minute))
+
+		config := expectApplied(t, applied)
+		assert.Equal(t, int64(500), config.Limits.MaxPageSize)
+		assert.Equal(t, current.Redis, config.Redis, "should keep settings applied at startup")
+	})
+
+	t.Run("Fail - invalid configuration is rejected", func(t *testing.T) {
+		clearEnv(t)
+		path := writeConfigFile(t, "config.yaml", reloadConfig)
+		_, applied := startWatch(t, path)
+
+		rewrite(t, path, reloadConfig+"limits:\n  maxPageSize: 0\n", time.Now().Add(time.Minute))
+		expectNotApplied(t, applied)
+		rewrite(t, path, "qualities: [broken", time.Now().Add(2*time.Minute))
+		expectNotApplied(t, applied)
+
+		rewrite(t, path, reloadConfig+"limits:\n  maxPageSize: 200\n", time.Now().Add(3*time.Minute))
+		assert.Equal(t, int64(200), expectApplied(t, applied).Limits.MaxPageSize, "should apply once it is fixed")
+	})
+
+	t.Run("SIGHUP reloads configuration", func(t *testing.T) {
+		clearEnv(t)
+		path := writeConfigFile(t, "config.yaml", reloadConfig)
+		_, applied := startWatch(t, path)
+		// file change proves the watch runs, so SIGHUP is not handled by the default handler
+		rewrite(t, path, reloadConfig, time.Now().Add(time.Minute))
+		expectApplied(t, applied)
+
+		t.Setenv("DEFAULT_AUDIO", "High")
+		assert.Nil(t, syscall.Kill(syscall.Getpid(), syscall.SIGHUP))
+
+		assert.Equal(t, "High", expectApplied(t, applied).Qualities.DefaultAudio)
+	})
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	lg "app/logging"
+	"app/models"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// audit trail is append-only, unlike the event log it is never trimmed and nothing is ever replayed from it
+const auditStreamKey = "audit:log"
+
+func auditHash(document string) string {
+	if document == "" {
+		return ""
+	}
+	hash := sha256.Sum256([]byte(document))
+	return hex.EncodeToString(hash[:])
+}
+
+func auditArgs(c context.Context, action string, targetId string, before string, after string) *redis.XAddArgs {
+	return &redis.XAddArgs{
+		Stream: auditStreamKey,
+		Values: map[string]interface{}{
+			"occurredAt":    time.Now().UTC().Format(time.RFC3339Nano),
+			"actor":         lg.Actor(c),
+			"action":        action,
+			"targetId":      targetId,
+			"beforeHash":    auditHash(before),
+			"afterHash":     auditHash(after),
+			"clientIp":      lg.ClientIp(c),
+			"correlationId": lg.CorrelationId(c),
+		},
+	}
+}
+
+// appendAudit records mutation in the transaction that makes it.
+func appendAudit(c context.Context, pipe redis.Pipeliner, action string, targetId string, before string, after string) {
+	pipe.XAdd(c, auditArgs(c, action, targetId, before, after))
+}
+
+// AppendAudit records action of the request `c` belongs to, `before` and `after` are documents it changed.
+var AppendAudit = func(c context.Context, action string, targetId string, before string, after string) error {
+	return redisError("append audit", targetId, redisClient.XAdd(c, auditArgs(c, action, targetId, before, after)).Err())
+}
+
+// GetAuditEntries returns at most `limit` entries between ids `start` and `end` (both inclusive, "-" and "+" for open ends).
+var GetAuditEntries = func(c context.Context, start string, end string, limit int64) ([]models.AuditEntry, error) {
+	messages, err := redisClient.XRangeN(c, auditStreamKey, start, end, limit).Result()
+	if err != nil {
+		return nil, redisError("get audit entries", auditStreamKey, err)
+	}
+	entries := []models.AuditEntry{}
+	for _, message := range messages {
+		entry := models.AuditEntry{Id: message.ID}
+		occurredAt, _ := message.Values["occurredAt"].(string)
+		entry.OccurredAt, _ = time.Parse(time.RFC3339Nano, occurredAt)
+		entry.Actor, _ = message.Values["actor"].(string)
+		entry.Action, _ = message.Values["action"].(string)
+		entry.TargetId, _ = message.Values["targetId"].(string)
+		entry.BeforeHash, _ = message.Values["beforeHash"].(string)
+		entry.AfterHash, _ = message.Values["afterHash"].(string)
+		entry.ClientIp, _ = message.Values["clientIp"].(string)
+		entry.CorrelationId, _ = message.Values["correlationId"].(string)
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	lg "app/logging"
+	"app/models"
+	"app/utils"
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditTrail(t *testing.T) {
+	utils.GetJsonStringFromStruct = originalGetJsonStringFromStruct
+
+	t.Run("Event mutations are audited with hashes of documents", func(t *testing.T) {
+		setup()
+		defer teardown()
+		requestContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+		requestContext.Request = httptest.NewRequest("DELETE", "/event", nil)
+		requestContext.Request.RemoteAddr = "192.0.2.10:51234"
+		lg.Middleware()(requestContext)
+		lg.SetActor(requestContext, "admin")
+
+		id, err := CreateEvent(requestContext, eventDataAsStruct)
+		assert.Nil(t, err)
+		created, _ := redisClient.Get(ctx, id).Result()
+		updated := eventDataAsStruct
+		updated.Name = "updated-name"
+		assert.Nil(t, UpdateEvent(requestContext, id, updated))
+		stored, _ := redisClient.Get(ctx, id).Result()
+		assert.Nil(t, DeleteEvent(requestContext, id))
+
+		entries, err := GetAuditEntries(ctx, "-", "+", 10)
+		assert.Nil(t, err)
+		assert.Len(t, entries, 3)
+		assert.Equal(t, []string{models.AuditEventCreate, models.AuditEventUpdate, models.AuditEventDelete},
+			[]string{entries[0].Action, entries[1].Action, entries[2].Action})
+		assert.Equal(t, "", entries[0].BeforeHash)
+		assert.Equal(t, auditHash(created), entries[0].AfterHash)
+		assert.Equal(t, auditHash(created), entries[1].BeforeHash)
+		assert.Equal(t, auditHash(stored), entries[1].AfterHash)
+		assert.Equal(t, auditHash(stored), entries[2].BeforeHash)
+		assert.Equal(t, "", entries[2].AfterHash)
+		for _, entry := range entries {
+			assert.Equal(t, id, entry.TargetId)
+			assert.Equal(t, "admin", entry.Actor)
+			assert.Equal(t, "192.0.2.10", entry.ClientIp)
+			assert.Equal(t, lg.CorrelationId(requestContext), entry.CorrelationId)
+			assert.WithinDuration(t, time.Now(), entry.OccurredAt, time.Minute)
+		}
+	})
+
+	t.Run("Failed mutation is not audited", func(t *testing.T) {
+		setup()
+		defer teardown()
+
+		assert.ErrorIs(t, UpdateEvent(ctx, "missing-id", eventDataAsStruct), ErrNotFound)
+		assert.ErrorIs(t, DeleteEvent(ctx, "missing-id"), ErrNotFound)
+
+		entries, err := GetAuditEntries(ctx, "-", "+", 10)
+		assert.Nil(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("Actions outside of transactions and time range", func(t *testing.T) {
+		setup()
+		defer teardown()
+		subscription, _ := json.Marshal(models.WebhookSubscription{Id: "subscription-id"})
+
+		assert.Nil(t, AppendAudit(ctx, models.AuditWebhookCreate, "subscription-id", "", string(subscription)))
+		entries, err := GetAuditEntries(ctx, "-", "+", 10)
+		assert.Nil(t, err)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, lg.ActorSystem, entries[0].Actor)
+		assert.Equal(t, auditHash(string(subscription)), entries[0].AfterHash)
+
+		future := strconv.FormatInt(time.Now().Add(time.Hour).UnixMilli(), 10)
+		entries, err = GetAuditEntries(ctx, future, "+", 10)
+		assert.Nil(t, err)
+		assert.Empty(t, entries)
+	})
+}
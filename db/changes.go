@@ -0,0 +1,230 @@
+package db
+
+import (
+	lg "app/logging"
+	"app/models"
+	"app/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog/log"
+)
+
+// every event mutation appends a domain event to the event log stream in the same transaction,
+// stream entry ids are used as SSE ids so that clients can resume with `Last-Event-ID`
+
+const changesStreamKey = "events:changes"
+
+// changesStreamMaxLen approximately caps the log, 0 keeps whole history which replay relies on
+var changesStreamMaxLen int64
+
+const replayBatchSize = 500
+
+func appendChange(c context.Context, pipe redis.Pipeliner, eventType string, event models.EventResponseData) error {
+	dataAsJsonString, err := json.Marshal(event)
+	if err != nil {
+		lg.WithContext(c).Error().Msgf("error converting change to json: %v", err)
+		return newError(ErrInvalid, "append change", event.Id, err)
+	}
+	pipe.XAdd(c, &redis.XAddArgs{
+		Stream: changesStreamKey,
+		MaxLen: changesStreamMaxLen,
+		Approx: changesStreamMaxLen > 0,
+		Values: map[string]interface{}{
+			"type":          eventType,
+			"occurredAt":    time.Now().UTC().Format(time.RFC3339Nano),
+			"actor":         lg.Actor(c),
+			"correlationId": lg.CorrelationId(c),
+			"event":         dataAsJsonString,
+		},
+	})
+	return nil
+}
+
+func parseChanges(messages []redis.XMessage) []models.DomainEvent {
+	changes := []models.DomainEvent{}
+	for _, message := range messages {
+		change := models.DomainEvent{Id: message.ID}
+		change.Type, _ = message.Values["type"].(string)
+		change.Actor, _ = message.Values["actor"].(string)
+		change.CorrelationId, _ = message.Values["correlationId"].(string)
+		occurredAt, _ := message.Values["occurredAt"].(string)
+		change.OccurredAt, _ = time.Parse(time.RFC3339Nano, occurredAt)
+		eventJson, _ := message.Values["event"].(string)
+		if err := json.Unmarshal([]byte(eventJson), &change.Event); err != nil {
+			log.Logger.Error().Msgf("error parsing change `%v`: %v", message.ID, err)
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func parseChangeId(id string) (uint64, uint64, error) {
+	parts := strings.SplitN(id, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid change id `%v`", id)
+	}
+	ms, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid change id `%v`", id)
+	}
+	seq, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid change id `%v`", id)
+	}
+	return ms, seq, nil
+}
+
+// NextChangeId returns the smallest id greater than `id`, used to read ranges exclusive of `id`.
+func NextChangeId(id string) (string, error) {
+	ms, seq, err := parseChangeId(id)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v-%v", ms, seq+1), nil
+}
+
+// IsChangeAfter reports whether change `id` was appended after change `lastId`, invalid ids are never after.
+func IsChangeAfter(id string, lastId string) bool {
+	ms, seq, err := parseChangeId(id)
+	if err != nil {
+		return false
+	}
+	lastMs, lastSeq, err := parseChangeId(lastId)
+	if err != nil {
+		return false
+	}
+	return ms > lastMs || ms == lastMs && seq > lastSeq
+}
+
+// GetLatestChangeId returns id of the newest change, "0-0" if there is none.
+var GetLatestChangeId = func(c context.Context) (string, error) {
+	messages, err := redisClient.XRevRangeN(c, changesStreamKey, "+", "-", 1).Result()
+	if err != nil {
+		return "", redisError("get latest change id", changesStreamKey, err)
+	}
+	if len(messages) == 0 {
+		return "0-0", nil
+	}
+	return messages[0].ID, nil
+}
+
+// ReadChanges returns changes after `lastId`, waiting up to `block` for new ones.
+var ReadChanges = func(c context.Context, lastId string, block time.Duration) ([]models.DomainEvent, error) {
+	streams, err := redisClient.XRead(c, &redis.XReadArgs{
+		Streams: []string{changesStreamKey, lastId},
+		Count:   100,
+		Block:   block,
+	}).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return []models.DomainEvent{}, nil
+		}
+		return nil, redisError("read changes", lastId, err)
+	}
+	changes := []models.DomainEvent{}
+	for _, stream := range streams {
+		changes = append(changes, parseChanges(stream.Messages)...)
+	}
+	return changes, nil
+}
+
+// GetChanges returns at most `limit` changes starting at id `start` (inclusive, "-" for the oldest).
+var GetChanges = func(c context.Context, start string, limit int64) ([]models.DomainEvent, error) {
+	messages, err := redisClient.XRangeN(c, changesStreamKey, start, "+", limit).Result()
+	if err != nil {
+		return nil, redisError("get changes", start, err)
+	}
+	return parseChanges(messages), nil
+}
+
+// CreateChangesGroup creates consumer group reading changes after `lastId` ("$" for new changes only),
+// existing group is left as is.
+var CreateChangesGroup = func(c context.Context, group string, lastId string) error {
+	err := redisError("create changes group", group, redisClient.XGroupCreateMkStream(c, changesStreamKey, group, lastId).Err())
+	if errors.Is(err, ErrConflict) {
+		return nil
+	}
+	return err
+}
+
+// ReadChangesGroup returns changes delivered to `consumer` but not acknowledged yet,
+// new changes of the group are read only when there are none, so each change is processed at least once.
+var ReadChangesGroup = func(c context.Context, group string, consumer string, count int64, block time.Duration) ([]models.DomainEvent, error) {
+	for _, start := range []string{"0", ">"} {
+		args := &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: consumer,
+			Streams:  []string{changesStreamKey, start},
+			Count:    count,
+			Block:    -1,
+		}
+		if start == ">" {
+			args.Block = block
+		}
+		streams, err := redisClient.XReadGroup(c, args).Result()
+		if err != nil {
+			if err == redis.Nil {
+				return []models.DomainEvent{}, nil
+			}
+			return nil, redisError("read changes group", group, err)
+		}
+		changes := []models.DomainEvent{}
+		for _, stream := range streams {
+			changes = append(changes, parseChanges(stream.Messages)...)
+		}
+		if len(changes) > 0 {
+			return changes, nil
+		}
+	}
+	return []models.DomainEvent{}, nil
+}
+
+var AckChanges = func(c context.Context, group string, ids ...string) error {
+	return redisError("ack changes", group, redisClient.XAck(c, changesStreamKey, group, ids...).Err())
+}
+
+// ReplayChanges rebuilds event documents from changes after `lastId` without notifying anyone,
+// events missing in the log are left untouched. Returns number of replayed changes.
+var ReplayChanges = func(c context.Context, lastId string) (int, error) {
+	replayed := 0
+	for {
+		start, err := NextChangeId(lastId)
+		if err != nil {
+			return replayed, newError(ErrInvalid, "replay changes", lastId, err)
+		}
+		changes, err := GetChanges(c, start, replayBatchSize)
+		if err != nil {
+			return replayed, redisError("replay changes", start, err)
+		}
+		if len(changes) == 0 {
+			return replayed, nil
+		}
+		_, err = redisClient.TxPipelined(c, func(pipe redis.Pipeliner) error {
+			for _, change := range changes {
+				if change.Type == models.DomainEventDeleted {
+					pipe.Del(c, change.Event.Id)
+					continue
+				}
+				dataAsJsonString, err := utils.GetJsonStringFromStruct(change.Event.EventData)
+				if err != nil {
+					return newError(ErrInvalid, "replay changes", change.Id, err)
+				}
+				pipe.Set(c, change.Event.Id, dataAsJsonString, 0)
+			}
+			return nil
+		})
+		if err != nil {
+			return replayed, redisError("replay changes", start, err)
+		}
+		replayed += len(changes)
+		lastId = changes[len(changes)-1].Id
+	}
+}
@@ -0,0 +1,165 @@
+package db
+
+import (
+	lg "app/logging"
+	"app/models"
+	"app/utils"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeFeed(t *testing.T) {
+	utils.GetJsonStringFromStruct = originalGetJsonStringFromStruct
+
+	t.Run("Empty feed", func(t *testing.T) {
+		setup()
+		defer teardown()
+
+		latestId, err := GetLatestChangeId(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, "0-0", latestId)
+
+		changes, err := ReadChanges(ctx, latestId, 10*time.Millisecond)
+		assert.Nil(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("Event mutations are appended to the feed in order", func(t *testing.T) {
+		setup()
+		defer teardown()
+
+		id, err := CreateEvent(ctx, eventDataAsStruct)
+		assert.Nil(t, err)
+		assert.Nil(t, UpdateEvent(ctx, id, eventDataAsStruct))
+		assert.Nil(t, DeleteEvent(ctx, id))
+
+		changes, err := ReadChanges(ctx, "0-0", 10*time.Millisecond)
+		assert.Nil(t, err)
+		assert.Len(t, changes, 3)
+		assert.Equal(t, models.DomainEventCreated, changes[0].Type)
+		assert.Equal(t, models.DomainEventUpdated, changes[1].Type)
+		assert.Equal(t, models.DomainEventDeleted, changes[2].Type)
+		for _, change := range changes {
+			assert.Equal(t, id, change.Event.Id)
+			assert.Equal(t, eventDataAsStruct.Name, change.Event.Name)
+			assert.Equal(t, lg.ActorSystem, change.Actor)
+			assert.WithinDuration(t, time.Now(), change.OccurredAt, time.Minute)
+		}
+
+		latestId, err := GetLatestChangeId(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, changes[2].Id, latestId)
+
+		resumed, err := ReadChanges(ctx, changes[0].Id, 10*time.Millisecond)
+		assert.Nil(t, err)
+		assert.Equal(t, changes[1:], resumed)
+	})
+
+	t.Run("Change records actor and correlation id of the request", func(t *testing.T) {
+		setup()
+		defer teardown()
+		requestContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+		requestContext.Request = httptest.NewRequest("POST", "/event", nil)
+		lg.Middleware()(requestContext)
+		lg.SetActor(requestContext, "admin")
+
+		_, err := CreateEvent(requestContext, eventDataAsStruct)
+		assert.Nil(t, err)
+
+		changes, err := GetChanges(ctx, "-", 10)
+		assert.Nil(t, err)
+		assert.Len(t, changes, 1)
+		assert.Equal(t, "admin", changes[0].Actor)
+		assert.NotEmpty(t, changes[0].CorrelationId)
+		assert.Equal(t, lg.CorrelationId(requestContext), changes[0].CorrelationId)
+	})
+}
+
+func TestGetChanges(t *testing.T) {
+	utils.GetJsonStringFromStruct = originalGetJsonStringFromStruct
+	setup()
+	defer teardown()
+	for i := 0; i < 3; i++ {
+		_, err := CreateEvent(ctx, eventDataAsStruct)
+		assert.Nil(t, err)
+	}
+	all, err := GetChanges(ctx, "-", 10)
+	assert.Nil(t, err)
+	assert.Len(t, all, 3)
+
+	t.Run("Page after change id", func(t *testing.T) {
+		start, err := NextChangeId(all[0].Id)
+		assert.Nil(t, err)
+		changes, err := GetChanges(ctx, start, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, all[1:2], changes)
+	})
+
+	t.Run("Invalid change id", func(t *testing.T) {
+		_, err := NextChangeId("not-an-id")
+		assert.NotNil(t, err)
+	})
+}
+
+func TestIsChangeAfter(t *testing.T) {
+	assert.True(t, IsChangeAfter("1682000000000-1", "1682000000000-0"))
+	assert.True(t, IsChangeAfter("1682000000001-0", "1682000000000-9"))
+	assert.False(t, IsChangeAfter("1682000000000-0", "1682000000000-0"))
+	assert.False(t, IsChangeAfter("1681999999999-0", "1682000000000-0"))
+	assert.False(t, IsChangeAfter("not-an-id", "1682000000000-0"))
+}
+
+func TestChangesGroup(t *testing.T) {
+	utils.GetJsonStringFromStruct = originalGetJsonStringFromStruct
+	setup()
+	defer teardown()
+	assert.Nil(t, CreateChangesGroup(ctx, "search-index", "0"))
+	assert.Nil(t, CreateChangesGroup(ctx, "search-index", "0"), "existing group is kept")
+	id, err := CreateEvent(ctx, eventDataAsStruct)
+	assert.Nil(t, err)
+
+	changes, err := ReadChangesGroup(ctx, "search-index", "consumer-1", 10, 10*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, id, changes[0].Event.Id)
+
+	redelivered, err := ReadChangesGroup(ctx, "search-index", "consumer-1", 10, 10*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Equal(t, changes, redelivered, "unacknowledged change is delivered again")
+
+	assert.Nil(t, AckChanges(ctx, "search-index", changes[0].Id))
+	changes, err = ReadChangesGroup(ctx, "search-index", "consumer-1", 10, 10*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestReplayChanges(t *testing.T) {
+	utils.GetJsonStringFromStruct = originalGetJsonStringFromStruct
+	setup()
+	defer teardown()
+	keptId, err := CreateEvent(ctx, eventDataAsStruct)
+	assert.Nil(t, err)
+	updated := eventDataAsStruct
+	updated.Name = "updated-name"
+	assert.Nil(t, UpdateEvent(ctx, keptId, updated))
+	deletedId, err := CreateEvent(ctx, eventDataAsStruct)
+	assert.Nil(t, err)
+	assert.Nil(t, DeleteEvent(ctx, deletedId))
+	// simulates lost or corrupted documents
+	assert.Nil(t, redisClient.Del(ctx, keptId).Err())
+	assert.Nil(t, redisClient.Set(ctx, deletedId, "{}", 0).Err())
+
+	replayed, err := ReplayChanges(ctx, "0-0")
+	assert.Nil(t, err)
+	assert.Equal(t, 4, replayed)
+
+	event, err := GetEvent(ctx, keptId)
+	assert.Nil(t, err)
+	assert.Equal(t, "updated-name", event.Name)
+	_, err = GetEvent(ctx, deletedId)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Kinds of storage errors, errors returned by the storage carry operation and key they happened on,
+// so they have to be checked with errors.Is, e.g. `errors.Is(err, db.ErrNotFound)`.
+var (
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned when stored data already exists or was changed concurrently
+	ErrConflict    = errors.New("conflict")
+	ErrUnavailable = errors.New("storage unavailable")
+	// ErrInvalid is returned when stored data cannot be read
+	ErrInvalid = errors.New("invalid data")
+)
+
+// Error is failure of operation `Op` on `Key`, `Kind` is one of the errors above
+// and `Err` is the underlying error, e.g. of redis, if any.
+type Error struct {
+	Kind error
+	Op   string
+	Key  string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	message := fmt.Sprintf("%v %v: %v", e.Op, e.Key, e.Kind)
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+	return message
+}
+
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func newError(kind error, op string, key string, err error) error {
+	return &Error{Kind: kind, Op: op, Key: key, Err: err}
+}
+
+// redisError returns error `err` of redis command of operation `op` on `key` as storage error,
+// errors already returned as storage errors, e.g. from transaction functions, are kept as they are.
+func redisError(op string, key string, err error) error {
+	var storageErr *Error
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &storageErr):
+		return err
+	case errors.Is(err, redis.Nil):
+		return newError(ErrNotFound, op, key, nil)
+	case errors.Is(err, redis.TxFailedErr), isBusyGroup(err):
+		return newError(ErrConflict, op, key, err)
+	default:
+		return newError(ErrUnavailable, op, key, err)
+	}
+}
+
+// isBusyGroup reports whether `err` is the reply of redis to creating a consumer group that already exists,
+// redis replies with an error string only, so it is the single place matching it.
+func isBusyGroup(err error) bool {
+	var replyErr redis.Error
+	return errors.As(err, &replyErr) && strings.HasPrefix(replyErr.Error(), "BUSYGROUP")
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"app/models"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+// replyError is error reply of redis server
+type replyError string
+
+func (e replyError) Error() string { return string(e) }
+
+func (replyError) RedisError() {}
+
+func TestRedisError(t *testing.T) {
+	connectionErr := errors.New("dial tcp: connection refused")
+	busyGroupErr := replyError("BUSYGROUP Consumer Group name already exists")
+	testCases := []struct {
+		description   string
+		err           error
+		expectedKind  error
+		expectedCause error
+	}{
+		{description: "Missing key", err: redis.Nil, expectedKind: ErrNotFound},
+		{description: "Watched key changed", err: redis.TxFailedErr, expectedKind: ErrConflict, expectedCause: redis.TxFailedErr},
+		{description: "Existing consumer group", err: busyGroupErr, expectedKind: ErrConflict, expectedCause: busyGroupErr},
+		{description: "Connection failure", err: connectionErr, expectedKind: ErrUnavailable, expectedCause: connectionErr},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := redisError("get event", "event-id", testCase.err)
+
+			assert.ErrorIs(t, err, testCase.expectedKind)
+			var storageErr *Error
+			assert.True(t, errors.As(err, &storageErr))
+			assert.Equal(t, "get event", storageErr.Op)
+			assert.Equal(t, "event-id", storageErr.Key)
+			assert.Equal(t, testCase.expectedCause, errors.Unwrap(err))
+		})
+	}
+
+	t.Run("No error", func(t *testing.T) {
+		assert.Nil(t, redisError("get event", "event-id", nil))
+	})
+
+	t.Run("Storage errors are kept", func(t *testing.T) {
+		err := fmt.Errorf("watch: %w", newError(ErrNotFound, "update event", "event-id", nil))
+		assert.Equal(t, err, redisError("save event", "other-id", err))
+	})
+}
+
+func TestErrorMessage(t *testing.T) {
+	assert.EqualError(t, newError(ErrNotFound, "get event", "event-id", nil), "get event event-id: not found")
+	assert.EqualError(t, newError(ErrUnavailable, "get event", "event-id", errors.New("i/o timeout")),
+		"get event event-id: storage unavailable: i/o timeout")
+	assert.False(t, errors.Is(newError(ErrNotFound, "get event", "event-id", nil), ErrConflict))
+}
+
+func TestStorageFailuresAreTyped(t *testing.T) {
+	setup()
+	defer teardown()
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	now := time.Now().UTC()
+
+	for description, call := range map[string]func() error{
+		"touch presence": func() error { return TouchPresence(cancelled, "event-id", "member", now, time.Minute) },
+		"get audit entries": func() error {
+			_, err := GetAuditEntries(cancelled, "-", "+", 10)
+			return err
+		},
+		"get changes": func() error {
+			_, err := GetChanges(cancelled, "-", 10)
+			return err
+		},
+		"create changes group": func() error { return CreateChangesGroup(cancelled, "group", "$") },
+		"replay changes": func() error {
+			_, err := ReplayChanges(cancelled, "0-0")
+			return err
+		},
+		"seed quality profiles": func() error { return SeedQualityProfiles(cancelled, qualityProfilesAsStruct) },
+		"set tenant qualities": func() error {
+			return SetTenantQualities(cancelled, "tenant-1", models.TenantQualities{})
+		},
+		"ack notification": func() error { return AckNotification(cancelled, "notification-id") },
+		"save webhook delivery": func() error {
+			return SaveWebhookDelivery(cancelled, models.WebhookDelivery{Id: "delivery-id"}, now)
+		},
+	} {
+		t.Run(description, func(t *testing.T) {
+			err := call()
+
+			assert.ErrorIs(t, err, ErrUnavailable)
+			assert.ErrorIs(t, err, context.Canceled)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"app/metrics"
+	"app/tracing"
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type startTimeKey struct{}
+
+// instrumentationHook measures and traces every command sent through redisClient,
+// transactions and pipelines are measured as a whole
+type instrumentationHook struct{}
+
+func (instrumentationHook) BeforeProcess(c context.Context, cmd redis.Cmder) (context.Context, error) {
+	return startCommand(c, cmd.Name(), 1), nil
+}
+
+func (instrumentationHook) AfterProcess(c context.Context, cmd redis.Cmder) error {
+	endCommand(c, cmd.Name(), []redis.Cmder{cmd})
+	return nil
+}
+
+func (instrumentationHook) BeforeProcessPipeline(c context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return startCommand(c, pipelineName(cmds), len(cmds)), nil
+}
+
+func (instrumentationHook) AfterProcessPipeline(c context.Context, cmds []redis.Cmder) error {
+	endCommand(c, pipelineName(cmds), cmds)
+	return nil
+}
+
+func pipelineName(cmds []redis.Cmder) string {
+	if len(cmds) > 0 && cmds[0].Name() == "multi" {
+		return "multi"
+	}
+	return "pipeline"
+}
+
+func startCommand(c context.Context, name string, length int) context.Context {
+	c, _ = tracing.Tracer().Start(c, "redis "+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemRedis,
+			semconv.DBOperation(name),
+			attribute.Int("db.redis.num_cmd", length),
+		))
+	return context.WithValue(c, startTimeKey{}, time.Now())
+}
+
+func endCommand(c context.Context, name string, cmds []redis.Cmder) {
+	if startTime, found := c.Value(startTimeKey{}).(time.Time); found {
+		metrics.RedisCommandDuration.WithLabelValues(name).Observe(time.Since(startTime).Seconds())
+	}
+	span := trace.SpanFromContext(c)
+	for _, cmd := range cmds {
+		if err := cmd.Err(); err != nil && err != redis.Nil {
+			metrics.RedisCommandErrors.WithLabelValues(cmd.Name()).Inc()
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End()
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"app/metrics"
+	"app/tracing"
+	"app/utils"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	testFuncs "app/testing"
+)
+
+func TestMetricsHook(t *testing.T) {
+	utils.GetJsonStringFromStruct = originalGetJsonStringFromStruct
+	setup()
+	defer teardown()
+
+	t.Run("Commands are measured, missing keys are not errors", func(t *testing.T) {
+		errorsBefore := testutil.ToFloat64(metrics.RedisCommandErrors.WithLabelValues("get"))
+		_, err := GetEvent(ctx, "non-existent-id")
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Contains(t, histogramCommands(t), "get")
+		assert.Equal(t, errorsBefore, testutil.ToFloat64(metrics.RedisCommandErrors.WithLabelValues("get")))
+	})
+
+	t.Run("Failed commands are counted", func(t *testing.T) {
+		assert.Nil(t, redisClient.Set(ctx, "not-a-number", "text", 0).Err())
+		before := testutil.ToFloat64(metrics.RedisCommandErrors.WithLabelValues("incr"))
+		assert.NotNil(t, redisClient.Incr(ctx, "not-a-number").Err())
+		assert.Equal(t, before+1, testutil.ToFloat64(metrics.RedisCommandErrors.WithLabelValues("incr")))
+	})
+
+	t.Run("Commands are traced as children of caller's span", func(t *testing.T) {
+		spans := testFuncs.UseInMemoryExporter()
+		c, parent := tracing.Tracer().Start(ctx, "GET /event/:id")
+		_, _ = GetEvent(c, "non-existent-id")
+		parent.End()
+
+		assert.Len(t, spans.GetSpans(), 2)
+		commandSpan := spans.GetSpans()[0]
+		assert.Equal(t, "redis get", commandSpan.Name)
+		assert.Equal(t, parent.SpanContext().SpanID(), commandSpan.Parent.SpanID())
+	})
+
+	t.Run("Transactions are measured as a whole", func(t *testing.T) {
+		_, err := CreateEvent(ctx, eventDataAsStruct)
+		assert.Nil(t, err)
+		assert.Contains(t, histogramCommands(t), "multi")
+	})
+}
+
+// histogramCommands returns `command` labels observed by RedisCommandDuration
+func histogramCommands(t *testing.T) []string {
+	families, err := metrics.Registry.Gather()
+	assert.Nil(t, err)
+	commands := []string{}
+	for _, family := range families {
+		if family.GetName() != "event_handler_redis_command_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				commands = append(commands, label.GetValue())
+			}
+		}
+	}
+	return commands
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"app/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog/log"
+)
+
+// delayed jobs are kept in a sorted set scored by their due time (unix ms),
+// job documents are stored separately so that their status outlives the queue entry
+
+const jobQueueKey = "scheduler:queue"
+const jobKeyPrefix = "scheduler:job:"
+const jobLeaseKeyPrefix = "scheduler:lease:"
+
+var ScheduleJob = func(c context.Context, job models.Job) error {
+	dataAsJsonString, convertErr := json.Marshal(job)
+	if convertErr != nil {
+		log.Logger.Error().Msgf("error converting job to json: %v", convertErr)
+		return newError(ErrInvalid, "save job", job.Id, convertErr)
+	}
+	_, err := redisClient.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		pipe.Set(c, jobKeyPrefix+job.Id, dataAsJsonString, 0)
+		jobQueue.add(c, pipe, job.Id, job.RunAt)
+		return nil
+	})
+	if err != nil {
+		log.Logger.Error().Msgf("error on scheduling job to redis: %v", err)
+		return redisError("schedule job", job.Id, err)
+	}
+	return nil
+}
+
+var FinishJob = func(c context.Context, job models.Job) error {
+	dataAsJsonString, convertErr := json.Marshal(job)
+	if convertErr != nil {
+		log.Logger.Error().Msgf("error converting job to json: %v", convertErr)
+		return newError(ErrInvalid, "save job", job.Id, convertErr)
+	}
+	_, err := redisClient.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		pipe.Set(c, jobKeyPrefix+job.Id, dataAsJsonString, 0)
+		jobQueue.remove(c, pipe, job.Id)
+		return nil
+	})
+	if err != nil {
+		log.Logger.Error().Msgf("error on finishing job in redis: %v", err)
+		return redisError("finish job", job.Id, err)
+	}
+	return nil
+}
+
+var GetJob = func(c context.Context, id string) (models.Job, error) {
+	result, err := redisClient.Get(c, jobKeyPrefix+id).Result()
+	if err != nil {
+		return models.Job{}, redisError("get job", id, err)
+	}
+	var job models.Job
+	jsonParseErr := json.Unmarshal([]byte(result), &job)
+	if jsonParseErr != nil {
+		return models.Job{}, newError(ErrInvalid, "get job", id, jsonParseErr)
+	}
+	return job, nil
+}
+
+// GetDueJobIds returns ids of queued jobs due at `now`, oldest first.
+var GetDueJobIds = func(c context.Context, now time.Time, limit int64) ([]string, error) {
+	return jobQueue.due(c, now, limit)
+}
+
+var GetQueuedJobs = func(c context.Context) ([]models.Job, error) {
+	ids, err := redisClient.ZRange(c, jobQueueKey, 0, -1).Result()
+	if err != nil {
+		return nil, redisError("get queued jobs", jobQueueKey, err)
+	}
+	jobs := []models.Job{}
+	for _, id := range ids {
+		job, err := GetJob(c, id)
+		if err != nil {
+			log.Logger.Error().Msg(fmt.Sprintf("error reading queued job `%v`: %v", id, err))
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// AcquireJobLease makes sure only one replica runs the job,
+// lease expires after `ttl` so that jobs of crashed replicas are picked up again.
+var AcquireJobLease = func(c context.Context, id string, owner string, ttl time.Duration) (bool, error) {
+	return jobQueue.acquire(c, id, owner, ttl)
+}
+
+var ReleaseJobLease = func(c context.Context, id string, owner string) error {
+	return jobQueue.release(c, id, owner)
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"app/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var jobRunAt = time.Date(2023, 4, 20, 13, 45, 0, 0, time.UTC)
+
+var jobAsStruct = models.Job{
+	Id:      "reminder-event-id-string",
+	Type:    "reminder",
+	EventId: "event-id-string",
+	RunAt:   jobRunAt,
+	Status:  "pending",
+}
+
+func TestScheduleJob(t *testing.T) {
+	t.Run("Scheduled job is stored and due at its time", func(t *testing.T) {
+		setup()
+		defer teardown()
+
+		err := ScheduleJob(ctx, jobAsStruct)
+		assert.Nil(t, err)
+
+		job, err := GetJob(ctx, jobAsStruct.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, jobAsStruct, job)
+
+		dueIds, err := GetDueJobIds(ctx, jobRunAt.Add(-time.Second), 10)
+		assert.Nil(t, err)
+		assert.Empty(t, dueIds)
+
+		dueIds, err = GetDueJobIds(ctx, jobRunAt, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{jobAsStruct.Id}, dueIds)
+
+		queuedJobs, err := GetQueuedJobs(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, []models.Job{jobAsStruct}, queuedJobs)
+	})
+}
+
+func TestFinishJob(t *testing.T) {
+	t.Run("Finished job is removed from queue but keeps its status", func(t *testing.T) {
+		setup()
+		defer teardown()
+		assert.Nil(t, ScheduleJob(ctx, jobAsStruct))
+
+		finishedJob := jobAsStruct
+		finishedJob.Status = "done"
+		finishedJob.Attempts = 1
+		err := FinishJob(ctx, finishedJob)
+		assert.Nil(t, err)
+
+		job, err := GetJob(ctx, jobAsStruct.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, finishedJob, job)
+
+		dueIds, err := GetDueJobIds(ctx, jobRunAt, 10)
+		assert.Nil(t, err)
+		assert.Empty(t, dueIds)
+	})
+}
+
+func TestGetJob(t *testing.T) {
+	t.Run("Fail - not found", func(t *testing.T) {
+		setup()
+		defer teardown()
+		_, err := GetJob(ctx, "non-existent-id")
+		assert.Equal(t, &Error{Kind: ErrNotFound, Op: "get job", Key: "non-existent-id"}, err)
+	})
+}
+
+func TestAcquireJobLease(t *testing.T) {
+	t.Run("Lease is exclusive until released", func(t *testing.T) {
+		setup()
+		defer teardown()
+
+		acquired, err := AcquireJobLease(ctx, jobAsStruct.Id, "replica-1", time.Minute)
+		assert.Nil(t, err)
+		assert.True(t, acquired)
+
+		acquired, err = AcquireJobLease(ctx, jobAsStruct.Id, "replica-2", time.Minute)
+		assert.Nil(t, err)
+		assert.False(t, acquired)
+
+		assert.Nil(t, ReleaseJobLease(ctx, jobAsStruct.Id, "replica-1"))
+		acquired, err = AcquireJobLease(ctx, jobAsStruct.Id, "replica-2", time.Minute)
+		assert.Nil(t, err)
+		assert.True(t, acquired)
+	})
+
+	t.Run("Lease taken over after expiry is not released by previous owner", func(t *testing.T) {
+		setup()
+		defer teardown()
+
+		acquired, _ := AcquireJobLease(ctx, jobAsStruct.Id, "replica-2", time.Minute)
+		assert.True(t, acquired)
+
+		assert.Nil(t, ReleaseJobLease(ctx, jobAsStruct.Id, "replica-1"))
+		acquired, err := AcquireJobLease(ctx, jobAsStruct.Id, "replica-3", time.Minute)
+		assert.Nil(t, err)
+		assert.False(t, acquired, "lease of replica-2 should be kept")
+	})
+}
@@ -0,0 +1,125 @@
+package db
+
+import (
+	"app/models"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// notifications are written to the outbox in the same transaction as the event mutation,
+// outbox is a sorted set scored by the time of the next delivery attempt (unix ms)
+
+const outboxQueueKey = "outbox:queue"
+const outboxMessageKeyPrefix = "outbox:message:"
+const outboxLeaseKeyPrefix = "outbox:lease:"
+const outboxDeadLetterKey = "outbox:dead"
+
+func newNotification(kind string, event models.EventResponseData) models.Notification {
+	return models.Notification{
+		Id:        uuid.NewString(),
+		Kind:      kind,
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func enqueueNotification(c context.Context, pipe redis.Pipeliner, notification models.Notification, sendAt time.Time) error {
+	dataAsJsonString, err := json.Marshal(notification)
+	if err != nil {
+		log.Logger.Error().Msgf("error converting notification to json: %v", err)
+		return newError(ErrInvalid, "enqueue notification", notification.Id, err)
+	}
+	pipe.Set(c, outboxMessageKeyPrefix+notification.Id, dataAsJsonString, 0)
+	outboxQueue.add(c, pipe, notification.Id, sendAt)
+	return nil
+}
+
+var EnqueueNotification = func(c context.Context, kind string, event models.EventResponseData) error {
+	notification := newNotification(kind, event)
+	_, err := redisClient.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		return enqueueNotification(c, pipe, notification, time.Now().UTC())
+	})
+	if err != nil {
+		log.Logger.Error().Msgf("error on writing notification to redis: %v", err)
+	}
+	return redisError("enqueue notification", notification.Id, err)
+}
+
+// GetDueNotificationIds returns ids of notifications to be delivered at `now`, oldest first.
+var GetDueNotificationIds = func(c context.Context, now time.Time, limit int64) ([]string, error) {
+	return outboxQueue.due(c, now, limit)
+}
+
+var GetNotification = func(c context.Context, id string) (models.Notification, error) {
+	result, err := redisClient.Get(c, outboxMessageKeyPrefix+id).Result()
+	if err != nil {
+		return models.Notification{}, redisError("get notification", id, err)
+	}
+	var notification models.Notification
+	jsonParseErr := json.Unmarshal([]byte(result), &notification)
+	if jsonParseErr != nil {
+		return models.Notification{}, newError(ErrInvalid, "get notification", id, jsonParseErr)
+	}
+	return notification, nil
+}
+
+var AcquireNotificationLease = func(c context.Context, id string, owner string, ttl time.Duration) (bool, error) {
+	return outboxQueue.acquire(c, id, owner, ttl)
+}
+
+var ReleaseNotificationLease = func(c context.Context, id string, owner string) error {
+	return outboxQueue.release(c, id, owner)
+}
+
+// AckNotification removes delivered notification from the outbox.
+var AckNotification = func(c context.Context, id string) error {
+	_, err := redisClient.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		pipe.Del(c, outboxMessageKeyPrefix+id)
+		outboxQueue.remove(c, pipe, id)
+		return nil
+	})
+	return redisError("ack notification", id, err)
+}
+
+var RetryNotification = func(c context.Context, notification models.Notification, retryAt time.Time) error {
+	_, err := redisClient.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		return enqueueNotification(c, pipe, notification, retryAt)
+	})
+	return redisError("retry notification", notification.Id, err)
+}
+
+// DeadLetterNotification moves notification which ran out of attempts to the dead-letter list.
+var DeadLetterNotification = func(c context.Context, notification models.Notification) error {
+	dataAsJsonString, err := json.Marshal(notification)
+	if err != nil {
+		return newError(ErrInvalid, "dead-letter notification", notification.Id, err)
+	}
+	_, err = redisClient.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		pipe.LPush(c, outboxDeadLetterKey, dataAsJsonString)
+		pipe.Del(c, outboxMessageKeyPrefix+notification.Id)
+		outboxQueue.remove(c, pipe, notification.Id)
+		return nil
+	})
+	return redisError("dead-letter notification", notification.Id, err)
+}
+
+var GetDeadNotifications = func(c context.Context) ([]models.Notification, error) {
+	results, err := redisClient.LRange(c, outboxDeadLetterKey, 0, -1).Result()
+	if err != nil {
+		return nil, redisError("get dead notifications", outboxDeadLetterKey, err)
+	}
+	notifications := []models.Notification{}
+	for _, result := range results {
+		var notification models.Notification
+		if err := json.Unmarshal([]byte(result), &notification); err != nil {
+			return nil, newError(ErrInvalid, "get dead notifications", outboxDeadLetterKey, err)
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, nil
+}
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"app/lifecycle"
+	"app/models"
+	"app/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var originalGetJsonStringFromStruct = utils.GetJsonStringFromStruct
+
+func getOutbox(t *testing.T) []models.Notification {
+	ids, err := GetDueNotificationIds(ctx, time.Now().UTC().Add(time.Hour), 100)
+	assert.Nil(t, err)
+	notifications := []models.Notification{}
+	for _, id := range ids {
+		notification, err := GetNotification(ctx, id)
+		assert.Nil(t, err)
+		notifications = append(notifications, notification)
+	}
+	return notifications
+}
+
+func TestOutboxWrittenWithEventMutations(t *testing.T) {
+	utils.GetJsonStringFromStruct = originalGetJsonStringFromStruct
+
+	t.Run("CreateEvent writes `event.created` notification", func(t *testing.T) {
+		setup()
+		defer teardown()
+
+		id, err := CreateEvent(ctx, eventDataAsStruct)
+		assert.Nil(t, err)
+
+		outbox := getOutbox(t)
+		assert.Len(t, outbox, 1)
+		assert.Equal(t, models.NotificationEventCreated, outbox[0].Kind)
+		assert.Equal(t, id, outbox[0].Event.Id)
+		assert.Equal(t, eventDataAsStruct.Invitees, outbox[0].Event.Invitees)
+	})
+
+	t.Run("UpdateEvent of cancelled event writes `event.cancelled` notification", func(t *testing.T) {
+		setup()
+		defer teardown()
+		insertDataToCache(redisClient, []KeyValuePair{{key: "event-id-string", value: eventDataAsJsonString}})
+
+		cancelledEvent := eventDataAsStruct
+		cancelledEvent.Status = lifecycle.Cancelled
+		err := UpdateEvent(ctx, "event-id-string", cancelledEvent)
+		assert.Nil(t, err)
+
+		outbox := getOutbox(t)
+		assert.Len(t, outbox, 1)
+		assert.Equal(t, models.NotificationEventCancelled, outbox[0].Kind)
+		assert.Equal(t, "event-id-string", outbox[0].Event.Id)
+	})
+
+	t.Run("UpdateEvent of missing event writes no notification", func(t *testing.T) {
+		setup()
+		defer teardown()
+
+		err := UpdateEvent(ctx, "non-existent-id", eventDataAsStruct)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Empty(t, getOutbox(t))
+	})
+}
+
+func TestNotificationDelivery(t *testing.T) {
+	event := models.EventResponseData{Id: "event-id-string", EventData: eventDataAsStruct}
+
+	t.Run("Retried notification is due at retry time", func(t *testing.T) {
+		setup()
+		defer teardown()
+		assert.Nil(t, EnqueueNotification(ctx, models.NotificationEventReminder, event))
+		notification := getOutbox(t)[0]
+
+		notification.Attempts = 1
+		retryAt := time.Now().UTC().Add(30 * time.Minute)
+		assert.Nil(t, RetryNotification(ctx, notification, retryAt))
+
+		dueIds, err := GetDueNotificationIds(ctx, retryAt.Add(-time.Second), 100)
+		assert.Nil(t, err)
+		assert.Empty(t, dueIds)
+		stored, err := GetNotification(ctx, notification.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, stored.Attempts)
+	})
+
+	t.Run("Acknowledged notification is removed", func(t *testing.T) {
+		setup()
+		defer teardown()
+		assert.Nil(t, EnqueueNotification(ctx, models.NotificationEventReminder, event))
+		notification := getOutbox(t)[0]
+
+		assert.Nil(t, AckNotification(ctx, notification.Id))
+
+		assert.Empty(t, getOutbox(t))
+		_, err := GetNotification(ctx, notification.Id)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Dead-lettered notification is moved to dead-letter list", func(t *testing.T) {
+		setup()
+		defer teardown()
+		assert.Nil(t, EnqueueNotification(ctx, models.NotificationEventReminder, event))
+		notification := getOutbox(t)[0]
+		notification.Attempts = 5
+		notification.LastError = "any error"
+
+		assert.Nil(t, DeadLetterNotification(ctx, notification))
+
+		assert.Empty(t, getOutbox(t))
+		deadNotifications, err := GetDeadNotifications(ctx)
+		assert.Nil(t, err)
+		assert.Len(t, deadNotifications, 1)
+		assert.Equal(t, notification.Id, deadNotifications[0].Id)
+		assert.Equal(t, "any error", deadNotifications[0].LastError)
+	})
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// presence of live channel viewers is a sorted set of connections scored by their last heartbeat (unix ms),
+// organizer announcements are fanned out to every replica through Pub/Sub
+
+const presenceKeyPrefix = "presence:"
+const liveChannelPrefix = "live:"
+
+// TouchPresence records heartbeat of connection `member`, connections silent for `ttl` are dropped.
+var TouchPresence = func(c context.Context, eventId string, member string, now time.Time, ttl time.Duration) error {
+	key := presenceKeyPrefix + eventId
+	_, err := redisClient.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(c, key, &redis.Z{Score: float64(now.UnixMilli()), Member: member})
+		pipe.ZRemRangeByScore(c, key, "-inf", strconv.FormatInt(now.Add(-ttl).UnixMilli(), 10))
+		pipe.Expire(c, key, ttl)
+		return nil
+	})
+	return redisError("touch presence", key, err)
+}
+
+var RemovePresence = func(c context.Context, eventId string, member string) error {
+	return redisError("remove presence", presenceKeyPrefix+eventId, redisClient.ZRem(c, presenceKeyPrefix+eventId, member).Err())
+}
+
+// GetPresence returns connections with heartbeat within `ttl`.
+var GetPresence = func(c context.Context, eventId string, now time.Time, ttl time.Duration) ([]string, error) {
+	members, err := redisClient.ZRangeByScore(c, presenceKeyPrefix+eventId, &redis.ZRangeBy{
+		Min: strconv.FormatInt(now.Add(-ttl).UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, redisError("get presence", presenceKeyPrefix+eventId, err)
+	}
+	return members, nil
+}
+
+var PublishLiveMessage = func(c context.Context, eventId string, message string) error {
+	return redisError("publish live message", liveChannelPrefix+eventId, redisClient.Publish(c, liveChannelPrefix+eventId, message).Err())
+}
+
+// SubscribeLiveMessages delivers messages published to event's live channel until `c` is done.
+var SubscribeLiveMessages = func(c context.Context, eventId string) (<-chan string, error) {
+	pubsub := redisClient.Subscribe(c, liveChannelPrefix+eventId)
+	// waits for subscription confirmation so that no message published afterwards is missed
+	if _, err := pubsub.Receive(c); err != nil {
+		pubsub.Close()
+		return nil, redisError("subscribe live messages", liveChannelPrefix+eventId, err)
+	}
+	messages := make(chan string)
+	go func() {
+		defer close(messages)
+		defer pubsub.Close()
+		channel := pubsub.Channel()
+		for {
+			select {
+			case <-c.Done():
+				return
+			case message, ok := <-channel:
+				if !ok {
+					return
+				}
+				select {
+				case messages <- message.Payload:
+				case <-c.Done():
+					return
+				}
+			}
+		}
+	}()
+	return messages, nil
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var presenceNow = time.Date(2023, 4, 20, 13, 45, 0, 0, time.UTC)
+
+func TestPresence(t *testing.T) {
+	t.Run("Connections without heartbeat within ttl are not present", func(t *testing.T) {
+		setup()
+		defer teardown()
+
+		assert.Nil(t, TouchPresence(ctx, "event-id-string", "first#1", presenceNow.Add(-time.Minute), 30*time.Second))
+		assert.Nil(t, TouchPresence(ctx, "event-id-string", "second#2", presenceNow, 30*time.Second))
+
+		members, err := GetPresence(ctx, "event-id-string", presenceNow, 30*time.Second)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"second#2"}, members)
+
+		assert.Nil(t, RemovePresence(ctx, "event-id-string", "second#2"))
+		members, err = GetPresence(ctx, "event-id-string", presenceNow, 30*time.Second)
+		assert.Nil(t, err)
+		assert.Empty(t, members)
+	})
+}
+
+func TestLiveMessages(t *testing.T) {
+	t.Run("Published message is delivered to subscriber of the event", func(t *testing.T) {
+		setup()
+		defer teardown()
+		c, cancel := context.WithCancel(ctx)
+
+		messages, err := SubscribeLiveMessages(c, "event-id-string")
+		assert.Nil(t, err)
+		assert.Nil(t, PublishLiveMessage(ctx, "other-event-id-string", "ignored"))
+		assert.Nil(t, PublishLiveMessage(ctx, "event-id-string", "announcement"))
+
+		select {
+		case message := <-messages:
+			assert.Equal(t, "announcement", message)
+		case <-time.After(time.Second):
+			t.Fatal("message not delivered")
+		}
+
+		cancel()
+		for range messages {
+		}
+	})
+}
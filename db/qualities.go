@@ -0,0 +1,154 @@
+package db
+
+import (
+	"app/models"
+	"context"
+	"encoding/json"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/exp/slices"
+)
+
+// profiles are kept in one hash keyed by `<kind>:<name>`, enabled sets in another one keyed by tenant
+const qualityProfilesKey = "quality:profiles"
+const tenantQualitiesKey = "quality:tenants"
+
+func qualityProfileField(kind string, name string) string {
+	return kind + ":" + name
+}
+
+var GetQualityProfiles = func(c context.Context) ([]models.QualityProfile, error) {
+	documents, err := redisClient.HGetAll(c, qualityProfilesKey).Result()
+	if err != nil {
+		return nil, redisError("get quality profiles", qualityProfilesKey, err)
+	}
+	profiles := []models.QualityProfile{}
+	for field, document := range documents {
+		var profile models.QualityProfile
+		if err := json.Unmarshal([]byte(document), &profile); err != nil {
+			log.Logger.Error().Msgf("skipping malformed quality profile `%v`: %v", field, err)
+			continue
+		}
+		profiles = append(profiles, profile)
+	}
+	slices.SortFunc(profiles, func(a models.QualityProfile, b models.QualityProfile) bool {
+		if a.Kind != b.Kind {
+			return a.Kind > b.Kind
+		}
+		if a.BitrateKbps != b.BitrateKbps {
+			return a.BitrateKbps < b.BitrateKbps
+		}
+		return a.Name < b.Name
+	})
+	return profiles, nil
+}
+
+var GetQualityProfile = func(c context.Context, kind string, name string) (models.QualityProfile, error) {
+	field := qualityProfileField(kind, name)
+	document, err := redisClient.HGet(c, qualityProfilesKey, field).Result()
+	if err != nil {
+		return models.QualityProfile{}, redisError("get quality profile", field, err)
+	}
+	var profile models.QualityProfile
+	if err := json.Unmarshal([]byte(document), &profile); err != nil {
+		return models.QualityProfile{}, newError(ErrInvalid, "get quality profile", field, err)
+	}
+	return profile, nil
+}
+
+// SeedQualityProfiles stores `profiles` if the catalog is empty, so that fresh deployment has a usable catalog
+// while profiles deleted by admin do not come back.
+var SeedQualityProfiles = func(c context.Context, profiles []models.QualityProfile) error {
+	err := redisClient.Watch(c, func(tx *redis.Tx) error {
+		count, err := tx.HLen(c, qualityProfilesKey).Result()
+		if err != nil || count > 0 {
+			return err
+		}
+		_, err = tx.TxPipelined(c, func(pipe redis.Pipeliner) error {
+			for _, profile := range profiles {
+				document, _ := json.Marshal(profile)
+				pipe.HSet(c, qualityProfilesKey, qualityProfileField(profile.Kind, profile.Name), document)
+			}
+			return nil
+		})
+		return err
+	}, qualityProfilesKey)
+	return redisError("seed quality profiles", qualityProfilesKey, err)
+}
+
+// SaveQualityProfile stores `profile`, `create` fails with ErrConflict if it is stored already,
+// otherwise with ErrNotFound if it is not.
+var SaveQualityProfile = func(c context.Context, profile models.QualityProfile, create bool) error {
+	field := qualityProfileField(profile.Kind, profile.Name)
+	document, err := json.Marshal(profile)
+	if err != nil {
+		return newError(ErrInvalid, "save quality profile", field, err)
+	}
+	err = redisClient.Watch(c, func(tx *redis.Tx) error {
+		exists, err := tx.HExists(c, qualityProfilesKey, field).Result()
+		if err != nil {
+			return err
+		}
+		if create && exists {
+			return newError(ErrConflict, "save quality profile", field, nil)
+		}
+		if !create && !exists {
+			return newError(ErrNotFound, "save quality profile", field, nil)
+		}
+		_, err = tx.TxPipelined(c, func(pipe redis.Pipeliner) error {
+			pipe.HSet(c, qualityProfilesKey, field, document)
+			return nil
+		})
+		return err
+	}, qualityProfilesKey)
+	return redisError("save quality profile", field, err)
+}
+
+var DeleteQualityProfile = func(c context.Context, kind string, name string) error {
+	field := qualityProfileField(kind, name)
+	deleted, err := redisClient.HDel(c, qualityProfilesKey, field).Result()
+	if err != nil {
+		return redisError("delete quality profile", field, err)
+	}
+	if deleted == 0 {
+		return newError(ErrNotFound, "delete quality profile", field, nil)
+	}
+	return nil
+}
+
+var GetTenantQualities = func(c context.Context) (map[string]models.TenantQualities, error) {
+	documents, err := redisClient.HGetAll(c, tenantQualitiesKey).Result()
+	if err != nil {
+		return nil, redisError("get tenant qualities", tenantQualitiesKey, err)
+	}
+	tenants := map[string]models.TenantQualities{}
+	for tenant, document := range documents {
+		var enabled models.TenantQualities
+		if err := json.Unmarshal([]byte(document), &enabled); err != nil {
+			log.Logger.Error().Msgf("skipping malformed qualities of tenant `%v`: %v", tenant, err)
+			continue
+		}
+		tenants[tenant] = enabled
+	}
+	return tenants, nil
+}
+
+var SetTenantQualities = func(c context.Context, tenant string, enabled models.TenantQualities) error {
+	document, err := json.Marshal(enabled)
+	if err != nil {
+		return newError(ErrInvalid, "set tenant qualities", tenant, err)
+	}
+	return redisError("set tenant qualities", tenant, redisClient.HSet(c, tenantQualitiesKey, tenant, document).Err())
+}
+
+var DeleteTenantQualities = func(c context.Context, tenant string) error {
+	deleted, err := redisClient.HDel(c, tenantQualitiesKey, tenant).Result()
+	if err != nil {
+		return redisError("delete tenant qualities", tenant, err)
+	}
+	if deleted == 0 {
+		return newError(ErrNotFound, "delete tenant qualities", tenant, nil)
+	}
+	return nil
+}
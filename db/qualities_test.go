@@ -0,0 +1,90 @@
+package db
+
+import (
+	"app/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var qualityProfilesAsStruct = []models.QualityProfile{
+	{Kind: models.QualityAudio, Name: "Low", Codec: "mp4a.40.2", BitrateKbps: 64, Channels: 2, SampleRateHz: 44100},
+	{Kind: models.QualityVideo, Name: "1080p", Codec: "avc1.640028", BitrateKbps: 6000, Width: 1920, Height: 1080},
+	{Kind: models.QualityVideo, Name: "720p", Codec: "avc1.64001f", BitrateKbps: 3000, Width: 1280, Height: 720},
+}
+
+func TestQualityProfiles(t *testing.T) {
+	c := context.Background()
+
+	t.Run("Seeded profiles are listed video first, by bitrate", func(t *testing.T) {
+		setup()
+		defer teardown()
+
+		assert.Nil(t, SeedQualityProfiles(c, qualityProfilesAsStruct))
+		profiles, err := GetQualityProfiles(c)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []models.QualityProfile{
+			qualityProfilesAsStruct[2], qualityProfilesAsStruct[1], qualityProfilesAsStruct[0],
+		}, profiles)
+	})
+
+	t.Run("Seed does not bring back deleted profiles", func(t *testing.T) {
+		setup()
+		defer teardown()
+
+		assert.Nil(t, SeedQualityProfiles(c, qualityProfilesAsStruct))
+		assert.Nil(t, DeleteQualityProfile(c, models.QualityVideo, "720p"))
+		assert.Nil(t, SeedQualityProfiles(c, qualityProfilesAsStruct))
+
+		profiles, _ := GetQualityProfiles(c)
+		assert.Len(t, profiles, 2)
+		assert.ErrorIs(t, DeleteQualityProfile(c, models.QualityVideo, "720p"), ErrNotFound)
+	})
+
+	t.Run("Single profile is read by kind and name", func(t *testing.T) {
+		setup()
+		defer teardown()
+
+		assert.Nil(t, SeedQualityProfiles(c, qualityProfilesAsStruct))
+		profile, err := GetQualityProfile(c, models.QualityVideo, "1080p")
+
+		assert.Nil(t, err)
+		assert.Equal(t, qualityProfilesAsStruct[1], profile)
+		_, err = GetQualityProfile(c, models.QualityAudio, "1080p")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Create fails for existing profile, update for missing one", func(t *testing.T) {
+		setup()
+		defer teardown()
+		profile := qualityProfilesAsStruct[1]
+
+		assert.ErrorIs(t, SaveQualityProfile(c, profile, false), ErrNotFound)
+		assert.Nil(t, SaveQualityProfile(c, profile, true))
+		assert.ErrorIs(t, SaveQualityProfile(c, profile, true), ErrConflict)
+		profile.BitrateKbps = 8000
+		assert.Nil(t, SaveQualityProfile(c, profile, false))
+
+		profiles, _ := GetQualityProfiles(c)
+		assert.Equal(t, []models.QualityProfile{profile}, profiles)
+	})
+}
+
+func TestTenantQualities(t *testing.T) {
+	c := context.Background()
+	setup()
+	defer teardown()
+	enabled := models.TenantQualities{Video: []string{"720p"}, Audio: []string{"Low"}}
+
+	assert.Nil(t, SetTenantQualities(c, "tenant-1", enabled))
+	tenants, err := GetTenantQualities(c)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]models.TenantQualities{"tenant-1": enabled}, tenants)
+
+	assert.Nil(t, DeleteTenantQualities(c, "tenant-1"))
+	tenants, _ = GetTenantQualities(c)
+	assert.Empty(t, tenants)
+	assert.ErrorIs(t, DeleteTenantQualities(c, "tenant-1"), ErrNotFound)
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// queue is a sorted set of item ids scored by the time they are due (unix ms), items are processed under leases,
+// so only one replica processes an item at a time. Lease expires after its ttl so that items of crashed replicas
+// are picked up again.
+type queue struct {
+	key            string
+	leaseKeyPrefix string
+}
+
+var jobQueue = queue{key: jobQueueKey, leaseKeyPrefix: jobLeaseKeyPrefix}
+var outboxQueue = queue{key: outboxQueueKey, leaseKeyPrefix: outboxLeaseKeyPrefix}
+var webhookQueue = queue{key: webhookQueueKey, leaseKeyPrefix: webhookLeaseKeyPrefix}
+
+func (q queue) add(c context.Context, pipe redis.Pipeliner, id string, dueAt time.Time) {
+	pipe.ZAdd(c, q.key, &redis.Z{
+		Score:  float64(dueAt.UnixMilli()),
+		Member: id,
+	})
+}
+
+func (q queue) remove(c context.Context, pipe redis.Pipeliner, id string) {
+	pipe.ZRem(c, q.key, id)
+}
+
+// due returns ids of items due at `now`, oldest first.
+func (q queue) due(c context.Context, now time.Time, limit int64) ([]string, error) {
+	ids, err := redisClient.ZRangeByScore(c, q.key, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.UnixMilli(), 10),
+		Count: limit,
+	}).Result()
+	return ids, redisError("get due items", q.key, err)
+}
+
+func (q queue) acquire(c context.Context, id string, owner string, ttl time.Duration) (bool, error) {
+	acquired, err := redisClient.SetNX(c, q.leaseKeyPrefix+id, owner, ttl).Result()
+	return acquired, redisError("acquire lease", q.leaseKeyPrefix+id, err)
+}
+
+// releaseLeaseScript deletes lease only if it is still held by the owner, lease which expired while its item
+// was processed may be held by another replica already.
+var releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+func (q queue) release(c context.Context, id string, owner string) error {
+	err := releaseLeaseScript.Run(c, redisClient, []string{q.leaseKeyPrefix + id}, owner).Err()
+	return redisError("release lease", q.leaseKeyPrefix+id, err)
+}
@@ -1,13 +1,17 @@
 package db
 
 import (
+	"app/config"
+	"app/health"
+	"app/lifecycle"
+	lg "app/logging"
+	"app/metrics"
 	"app/models"
 	"app/utils"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -19,69 +23,187 @@ import (
 // but since this repo has only 1 endpoint and any db selected would need to have a database layer
 // I decided to use it since I can demonstrate the layer and unit tests with it and it is easy to setup
 
-var redisEndpoint string = os.Getenv("REDIS_ENDPOINT")
-var redisPassword string = os.Getenv("REDIS_PASSWORD")
-var ctx = context.Background()
-var redisClient = Init()
+// redisClient points to default local server until Connect is called
+var redisClient = newClient(config.Default().Redis)
 
-func Init() *redis.Client {
+func newClient(settings config.Redis) *redis.Client {
 	rdb := redis.NewClient(&redis.Options{
-		Addr:        redisEndpoint,
-		Password:    redisPassword,
+		Addr:        settings.Addr(),
+		Password:    settings.Password,
 		DialTimeout: time.Second,
 	})
-	_, err := rdb.Ping(ctx).Result()
+	rdb.AddHook(instrumentationHook{})
+	return rdb
+}
+
+// Connect replaces the client by one using `settings`, connection failure is only logged
+// since readiness probe reports it and the client reconnects on its own.
+func Connect(settings config.Redis) {
+	redisClient.Close()
+	redisClient = newClient(settings)
+	changesStreamMaxLen = settings.ChangesMaxLen
+	_, err := redisClient.Ping(context.Background()).Result()
 	if err != nil {
 		log.Logger.Error().Msg(fmt.Sprintf(
 			"error connecting to redis cache, err: '%v'", err))
 	} else {
 		log.Logger.Info().Msg("redis connection successful")
 	}
-	return rdb
 }
 
-var GetEvent = func(id string) (models.EventResponseData, error) {
-	result, err := redisClient.Get(ctx, id).Result()
+func init() {
+	health.Register("redis", true, time.Second, func(c context.Context) error {
+		return Ping(c)
+	})
+}
+
+var Ping = func(c context.Context) error {
+	return redisError("ping", "", redisClient.Ping(c).Err())
+}
+
+// Close closes connections of the client, it cannot be used afterwards.
+func Close() error {
+	return redisClient.Close()
+}
+
+var GetEvent = func(c context.Context, id string) (models.EventResponseData, error) {
+	result, err := redisClient.Get(c, id).Result()
 	if err != nil {
-		if err == redis.Nil {
-			return models.EventResponseData{}, errors.New("not found")
-		}
-		return models.EventResponseData{}, errors.New(
-			"redis connection error",
-		)
+		return models.EventResponseData{}, redisError("get event", id, err)
 	}
 	var eventData models.EventResponseData
 	jsonParseErr := json.Unmarshal([]byte(result), &eventData)
 	if jsonParseErr != nil {
-		return models.EventResponseData{}, jsonParseErr
+		return models.EventResponseData{}, newError(ErrInvalid, "get event", id, jsonParseErr)
 	}
 	eventData.Id = id
 	return eventData, nil
 }
 
-var CreateEvent = func(payload models.EventData) (string, error) {
+var CreateEvent = func(c context.Context, payload models.EventData) (string, error) {
 	eventId := uuid.NewString()
 	payload.Id = eventId
 	dataAsJsonString, convertErr := utils.GetJsonStringFromStruct(payload)
 	if convertErr != nil {
-		log.Logger.Error().Msgf("error converting data to json: %v", convertErr)
+		lg.WithContext(c).Error().Msgf("error converting data to json: %v", convertErr)
 		return "", convertErr
 	}
-	err := redisClient.Set(ctx, eventId, dataAsJsonString, 0).Err()
+	event := models.EventResponseData{Id: eventId, EventData: payload}
+	notification := newNotification(models.NotificationEventCreated, event)
+	subscriptions, err := GetWebhookSubscriptions(c)
+	if err != nil {
+		lg.WithContext(c).Error().Msgf("error reading webhook subscriptions: %v", err)
+		return "", redisError("create event", eventId, err)
+	}
+	_, err = redisClient.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		pipe.Set(c, eventId, dataAsJsonString, 0)
+		if err := enqueueNotification(c, pipe, notification, notification.CreatedAt); err != nil {
+			return err
+		}
+		if err := enqueueWebhookDeliveries(c, pipe, subscriptions, models.WebhookEventCreated, event); err != nil {
+			return err
+		}
+		appendAudit(c, pipe, models.AuditEventCreate, eventId, "", dataAsJsonString)
+		return appendChange(c, pipe, models.DomainEventCreated, event)
+	})
 	if err != nil {
-		log.Logger.Error().Msgf("error on setting data to redis: %v", err)
-		return "", err
+		lg.WithContext(c).Error().Msgf("error on setting data to redis: %v", err)
+		return "", redisError("create event", eventId, err)
 	}
+	metrics.EventsCreated.Inc()
 	return eventId, nil
 }
 
-var DeleteEvent = func(id string) error {
-	result, err := redisClient.Del(ctx, id).Result()
+var DeleteEvent = func(c context.Context, id string) error {
+	subscriptions, err := GetWebhookSubscriptions(c)
 	if err != nil {
+		lg.WithContext(c).Error().Msgf("error reading webhook subscriptions: %v", err)
+		return redisError("delete event", id, err)
+	}
+	err = redisClient.Watch(c, func(tx *redis.Tx) error {
+		result, err := tx.Get(c, id).Result()
+		if err != nil {
+			return redisError("delete event", id, err)
+		}
+		// deleted event is announced with its last known data, id is enough if it cannot be parsed
+		var event models.EventResponseData
+		_ = json.Unmarshal([]byte(result), &event)
+		event.Id = id
+		_, err = tx.TxPipelined(c, func(pipe redis.Pipeliner) error {
+			pipe.Del(c, id)
+			if err := enqueueWebhookDeliveries(c, pipe, subscriptions, models.WebhookEventDeleted, event); err != nil {
+				return err
+			}
+			appendAudit(c, pipe, models.AuditEventDelete, id, result, "")
+			return appendChange(c, pipe, models.DomainEventDeleted, event)
+		})
 		return err
+	}, id)
+	if err == nil {
+		metrics.EventsDeleted.Inc()
 	}
-	if result == 0 {
-		return errors.New("not found")
+	return redisError("delete event", id, err)
+}
+
+var UpdateEvent = func(c context.Context, id string, payload models.EventData) error {
+	return updateEvent(c, id, payload, nil)
+}
+
+// UpdateEventStatus updates event like UpdateEvent, provided its stored status is still `expectedStatus`,
+// otherwise it fails with ErrConflict, so concurrent status changes do not overwrite each other.
+var UpdateEventStatus = func(c context.Context, id string, expectedStatus string, payload models.EventData) error {
+	return updateEvent(c, id, payload, &expectedStatus)
+}
+
+func updateEvent(c context.Context, id string, payload models.EventData, expectedStatus *string) error {
+	payload.Id = id
+	dataAsJsonString, convertErr := utils.GetJsonStringFromStruct(payload)
+	if convertErr != nil {
+		lg.WithContext(c).Error().Msgf("error converting data to json: %v", convertErr)
+		return convertErr
+	}
+	kind := models.NotificationEventUpdated
+	if payload.Status == lifecycle.Cancelled {
+		kind = models.NotificationEventCancelled
+	}
+	event := models.EventResponseData{Id: id, EventData: payload}
+	notification := newNotification(kind, event)
+	subscriptions, err := GetWebhookSubscriptions(c)
+	if err != nil {
+		lg.WithContext(c).Error().Msgf("error reading webhook subscriptions: %v", err)
+		return redisError("update event", id, err)
+	}
+	// watching the key makes sure event is not deleted between the check and the write
+	err = redisClient.Watch(c, func(tx *redis.Tx) error {
+		before, err := tx.Get(c, id).Result()
+		if err != nil {
+			return redisError("update event", id, err)
+		}
+		if expectedStatus != nil {
+			var stored models.EventData
+			if err := json.Unmarshal([]byte(before), &stored); err != nil {
+				return newError(ErrInvalid, "update event", id, err)
+			}
+			if stored.Status != *expectedStatus {
+				return newError(ErrConflict, "update event", id, nil)
+			}
+		}
+		_, err = tx.TxPipelined(c, func(pipe redis.Pipeliner) error {
+			pipe.Set(c, id, dataAsJsonString, 0)
+			if err := enqueueNotification(c, pipe, notification, notification.CreatedAt); err != nil {
+				return err
+			}
+			if err := enqueueWebhookDeliveries(c, pipe, subscriptions, models.WebhookEventUpdated, event); err != nil {
+				return err
+			}
+			appendAudit(c, pipe, models.AuditEventUpdate, id, before, dataAsJsonString)
+			return appendChange(c, pipe, models.DomainEventUpdated, event)
+		})
+		return err
+	}, id)
+	err = redisError("update event", id, err)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		lg.WithContext(c).Error().Msgf("error on setting data to redis: %v", err)
 	}
-	return nil
+	return err
 }
@@ -1,21 +1,40 @@
 package db
 
 import (
+	"app/config"
+	lg "app/logging"
 	"app/models"
 	"app/utils"
+	"bytes"
+	"context"
 	"errors"
+	"flag"
+	"net/http/httptest"
+	"os"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/alicebob/miniredis"
+	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
 )
 
 var redisServer, _ = miniredis.Run()
 
+var ctx = context.Background()
+
+func TestMain(m *testing.M) {
+	// tests use the server configured for the service, validity of unrelated settings does not matter
+	settings, _ := config.Load(flag.NewFlagSet("db.test", flag.ContinueOnError), nil)
+	Connect(settings.Redis)
+	os.Exit(m.Run())
+}
+
 func mockRedis() *miniredis.Miniredis {
 	s, err := miniredis.Run()
 
@@ -68,6 +87,10 @@ func retrieveDataFromCache(client *redis.Client) []KeyValuePair {
 	var value string
 	for iter.Next(ctx) {
 		key = iter.Val()
+		// only event documents, namespaced keys (outbox, scheduler) are checked by their own tests
+		if strings.Contains(key, ":") {
+			continue
+		}
 		value, _ = client.Get(ctx, key).Result()
 		data = append(data, KeyValuePair{
 			key:   key,
@@ -125,7 +148,7 @@ var GetEventTestCases = []struct {
 	{
 		description:   "Fail - not found",
 		submitId:      "non-existent-id",
-		expectedError: errors.New("not found"),
+		expectedError: &Error{Kind: ErrNotFound, Op: "get event", Key: "non-existent-id"},
 	},
 }
 
@@ -135,7 +158,7 @@ func TestGetEvent(t *testing.T) {
 			setup()
 			defer teardown()
 			insertDataToCache(redisClient, testCase.innitialCache)
-			resp, err := GetEvent(testCase.submitId)
+			resp, err := GetEvent(ctx, testCase.submitId)
 			assert.Equal(t, testCase.expectedError, err)
 			assert.Equal(t, testCase.expectedResp, resp)
 		})
@@ -196,7 +219,7 @@ func TestCreateEvent(t *testing.T) {
 			}
 			insertDataToCache(redisClient, testCase.innitialCache)
 
-			respId, err := CreateEvent(testCase.submitPayload)
+			respId, err := CreateEvent(ctx, testCase.submitPayload)
 
 			if testCase.expectRespId {
 				_, uuIderr := uuid.Parse(respId)
@@ -267,7 +290,7 @@ var DeleteEventTestCases = []struct {
 			},
 		},
 		submitId:      "non-existent-id",
-		expectedError: errors.New("not found"),
+		expectedError: &Error{Kind: ErrNotFound, Op: "delete event", Key: "non-existent-id"},
 	},
 }
 
@@ -277,7 +300,73 @@ func TestDeleteEvent(t *testing.T) {
 			setup()
 			defer teardown()
 			insertDataToCache(redisClient, testCase.innitialCache)
-			err := DeleteEvent(testCase.submitId)
+			err := DeleteEvent(ctx, testCase.submitId)
+			assert.Equal(t, testCase.expectedError, err)
+			cacheContents := retrieveDataFromCache(redisClient)
+			assert.Equal(t,
+				sortDataByKey(testCase.expectedCache),
+				sortDataByKey(cacheContents),
+			)
+		})
+	}
+}
+
+var UpdateEventTestCases = []struct {
+	description   string
+	innitialCache []KeyValuePair
+	submitId      string
+	expectedCache []KeyValuePair
+	expectedError error
+}{
+	{
+		description: "Success",
+		innitialCache: []KeyValuePair{
+			{
+				key:   "event-id-string",
+				value: "content",
+			},
+		},
+		submitId: "event-id-string",
+		expectedCache: []KeyValuePair{
+			{
+				key:   "event-id-string",
+				value: eventDataAsJsonString,
+			},
+		},
+	},
+	{
+		description: "Fail - key does not exist",
+		innitialCache: []KeyValuePair{
+			{
+				key:   "id-1",
+				value: "content-1",
+			},
+		},
+		submitId: "non-existent-id",
+		expectedCache: []KeyValuePair{
+			{
+				key:   "id-1",
+				value: "content-1",
+			},
+		},
+		expectedError: &Error{Kind: ErrNotFound, Op: "update event", Key: "non-existent-id"},
+	},
+}
+
+func TestUpdateEvent(t *testing.T) {
+	for _, testCase := range UpdateEventTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			setup()
+			defer teardown()
+			utils.GetJsonStringFromStruct = func(data interface{}) (string, error) {
+				convertedData := data.(models.EventData)
+				assert.Equal(t, testCase.submitId, convertedData.Id)
+				return eventDataAsJsonString, nil
+			}
+			insertDataToCache(redisClient, testCase.innitialCache)
+
+			err := UpdateEvent(ctx, testCase.submitId, eventDataAsStruct)
+
 			assert.Equal(t, testCase.expectedError, err)
 			cacheContents := retrieveDataFromCache(redisClient)
 			assert.Equal(t,
@@ -287,3 +376,70 @@ func TestDeleteEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateEventStatus(t *testing.T) {
+	utils.GetJsonStringFromStruct = originalGetJsonStringFromStruct
+	setup()
+	defer teardown()
+	id, err := CreateEvent(ctx, models.EventData{Name: "event-name", Status: "draft"})
+	assert.Nil(t, err)
+
+	t.Run("Status is updated when it did not change meanwhile", func(t *testing.T) {
+		err := UpdateEventStatus(ctx, id, "draft", models.EventData{Name: "event-name", Status: "scheduled"})
+		assert.Nil(t, err)
+		event, _ := GetEvent(ctx, id)
+		assert.Equal(t, "scheduled", event.Status)
+	})
+
+	t.Run("Fail - status was changed meanwhile", func(t *testing.T) {
+		err := UpdateEventStatus(ctx, id, "draft", models.EventData{Name: "event-name", Status: "cancelled"})
+		assert.ErrorIs(t, err, ErrConflict)
+		event, _ := GetEvent(ctx, id)
+		assert.Equal(t, "scheduled", event.Status)
+	})
+
+	t.Run("Fail - event does not exist", func(t *testing.T) {
+		err := UpdateEventStatus(ctx, "non-existent-id", "draft", models.EventData{Status: "scheduled"})
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestEventWritesUseRequestContext(t *testing.T) {
+	t.Run("Cancelled request writes nothing", func(t *testing.T) {
+		setup()
+		defer teardown()
+		utils.GetJsonStringFromStruct = originalGetJsonStringFromStruct
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := CreateEvent(cancelled, eventDataAsStruct)
+
+		assert.ErrorIs(t, err, ErrUnavailable)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, retrieveDataFromCache(redisClient))
+	})
+}
+
+func TestEventErrorsAreLoggedWithCorrelationId(t *testing.T) {
+	t.Run("Error log of failed write carries correlation id of the request", func(t *testing.T) {
+		setup()
+		defer teardown()
+		utils.GetJsonStringFromStruct = func(data interface{}) (string, error) {
+			return "", errors.New("any error")
+		}
+		defer func() { utils.GetJsonStringFromStruct = originalGetJsonStringFromStruct }()
+		var output bytes.Buffer
+		log.Logger = log.Output(&output)
+		defer func() { log.Logger = log.Output(os.Stderr) }()
+		requestContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+		requestContext.Request = httptest.NewRequest("POST", "/event", nil)
+		requestContext.Request.Header.Set(lg.RequestIdHeader, "upstream-request-1")
+		lg.Middleware()(requestContext)
+
+		_, err := CreateEvent(requestContext, eventDataAsStruct)
+
+		assert.NotNil(t, err)
+		assert.Contains(t, output.String(), "error converting data to json")
+		assert.Contains(t, output.String(), "upstream-request-1")
+	})
+}
@@ -0,0 +1,211 @@
+package db
+
+import (
+	"app/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/exp/slices"
+)
+
+// deliveries are enqueued in the same transaction as the event mutation,
+// queue is a sorted set scored by the time of the next delivery attempt (unix ms)
+
+const webhookSubscriptionsKey = "webhook:subscriptions"
+const webhookSubscriptionKeyPrefix = "webhook:subscription:"
+const webhookDeliveryKeyPrefix = "webhook:delivery:"
+const webhookDeliveryLogKeyPrefix = "webhook:deliveries:"
+const webhookQueueKey = "webhook:queue"
+const webhookLeaseKeyPrefix = "webhook:lease:"
+
+// deliveryLogSize is number of latest deliveries kept per subscription
+const deliveryLogSize = 100
+
+var CreateWebhookSubscription = func(c context.Context, payload models.WebhookSubscriptionData) (models.WebhookSubscription, error) {
+	subscription := models.WebhookSubscription{
+		Id:                      uuid.NewString(),
+		CreatedAt:               time.Now().UTC(),
+		WebhookSubscriptionData: payload,
+	}
+	dataAsJsonString, err := json.Marshal(subscription)
+	if err != nil {
+		log.Logger.Error().Msgf("error converting subscription to json: %v", err)
+		return models.WebhookSubscription{}, newError(ErrInvalid, "create webhook subscription", subscription.Id, err)
+	}
+	_, err = redisClient.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		pipe.Set(c, webhookSubscriptionKeyPrefix+subscription.Id, dataAsJsonString, 0)
+		pipe.SAdd(c, webhookSubscriptionsKey, subscription.Id)
+		return nil
+	})
+	if err != nil {
+		log.Logger.Error().Msgf("error on setting subscription to redis: %v", err)
+		return models.WebhookSubscription{}, redisError("create webhook subscription", subscription.Id, err)
+	}
+	return subscription, nil
+}
+
+var GetWebhookSubscription = func(c context.Context, id string) (models.WebhookSubscription, error) {
+	result, err := redisClient.Get(c, webhookSubscriptionKeyPrefix+id).Result()
+	if err != nil {
+		return models.WebhookSubscription{}, redisError("get webhook subscription", id, err)
+	}
+	var subscription models.WebhookSubscription
+	jsonParseErr := json.Unmarshal([]byte(result), &subscription)
+	if jsonParseErr != nil {
+		return models.WebhookSubscription{}, newError(ErrInvalid, "get webhook subscription", id, jsonParseErr)
+	}
+	return subscription, nil
+}
+
+var GetWebhookSubscriptions = func(c context.Context) ([]models.WebhookSubscription, error) {
+	ids, err := redisClient.SMembers(c, webhookSubscriptionsKey).Result()
+	if err != nil {
+		return nil, redisError("get webhook subscriptions", webhookSubscriptionsKey, err)
+	}
+	slices.Sort(ids)
+	subscriptions := []models.WebhookSubscription{}
+	for _, id := range ids {
+		subscription, err := GetWebhookSubscription(c, id)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
+
+var DeleteWebhookSubscription = func(c context.Context, id string) error {
+	var deleted *redis.IntCmd
+	_, err := redisClient.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		deleted = pipe.Del(c, webhookSubscriptionKeyPrefix+id)
+		pipe.SRem(c, webhookSubscriptionsKey, id)
+		return nil
+	})
+	if err != nil {
+		return redisError("delete webhook subscription", id, err)
+	}
+	if deleted.Val() == 0 {
+		return newError(ErrNotFound, "delete webhook subscription", id, nil)
+	}
+	return nil
+}
+
+// enqueueWebhookDeliveries adds delivery for every subscription of `eventType`,
+// `subscriptions` have to be read before the transaction is started.
+func enqueueWebhookDeliveries(
+	c context.Context,
+	pipe redis.Pipeliner,
+	subscriptions []models.WebhookSubscription,
+	eventType string,
+	event models.EventResponseData,
+) error {
+	now := time.Now().UTC()
+	for _, subscription := range subscriptions {
+		if !slices.Contains(subscription.EventTypes, eventType) {
+			continue
+		}
+		deliveryId := uuid.NewString()
+		payload, err := json.Marshal(models.WebhookPayload{
+			Id:         deliveryId,
+			Type:       eventType,
+			OccurredAt: now,
+			Event:      event,
+		})
+		if err != nil {
+			return newError(ErrInvalid, "enqueue webhook delivery", deliveryId, err)
+		}
+		delivery := models.WebhookDelivery{
+			Id:             deliveryId,
+			SubscriptionId: subscription.Id,
+			EventType:      eventType,
+			Payload:        string(payload),
+			Status:         models.WebhookDeliveryPending,
+			CreatedAt:      now,
+		}
+		if err := saveWebhookDelivery(c, pipe, delivery, now); err != nil {
+			return err
+		}
+		logKey := webhookDeliveryLogKeyPrefix + subscription.Id
+		pipe.LPush(c, logKey, deliveryId)
+		pipe.LTrim(c, logKey, 0, deliveryLogSize-1)
+	}
+	return nil
+}
+
+func saveWebhookDelivery(c context.Context, pipe redis.Pipeliner, delivery models.WebhookDelivery, sendAt time.Time) error {
+	dataAsJsonString, err := json.Marshal(delivery)
+	if err != nil {
+		log.Logger.Error().Msgf("error converting webhook delivery to json: %v", err)
+		return newError(ErrInvalid, "save webhook delivery", delivery.Id, err)
+	}
+	// delivery documents expire after a month, expired entries are skipped when reading the delivery log
+	pipe.Set(c, webhookDeliveryKeyPrefix+delivery.Id, dataAsJsonString, 30*24*time.Hour)
+	if delivery.Status == models.WebhookDeliveryPending {
+		webhookQueue.add(c, pipe, delivery.Id, sendAt)
+	} else {
+		webhookQueue.remove(c, pipe, delivery.Id)
+	}
+	return nil
+}
+
+// SaveWebhookDelivery stores delivery, pending deliveries are (re)queued to be sent at `sendAt`.
+var SaveWebhookDelivery = func(c context.Context, delivery models.WebhookDelivery, sendAt time.Time) error {
+	_, err := redisClient.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		return saveWebhookDelivery(c, pipe, delivery, sendAt)
+	})
+	return redisError("save webhook delivery", delivery.Id, err)
+}
+
+var GetWebhookDelivery = func(c context.Context, id string) (models.WebhookDelivery, error) {
+	result, err := redisClient.Get(c, webhookDeliveryKeyPrefix+id).Result()
+	if err != nil {
+		return models.WebhookDelivery{}, redisError("get webhook delivery", id, err)
+	}
+	var delivery models.WebhookDelivery
+	jsonParseErr := json.Unmarshal([]byte(result), &delivery)
+	if jsonParseErr != nil {
+		return models.WebhookDelivery{}, newError(ErrInvalid, "get webhook delivery", id, jsonParseErr)
+	}
+	return delivery, nil
+}
+
+// GetWebhookDeliveries returns delivery log of subscription, newest first.
+var GetWebhookDeliveries = func(c context.Context, subscriptionId string) ([]models.WebhookDelivery, error) {
+	ids, err := redisClient.LRange(c, webhookDeliveryLogKeyPrefix+subscriptionId, 0, -1).Result()
+	if err != nil {
+		return nil, redisError("get webhook deliveries", subscriptionId, err)
+	}
+	deliveries := []models.WebhookDelivery{}
+	for _, id := range ids {
+		delivery, err := GetWebhookDelivery(c, id)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+// GetDueWebhookDeliveryIds returns ids of deliveries to be sent at `now`, oldest first.
+var GetDueWebhookDeliveryIds = func(c context.Context, now time.Time, limit int64) ([]string, error) {
+	return webhookQueue.due(c, now, limit)
+}
+
+var AcquireWebhookDeliveryLease = func(c context.Context, id string, owner string, ttl time.Duration) (bool, error) {
+	return webhookQueue.acquire(c, id, owner, ttl)
+}
+
+var ReleaseWebhookDeliveryLease = func(c context.Context, id string, owner string) error {
+	return webhookQueue.release(c, id, owner)
+}
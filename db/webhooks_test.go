@@ -0,0 +1,124 @@
+package db
+
+import (
+	"app/models"
+	"app/utils"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var webhookSubscriptionDataAsStruct = models.WebhookSubscriptionData{
+	Url:        "http://127.0.0.1/hooks",
+	Secret:     "a-long-random-secret",
+	EventTypes: []string{models.WebhookEventCreated, models.WebhookEventDeleted},
+}
+
+func TestWebhookSubscriptions(t *testing.T) {
+	t.Run("Created subscription can be read, listed and deleted", func(t *testing.T) {
+		setup()
+		defer teardown()
+
+		subscription, err := CreateWebhookSubscription(ctx, webhookSubscriptionDataAsStruct)
+		assert.Nil(t, err)
+		assert.Equal(t, webhookSubscriptionDataAsStruct, subscription.WebhookSubscriptionData)
+
+		stored, err := GetWebhookSubscription(ctx, subscription.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, subscription, stored)
+
+		subscriptions, err := GetWebhookSubscriptions(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, []models.WebhookSubscription{subscription}, subscriptions)
+
+		assert.Nil(t, DeleteWebhookSubscription(ctx, subscription.Id))
+		_, err = GetWebhookSubscription(ctx, subscription.Id)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.ErrorIs(t, DeleteWebhookSubscription(ctx, subscription.Id), ErrNotFound)
+	})
+}
+
+func getWebhookQueue(t *testing.T) []models.WebhookDelivery {
+	ids, err := GetDueWebhookDeliveryIds(ctx, time.Now().UTC().Add(time.Hour), 100)
+	assert.Nil(t, err)
+	deliveries := []models.WebhookDelivery{}
+	for _, id := range ids {
+		delivery, err := GetWebhookDelivery(ctx, id)
+		assert.Nil(t, err)
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries
+}
+
+func TestWebhookDeliveriesWrittenWithEventMutations(t *testing.T) {
+	utils.GetJsonStringFromStruct = originalGetJsonStringFromStruct
+
+	t.Run("CreateEvent enqueues delivery for subscribed event type", func(t *testing.T) {
+		setup()
+		defer teardown()
+		subscription, _ := CreateWebhookSubscription(ctx, webhookSubscriptionDataAsStruct)
+
+		id, err := CreateEvent(ctx, eventDataAsStruct)
+		assert.Nil(t, err)
+
+		queue := getWebhookQueue(t)
+		assert.Len(t, queue, 1)
+		assert.Equal(t, subscription.Id, queue[0].SubscriptionId)
+		assert.Equal(t, models.WebhookEventCreated, queue[0].EventType)
+		assert.Equal(t, models.WebhookDeliveryPending, queue[0].Status)
+		var payload models.WebhookPayload
+		assert.Nil(t, json.Unmarshal([]byte(queue[0].Payload), &payload))
+		assert.Equal(t, queue[0].Id, payload.Id)
+		assert.Equal(t, id, payload.Event.Id)
+		assert.Equal(t, eventDataAsStruct.Name, payload.Event.Name)
+
+		deliveryLog, err := GetWebhookDeliveries(ctx, subscription.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, queue, deliveryLog)
+	})
+
+	t.Run("UpdateEvent skips subscriptions without `event.updated`", func(t *testing.T) {
+		setup()
+		defer teardown()
+		_, _ = CreateWebhookSubscription(ctx, webhookSubscriptionDataAsStruct)
+		insertDataToCache(redisClient, []KeyValuePair{{key: "event-id-string", value: eventDataAsJsonString}})
+
+		assert.Nil(t, UpdateEvent(ctx, "event-id-string", eventDataAsStruct))
+		assert.Empty(t, getWebhookQueue(t))
+	})
+
+	t.Run("DeleteEvent enqueues `event.deleted` delivery", func(t *testing.T) {
+		setup()
+		defer teardown()
+		_, _ = CreateWebhookSubscription(ctx, webhookSubscriptionDataAsStruct)
+		insertDataToCache(redisClient, []KeyValuePair{{key: "event-id-string", value: eventDataAsJsonString}})
+
+		assert.Nil(t, DeleteEvent(ctx, "event-id-string"))
+
+		queue := getWebhookQueue(t)
+		assert.Len(t, queue, 1)
+		assert.Equal(t, models.WebhookEventDeleted, queue[0].EventType)
+	})
+}
+
+func TestSaveWebhookDelivery(t *testing.T) {
+	t.Run("Finished delivery leaves the queue", func(t *testing.T) {
+		setup()
+		defer teardown()
+		_, _ = CreateWebhookSubscription(ctx, webhookSubscriptionDataAsStruct)
+		utils.GetJsonStringFromStruct = originalGetJsonStringFromStruct
+		_, _ = CreateEvent(ctx, eventDataAsStruct)
+		delivery := getWebhookQueue(t)[0]
+
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.Attempts = 1
+		assert.Nil(t, SaveWebhookDelivery(ctx, delivery, time.Now().UTC()))
+
+		assert.Empty(t, getWebhookQueue(t))
+		stored, err := GetWebhookDelivery(ctx, delivery.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, delivery, stored)
+	})
+}
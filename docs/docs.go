@@ -18,8 +18,1537 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/audit": {
+            "get": {
+                "description": "` + "`" + `from` + "`" + ` and ` + "`" + `to` + "`" + ` are inclusive. Whole range is exported as NDJSON with ` + "`" + `format=ndjson` + "`" + `\nor ` + "`" + `Accept: application/x-ndjson` + "`" + `, ` + "`" + `limit` + "`" + ` does not apply then.",
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Lists audit trail of data changes and failed authentications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 timestamp",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 timestamp",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "max number of entries (1-1000, default 100 unless ` + "`" + `limits` + "`" + ` are configured)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "` + "`" + `ndjson` + "`" + ` for export",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AuditEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/changes": {
+            "get": {
+                "description": "` + "`" + `since` + "`" + ` is exclusive when it is id of a change, inclusive when it is a timestamp.\nNext page is requested with id of the last returned change.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Event"
+                ],
+                "summary": "Lists domain events recorded for every event mutation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "change id or RFC 3339 timestamp",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "max number of changes (1-1000, default 100 unless ` + "`" + `limits` + "`" + ` are configured)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.DomainEvent"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/log-level": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Shows levels logs are written at",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LogLevels"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Changes global or package log level until restart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Log level",
+                        "name": "level",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LogLevelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LogLevels"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/log-level/{package}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Makes package log at global level again",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "package name",
+                        "name": "package",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LogLevels"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/qualities": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Lists quality profiles events may use, video first, by bitrate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.QualityProfile"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Adds quality profile to the catalog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Quality profile",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.QualityProfile"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.QualityProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/qualities/{kind}/{name}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Retrieves quality profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "video or audio",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "profile name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.QualityProfile"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "` + "`" + `kind` + "`" + ` and ` + "`" + `name` + "`" + ` may be left out of the body, they cannot be changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Replaces attributes of quality profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "video or audio",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "profile name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Quality profile",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.QualityProfile"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.QualityProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Existing events keep the quality, new events cannot use it.",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Removes quality profile from the catalog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "video or audio",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "profile name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tenants/{tenant}/qualities": {
+            "get": {
+                "description": "Empty list means every profile of the kind is enabled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Shows qualities tenant may use",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "tenant sent in X-Tenant-ID header",
+                        "name": "tenant",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TenantQualities"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Empty list enables every profile of the kind, including ones added later.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Changes qualities tenant may use",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "tenant sent in X-Tenant-ID header",
+                        "name": "tenant",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Enabled qualities",
+                        "name": "qualities",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TenantQualities"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TenantQualities"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Lets tenant use every quality profile again",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "tenant sent in X-Tenant-ID header",
+                        "name": "tenant",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
         "/event": {
             "post": {
+                "description": "Errors are returned as RFC 7807 problem details, with failed validations per field,\nwhen ` + "`" + `Accept` + "`" + ` header has ` + "`" + `application/problem+json` + "`" + `.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Event"
+                ],
+                "summary": "Creates event to database",
+                "parameters": [
+                    {
+                        "description": "Event Data",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.EventData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventResponseData"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/event/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Event"
+                ],
+                "summary": "Retrieves event from database",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventResponseData"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Event"
+                ],
+                "summary": "Delete event from database",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event ID (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/event/{id}/access": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Live"
+                ],
+                "summary": "Issues token granting invitee access to event's live channel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event ID (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Invitee",
+                        "name": "access",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.EventAccessRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventAccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/event/{id}/cancel": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Event"
+                ],
+                "summary": "Moves ` + "`" + `draft` + "`" + `, ` + "`" + `scheduled` + "`" + ` or ` + "`" + `live` + "`" + ` event to ` + "`" + `cancelled` + "`" + `",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event ID (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventResponseData"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/event/{id}/end": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Event"
+                ],
+                "summary": "Moves event from ` + "`" + `live` + "`" + ` to ` + "`" + `ended` + "`" + `",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event ID (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventResponseData"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/event/{id}/manifest.mpd": {
+            "get": {
+                "description": "Bandwidth, resolution and codecs come from the quality catalog, segment duration follows\nlatency mode. Stream URLs are configured by ` + "`" + `streams` + "`" + ` settings.",
+                "produces": [
+                    "application/dash+xml"
+                ],
+                "tags": [
+                    "Event"
+                ],
+                "summary": "Generates live MPEG-DASH manifest of event renditions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/event/{id}/master.m3u8": {
+            "get": {
+                "description": "Variants pair every video quality with every audio quality, bandwidth, resolution and codecs\ncome from the quality catalog. Stream URLs are configured by ` + "`" + `streams` + "`" + ` settings.",
+                "produces": [
+                    "application/vnd.apple.mpegurl"
+                ],
+                "tags": [
+                    "Event"
+                ],
+                "summary": "Generates HLS master playlist of event renditions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/event/{id}/presence": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Live"
+                ],
+                "summary": "Lists invitees connected to event's live channel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event ID (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Presence"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/event/{id}/publish": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Event"
+                ],
+                "summary": "Moves event from ` + "`" + `draft` + "`" + ` to ` + "`" + `scheduled` + "`" + `",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event ID (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventResponseData"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/event/{id}/start": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Event"
+                ],
+                "summary": "Moves event from ` + "`" + `scheduled` + "`" + ` to ` + "`" + `live` + "`" + `",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event ID (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventResponseData"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/event/{id}/ws": {
+            "get": {
+                "description": "Invitees authenticate by ` + "`" + `email` + "`" + ` \u0026 ` + "`" + `token` + "`" + ` query (see ` + "`" + `POST /event/{id}/access` + "`" + `), organizers by admin token header.\nServer sends JSON messages ` + "`" + `{\"type\":\"announcement\",\"message\":\"...\",\"sentAt\":\"...\"}` + "`" + ` and ` + "`" + `{\"type\":\"presence\",\"count\":1}` + "`" + `, organizers also receive ` + "`" + `viewers` + "`" + `.\nOrganizers broadcast by sending ` + "`" + `{\"type\":\"announcement\",\"message\":\"...\"}` + "`" + `.\nBrowsers may connect from pages of the service's own host or of origins allowed by ` + "`" + `LIVE_ALLOWED_ORIGINS` + "`" + `, others get 403.",
+                "tags": [
+                    "Live"
+                ],
+                "summary": "Opens WebSocket live channel of ` + "`" + `live` + "`" + ` event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event ID (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Invitee email",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Invitee access token",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/stream": {
+            "get": {
+                "description": "Emits ` + "`" + `created` + "`" + `, ` + "`" + `updated` + "`" + ` and ` + "`" + `deleted` + "`" + ` messages, reconnecting clients resume after ` + "`" + `Last-Event-ID` + "`" + `.\nInvitees are only included for callers with admin token.\nEvents are not scoped to tenants, the stream includes events of every tenant.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Event"
+                ],
+                "summary": "Streams event changes as Server-Sent Events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id of the last received message",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated message types to receive (created,updated,deleted), unknown types are rejected",
+                        "name": "types",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthcheck": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health check"
+                ],
+                "summary": "Liveness check reporting build, does not check dependencies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.JsonHealthCheckStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Scheduler"
+                ],
+                "summary": "Lists jobs waiting in scheduler queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Job"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Scheduler"
+                ],
+                "summary": "Retrieves status of scheduler job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/livez": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health check"
+                ],
+                "summary": "Liveness probe, does not check dependencies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Health"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/dead": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Lists notifications which ran out of delivery attempts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Notification"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Results are cached for 5s. Failing non-critical check makes status ` + "`" + `degraded` + "`" + ` but still ready.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health check"
+                ],
+                "summary": "Readiness probe, checks Redis, scheduler, outbox and webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Health"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Health"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Lists webhook subscriptions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WebhookSubscription"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Deliveries are signed by ` + "`" + `X-Signature-256: sha256=\u003chex HMAC-SHA256 of body keyed by secret\u003e` + "`" + `.",
                 "consumes": [
                     "application/json"
                 ],
@@ -27,17 +1556,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Event"
+                    "Webhooks"
                 ],
-                "summary": "Creates event to database",
+                "summary": "Subscribes URL to event lifecycle changes",
                 "parameters": [
                     {
-                        "description": "Event Data",
-                        "name": "event",
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Subscription Data",
+                        "name": "subscription",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.EventData"
+                            "$ref": "#/definitions/models.WebhookSubscriptionData"
                         }
                     }
                 ],
@@ -45,7 +1581,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.EventResponseData"
+                            "$ref": "#/definitions/models.WebhookSubscription"
                         }
                     },
                     "400": {
@@ -63,19 +1599,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/event/{id}": {
+        "/webhooks/{id}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Event"
+                    "Webhooks"
                 ],
-                "summary": "Retrieves event from database",
+                "summary": "Retrieves webhook subscription",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Event ID (uuid)",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (uuid)",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -85,7 +1628,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.EventResponseData"
+                            "$ref": "#/definitions/models.WebhookSubscription"
                         }
                     },
                     "404": {
@@ -104,9 +1647,9 @@ const docTemplate = `{
             },
             "delete": {
                 "tags": [
-                    "Event"
+                    "Webhooks"
                 ],
-                "summary": "Delete event from database",
+                "summary": "Deletes webhook subscription",
                 "parameters": [
                     {
                         "type": "string",
@@ -117,7 +1660,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Event ID (uuid)",
+                        "description": "Subscription ID (uuid)",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -127,6 +1670,12 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content"
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -136,20 +1685,105 @@ const docTemplate = `{
                 }
             }
         },
-        "/healthcheck": {
+        "/webhooks/{id}/deliveries": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Health check"
+                    "Webhooks"
+                ],
+                "summary": "Lists latest deliveries of webhook subscription, newest first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Checks health of this service",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.JsonHealthCheckStatus"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{deliveryId}/redeliver": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Queues webhook delivery to be sent again",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token string value",
+                        "name": "API-AUTHENTICATION",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (uuid)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery ID (uuid)",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookDelivery"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/weberrors.AppError"
                         }
                     }
                 }
@@ -157,8 +1791,126 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "models.AudioTrack": {
+            "type": "object",
+            "required": [
+                "language",
+                "qualities"
+            ],
+            "properties": {
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "qualities": {
+                    "type": "array",
+                    "minItems": 1,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Low",
+                        "Mid"
+                    ]
+                }
+            }
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "event.delete"
+                },
+                "actor": {
+                    "type": "string",
+                    "example": "admin"
+                },
+                "afterHash": {
+                    "type": "string"
+                },
+                "beforeHash": {
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "clientIp": {
+                    "type": "string",
+                    "example": "192.0.2.10"
+                },
+                "correlationId": {
+                    "type": "string",
+                    "example": "0b8a1f5e-4c1d-4f0e-9a57-1e6f4d7e2c11"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1682000000000-0"
+                },
+                "occurredAt": {
+                    "type": "string",
+                    "example": "2023-04-20T13:45:00Z"
+                },
+                "targetId": {
+                    "type": "string",
+                    "example": "5b8d3c5e-2d0a-4a8f-8b57-7c1e2a3b4c5d"
+                }
+            }
+        },
+        "models.DomainEvent": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "admin"
+                },
+                "correlationId": {
+                    "type": "string",
+                    "example": "0b8a1f5e-4c1d-4f0e-9a57-1e6f4d7e2c11"
+                },
+                "event": {
+                    "$ref": "#/definitions/models.EventResponseData"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1682000000000-0"
+                },
+                "occurredAt": {
+                    "type": "string",
+                    "example": "2023-04-20T13:45:00Z"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "EventCreated"
+                }
+            }
+        },
+        "models.EventAccess": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "valid-email@mail.com"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "5f2b8c..."
+                }
+            }
+        },
+        "models.EventAccessRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "valid-email@mail.com"
+                }
+            }
+        },
         "models.EventData": {
-            "description": "If not provided, ` + "`" + `videoQuality` + "`" + ` \u0026 ` + "`" + `audioQuality` + "`" + ` default to ` + "`" + `[\"720p\"]` + "`" + ` \u0026 ` + "`" + `[\"Low\"]` + "`" + `, respectively. If provided, first item in the list is event's default quality.",
+            "description": "If not provided, ` + "`" + `videoQuality` + "`" + ` \u0026 ` + "`" + `audioQuality` + "`" + ` default to ` + "`" + `[\"720p\"]` + "`" + ` \u0026 ` + "`" + `[\"Low\"]` + "`" + `, respectively. If provided, first item in the list is event's default quality. ` + "`" + `streaming` + "`" + ` is derived from them if not provided, otherwise they are derived from ` + "`" + `streaming` + "`" + `.",
             "type": "object",
             "required": [
                 "date",
@@ -201,6 +1953,7 @@ const docTemplate = `{
                     ]
                 },
                 "languages": {
+                    "description": "BCP 47 tags, English or native language names are accepted and stored as tags",
                     "type": "array",
                     "minItems": 1,
                     "uniqueItems": true,
@@ -208,8 +1961,8 @@ const docTemplate = `{
                         "type": "string"
                     },
                     "example": [
-                        "English",
-                        "French"
+                        "en",
+                        "fr"
                     ]
                 },
                 "name": {
@@ -219,6 +1972,14 @@ const docTemplate = `{
                     "minLength": 1,
                     "example": "A event-Name3_x"
                 },
+                "status": {
+                    "description": "read-only, new events always start as ` + "`" + `draft` + "`" + ` (draft, scheduled, live, ended, cancelled)",
+                    "type": "string",
+                    "example": "draft"
+                },
+                "streaming": {
+                    "$ref": "#/definitions/models.StreamingConfig"
+                },
                 "videoQuality": {
                     "type": "array",
                     "uniqueItems": true,
@@ -280,7 +2041,19 @@ const docTemplate = `{
                         "example@mail.com"
                     ]
                 },
+                "languageNames": {
+                    "description": "names of ` + "`" + `languages` + "`" + ` in locale of ` + "`" + `Accept-Language` + "`" + ` header, English by default",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "en": "English",
+                        "fr": "French"
+                    }
+                },
                 "languages": {
+                    "description": "BCP 47 tags, English or native language names are accepted and stored as tags",
                     "type": "array",
                     "minItems": 1,
                     "uniqueItems": true,
@@ -288,8 +2061,8 @@ const docTemplate = `{
                         "type": "string"
                     },
                     "example": [
-                        "English",
-                        "French"
+                        "en",
+                        "fr"
                     ]
                 },
                 "name": {
@@ -299,6 +2072,14 @@ const docTemplate = `{
                     "minLength": 1,
                     "example": "A event-Name3_x"
                 },
+                "status": {
+                    "description": "read-only, new events always start as ` + "`" + `draft` + "`" + ` (draft, scheduled, live, ended, cancelled)",
+                    "type": "string",
+                    "example": "draft"
+                },
+                "streaming": {
+                    "$ref": "#/definitions/models.StreamingConfig"
+                },
                 "videoQuality": {
                     "type": "array",
                     "uniqueItems": true,
@@ -314,6 +2095,76 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Health": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HealthCheck"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "models.HealthCheck": {
+            "type": "object",
+            "properties": {
+                "critical": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "error": {
+                    "type": "string"
+                },
+                "latencyMs": {
+                    "type": "number",
+                    "example": 0.42
+                },
+                "name": {
+                    "type": "string",
+                    "example": "redis"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "models.Job": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "eventId": {
+                    "type": "string",
+                    "example": "db6bed50-7172-4051-86ab-d1e90705c692"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "reminder-db6bed50-7172-4051-86ab-d1e90705c692"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "runAt": {
+                    "type": "string",
+                    "example": "2023-04-20T13:45:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "reminder"
+                }
+            }
+        },
         "models.JsonHealthCheckStatus": {
             "type": "object",
             "properties": {
@@ -328,14 +2179,388 @@ const docTemplate = `{
                 }
             }
         },
+        "models.LogLevelRequest": {
+            "type": "object",
+            "required": [
+                "level"
+            ],
+            "properties": {
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "trace",
+                        "debug",
+                        "info",
+                        "warn",
+                        "error",
+                        "disabled"
+                    ],
+                    "example": "debug"
+                },
+                "package": {
+                    "description": "Package is package of the service, e.g. ` + "`" + `db` + "`" + `, global level is changed if it is empty",
+                    "type": "string",
+                    "example": "db"
+                }
+            }
+        },
+        "models.LogLevels": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "type": "string",
+                    "example": "info"
+                },
+                "packages": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.Notification": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2023-04-20T13:45:00Z"
+                },
+                "event": {
+                    "$ref": "#/definitions/models.EventResponseData"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0b1d34a7-8bcd-47f3-8923-d472510d8da4"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "event.created"
+                },
+                "lastError": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Presence": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "viewers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "valid-email@mail.com"
+                    ]
+                }
+            }
+        },
+        "models.QualityProfile": {
+            "description": "` + "`" + `width` + "`" + ` \u0026 ` + "`" + `height` + "`" + ` are required for video, ` + "`" + `channels` + "`" + ` \u0026 ` + "`" + `sampleRateHz` + "`" + ` for audio profiles.",
+            "type": "object",
+            "required": [
+                "bitrateKbps",
+                "codec",
+                "kind",
+                "name"
+            ],
+            "properties": {
+                "bitrateKbps": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 6000
+                },
+                "channels": {
+                    "type": "integer",
+                    "maximum": 16,
+                    "minimum": 0,
+                    "example": 2
+                },
+                "codec": {
+                    "description": "RFC 6381 codec string as used in HLS ` + "`" + `CODECS` + "`" + ` and DASH ` + "`" + `codecs` + "`" + `",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "avc1.640028"
+                },
+                "height": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1080
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "video",
+                        "audio"
+                    ],
+                    "example": "video"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "1080p"
+                },
+                "sampleRateHz": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 48000
+                },
+                "width": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1920
+                }
+            }
+        },
+        "models.StreamingConfig": {
+            "type": "object",
+            "required": [
+                "defaultRendition",
+                "ladder"
+            ],
+            "properties": {
+                "audioTracks": {
+                    "description": "one track per language, defaults to a track of ` + "`" + `audioQuality` + "`" + ` for every event language",
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/models.AudioTrack"
+                    }
+                },
+                "defaultRendition": {
+                    "type": "string",
+                    "example": "720p"
+                },
+                "ladder": {
+                    "description": "adaptive bitrate ladder, video qualities player switches between",
+                    "type": "array",
+                    "minItems": 1,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "720p",
+                        "1080p"
+                    ]
+                },
+                "latencyMode": {
+                    "description": "standard, low or ultra-low, defaults to standard",
+                    "type": "string",
+                    "enum": [
+                        "standard",
+                        "low",
+                        "ultra-low"
+                    ],
+                    "example": "standard"
+                },
+                "subtitles": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/models.SubtitleTrack"
+                    }
+                }
+            }
+        },
+        "models.SubtitleTrack": {
+            "type": "object",
+            "required": [
+                "language"
+            ],
+            "properties": {
+                "format": {
+                    "description": "webvtt or ttml, defaults to webvtt",
+                    "type": "string",
+                    "enum": [
+                        "webvtt",
+                        "ttml"
+                    ],
+                    "example": "webvtt"
+                },
+                "language": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "fr"
+                }
+            }
+        },
+        "models.TenantQualities": {
+            "type": "object",
+            "properties": {
+                "audio": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Low",
+                        "Mid"
+                    ]
+                },
+                "video": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "720p",
+                        "1080p"
+                    ]
+                }
+            }
+        },
+        "models.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2023-04-20T13:45:00Z"
+                },
+                "deliveredAt": {
+                    "type": "string",
+                    "example": "2023-04-20T13:45:01Z"
+                },
+                "eventType": {
+                    "type": "string",
+                    "example": "event.created"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "90a04b08-d820-4106-8ced-2cbc940728a3"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "responseStatus": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "status": {
+                    "type": "string",
+                    "example": "delivered"
+                },
+                "subscriptionId": {
+                    "type": "string",
+                    "example": "0b1d34a7-8bcd-47f3-8923-d472510d8da4"
+                }
+            }
+        },
+        "models.WebhookSubscription": {
+            "type": "object",
+            "required": [
+                "eventTypes",
+                "secret",
+                "url"
+            ],
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2023-04-20T13:45:00Z"
+                },
+                "eventTypes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "event.created",
+                        "event.deleted"
+                    ]
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0b1d34a7-8bcd-47f3-8923-d472510d8da4"
+                },
+                "secret": {
+                    "description": "used as HMAC-SHA256 key of ` + "`" + `X-Signature-256` + "`" + ` header, never returned",
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 16,
+                    "example": "a-long-random-secret"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/events"
+                }
+            }
+        },
+        "models.WebhookSubscriptionData": {
+            "type": "object",
+            "required": [
+                "eventTypes",
+                "secret",
+                "url"
+            ],
+            "properties": {
+                "eventTypes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "event.created",
+                        "event.deleted"
+                    ]
+                },
+                "secret": {
+                    "description": "used as HMAC-SHA256 key of ` + "`" + `X-Signature-256` + "`" + ` header, never returned",
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 16,
+                    "example": "a-long-random-secret"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/events"
+                }
+            }
+        },
         "weberrors.AppError": {
             "type": "object",
             "properties": {
+                "additionalErrors": {
+                    "description": "other errors of the request, when it failed for more reasons",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/weberrors.AppError"
+                    }
+                },
+                "correlationId": {
+                    "description": "id of the request, to be quoted in error reports",
+                    "type": "string",
+                    "example": "0b8a1f5e-4c1d-4f0e-9a57-1e6f4d7e2c11"
+                },
                 "description": {
                     "type": "string"
                 },
                 "error": {
                     "type": "string"
+                },
+                "errorCode": {
+                    "description": "stable code of the error, clients may localize descriptions by it",
+                    "type": "string",
+                    "example": "not_found"
                 }
             }
         }
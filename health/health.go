@@ -0,0 +1,178 @@
+package health
+
+import (
+	"app/models"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOk       = "ok"
+	StatusDegraded = "degraded"
+	StatusFailing  = "failing"
+)
+
+const defaultTimeout = 2 * time.Second
+
+// cacheTTL is how long results are reused, so that probes of every replica do not hammer dependencies
+var cacheTTL = 5 * time.Second
+
+// Check returns error if the dependency cannot be used, it should give up when `c` is done.
+type Check func(c context.Context) error
+
+type registration struct {
+	check   Check
+	timeout time.Duration
+	// failure of a critical check makes the service not ready
+	critical bool
+}
+
+var registry = struct {
+	mutex  sync.Mutex
+	checks map[string]registration
+	cached *models.Health
+	// checkedAt is when cached results were computed
+	checkedAt time.Time
+}{checks: map[string]registration{}}
+
+// Register adds check run by readiness probe, check of the same name is replaced.
+// Zero `timeout` means the default of 2s.
+func Register(name string, critical bool, timeout time.Duration, check Check) {
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	registry.checks[name] = registration{check: check, timeout: timeout, critical: critical}
+	registry.cached = nil
+}
+
+// Unregister removes check, e.g. when the subsystem it checks is stopped.
+func Unregister(name string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	delete(registry.checks, name)
+	registry.cached = nil
+}
+
+var draining = make(chan struct{})
+var drainOnce sync.Once
+
+// Drain makes readiness fail from now on, so that load balancers stop routing to the service before it stops.
+func Drain() {
+	drainOnce.Do(func() { close(draining) })
+}
+
+// Draining is closed when shutdown starts, long-lived requests like streams should end then.
+func Draining() <-chan struct{} {
+	return draining
+}
+
+func isDraining() bool {
+	select {
+	case <-draining:
+		return true
+	default:
+		return false
+	}
+}
+
+// Readiness runs registered checks in parallel, results are cached for a few seconds.
+// Status is `failing` if a critical check fails, `degraded` if any other does, and always after Drain.
+func Readiness(c context.Context) models.Health {
+	if isDraining() {
+		return models.Health{Status: StatusFailing, Checks: []models.HealthCheck{{
+			Name:     "shutdown",
+			Status:   StatusFailing,
+			Critical: true,
+			Error:    "service is shutting down",
+		}}}
+	}
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	if registry.cached != nil && time.Since(registry.checkedAt) < cacheTTL {
+		return *registry.cached
+	}
+	results := make([]models.HealthCheck, 0, len(registry.checks))
+	resultsMutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for name, registered := range registry.checks {
+		wg.Add(1)
+		go func(name string, registered registration) {
+			defer wg.Done()
+			result := run(c, name, registered)
+			resultsMutex.Lock()
+			results = append(results, result)
+			resultsMutex.Unlock()
+		}(name, registered)
+	}
+	wg.Wait()
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+
+	health := models.Health{Status: StatusOk, Checks: results}
+	for _, result := range results {
+		if result.Status == StatusOk {
+			continue
+		}
+		if result.Critical {
+			health.Status = StatusFailing
+			break
+		}
+		health.Status = StatusDegraded
+	}
+	registry.cached = &health
+	registry.checkedAt = time.Now()
+	return health
+}
+
+// run gives up waiting after timeout even if the check ignores its context.
+func run(c context.Context, name string, registered registration) models.HealthCheck {
+	c, cancel := context.WithTimeout(c, registered.timeout)
+	defer cancel()
+	startTime := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- registered.check(c)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-c.Done():
+		err = fmt.Errorf("timed out after %v", registered.timeout)
+	}
+	result := models.HealthCheck{
+		Name:      name,
+		Status:    StatusOk,
+		Critical:  registered.critical,
+		LatencyMs: float64(time.Since(startTime).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFailing
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Heartbeat tracks that a background loop keeps running.
+type Heartbeat struct {
+	last atomic.Int64
+}
+
+func (h *Heartbeat) Beat(now time.Time) {
+	h.last.Store(now.UnixNano())
+}
+
+// Check fails when the loop has not beaten for longer than `maxAge`.
+func (h *Heartbeat) Check(maxAge time.Duration) Check {
+	return func(c context.Context) error {
+		last := time.Unix(0, h.last.Load())
+		if age := time.Since(last); age > maxAge {
+			return fmt.Errorf("last run %v ago", age.Truncate(time.Millisecond))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,155 @@
+package health
+
+import (
+	"app/models"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func reset(t *testing.T) {
+	registry.checks = map[string]registration{}
+	registry.cached = nil
+	t.Cleanup(func() {
+		registry.checks = map[string]registration{}
+		registry.cached = nil
+	})
+}
+
+func healthy(c context.Context) error {
+	return nil
+}
+
+func failing(c context.Context) error {
+	return errors.New("connection refused")
+}
+
+var ReadinessTestCases = []struct {
+	description    string
+	critical       Check
+	nonCritical    Check
+	expectedStatus string
+}{
+	{"All checks pass", healthy, healthy, StatusOk},
+	{"Non-critical check fails", healthy, failing, StatusDegraded},
+	{"Critical check fails", failing, healthy, StatusFailing},
+	{"All checks fail", failing, failing, StatusFailing},
+}
+
+func TestReadiness(t *testing.T) {
+	for _, testCase := range ReadinessTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			reset(t)
+			Register("redis", true, 0, testCase.critical)
+			Register("outbox", false, 0, testCase.nonCritical)
+
+			result := Readiness(context.Background())
+
+			assert.Equal(t, testCase.expectedStatus, result.Status)
+			assert.Len(t, result.Checks, 2)
+			assert.Equal(t, "outbox", result.Checks[0].Name, "should sort checks by name")
+			assert.False(t, result.Checks[0].Critical)
+			assert.Equal(t, "redis", result.Checks[1].Name)
+			assert.True(t, result.Checks[1].Critical)
+		})
+	}
+
+	t.Run("Failed check reports error", func(t *testing.T) {
+		reset(t)
+		Register("redis", true, 0, failing)
+
+		result := Readiness(context.Background())
+
+		assert.Equal(t, []models.HealthCheck{{
+			Name:      "redis",
+			Status:    StatusFailing,
+			Critical:  true,
+			LatencyMs: result.Checks[0].LatencyMs,
+			Error:     "connection refused",
+		}}, result.Checks)
+	})
+
+	t.Run("Check exceeding timeout fails", func(t *testing.T) {
+		reset(t)
+		Register("redis", true, 10*time.Millisecond, func(c context.Context) error {
+			time.Sleep(100 * time.Millisecond)
+			return nil
+		})
+
+		startTime := time.Now()
+		result := Readiness(context.Background())
+
+		assert.Less(t, time.Since(startTime), 100*time.Millisecond, "should not wait for check ignoring timeout")
+		assert.Equal(t, StatusFailing, result.Status)
+		assert.Equal(t, "timed out after 10ms", result.Checks[0].Error)
+		assert.GreaterOrEqual(t, result.Checks[0].LatencyMs, float64(10))
+	})
+
+	t.Run("Results are cached", func(t *testing.T) {
+		reset(t)
+		calls := 0
+		Register("redis", true, 0, func(c context.Context) error {
+			calls++
+			return nil
+		})
+
+		Readiness(context.Background())
+		Readiness(context.Background())
+		assert.Equal(t, 1, calls)
+
+		originalCacheTTL := cacheTTL
+		cacheTTL = 0
+		defer func() { cacheTTL = originalCacheTTL }()
+		Readiness(context.Background())
+		assert.Equal(t, 2, calls, "should run checks again when cache expires")
+	})
+
+	t.Run("Unregistered check is not run", func(t *testing.T) {
+		reset(t)
+		Register("scheduler", false, 0, failing)
+		Unregister("scheduler")
+
+		result := Readiness(context.Background())
+
+		assert.Equal(t, StatusOk, result.Status)
+		assert.Empty(t, result.Checks)
+	})
+}
+
+func TestHeartbeat(t *testing.T) {
+	heartbeat := &Heartbeat{}
+	check := heartbeat.Check(time.Minute)
+	assert.Error(t, check(context.Background()), "should fail before first beat")
+
+	heartbeat.Beat(time.Now())
+	assert.Nil(t, check(context.Background()))
+
+	heartbeat.Beat(time.Now().Add(-2 * time.Minute))
+	assert.ErrorContains(t, check(context.Background()), "last run 2m0")
+}
+
+func TestDrain(t *testing.T) {
+	reset(t)
+	defer func() {
+		draining = make(chan struct{})
+		drainOnce = sync.Once{}
+	}()
+	Register("redis", true, 0, healthy)
+	assert.Equal(t, StatusOk, Readiness(context.Background()).Status)
+
+	Drain()
+	Drain()
+
+	result := Readiness(context.Background())
+	assert.Equal(t, StatusFailing, result.Status, "should fail even with cached healthy results")
+	assert.Equal(t, "service is shutting down", result.Checks[0].Error)
+	select {
+	case <-Draining():
+	default:
+		assert.Fail(t, "should signal long-lived requests to end")
+	}
+}
@@ -0,0 +1,77 @@
+package lifecycle
+
+import (
+	"app/utils"
+	"errors"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+const (
+	Draft     = "draft"
+	Scheduled = "scheduled"
+	Live      = "live"
+	Ended     = "ended"
+	Cancelled = "cancelled"
+)
+
+const (
+	ActionPublish = "publish"
+	ActionStart   = "start"
+	ActionEnd     = "end"
+	ActionCancel  = "cancel"
+)
+
+type transition struct {
+	From []string
+	To   string
+}
+
+// legal transitions of the event state machine, keyed by action
+var transitions = map[string]transition{
+	ActionPublish: {From: []string{Draft}, To: Scheduled},
+	ActionStart:   {From: []string{Scheduled}, To: Live},
+	ActionEnd:     {From: []string{Live}, To: Ended},
+	ActionCancel:  {From: []string{Draft, Scheduled, Live}, To: Cancelled},
+}
+
+var ErrInvalidTransition = errors.New("invalid transition")
+
+// Current returns status of the event, events stored before
+// the lifecycle was introduced have no status and count as scheduled.
+func Current(status string) string {
+	if status == "" {
+		return Scheduled
+	}
+	return status
+}
+
+// Transition returns the status reached by applying `action` to `status`.
+var Transition = func(status string, action string) (string, error) {
+	t, found := transitions[action]
+	if !found || !slices.Contains(t.From, Current(status)) {
+		return "", ErrInvalidTransition
+	}
+	return t.To, nil
+}
+
+// Resolve auto-transitions scheduled and live events based on their start time,
+// events are considered ended `utils.EVENT_DURATION` after they start.
+var Resolve = func(status string, timestamp string, now time.Time) string {
+	status = Current(status)
+	if status != Scheduled && status != Live {
+		return status
+	}
+	startTime, err := time.Parse(utils.TIMESTAMP_LAYOUT, timestamp)
+	if err != nil {
+		return status
+	}
+	switch {
+	case !now.Before(startTime.Add(utils.EVENT_DURATION)):
+		return Ended
+	case !now.Before(startTime):
+		return Live
+	}
+	return status
+}
@@ -0,0 +1,66 @@
+package lifecycle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var TransitionTestCases = []struct {
+	description    string
+	submitStatus   string
+	submitAction   string
+	expectedStatus string
+	expectedError  error
+}{
+	{"publish draft", Draft, ActionPublish, Scheduled, nil},
+	{"start scheduled", Scheduled, ActionStart, Live, nil},
+	{"start legacy event without status", "", ActionStart, Live, nil},
+	{"end live", Live, ActionEnd, Ended, nil},
+	{"cancel draft", Draft, ActionCancel, Cancelled, nil},
+	{"cancel live", Live, ActionCancel, Cancelled, nil},
+	{"Fail - publish live", Live, ActionPublish, "", ErrInvalidTransition},
+	{"Fail - end scheduled", Scheduled, ActionEnd, "", ErrInvalidTransition},
+	{"Fail - cancel ended", Ended, ActionCancel, "", ErrInvalidTransition},
+	{"Fail - start cancelled", Cancelled, ActionStart, "", ErrInvalidTransition},
+	{"Fail - unknown action", Draft, "archive", "", ErrInvalidTransition},
+}
+
+func TestTransition(t *testing.T) {
+	for _, testCase := range TransitionTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			status, err := Transition(testCase.submitStatus, testCase.submitAction)
+			assert.Equal(t, testCase.expectedError, err)
+			assert.Equal(t, testCase.expectedStatus, status)
+		})
+	}
+}
+
+var eventStart = time.Date(2023, 4, 20, 14, 0, 0, 0, time.UTC)
+
+var ResolveTestCases = []struct {
+	description     string
+	submitStatus    string
+	submitTimestamp string
+	submitNow       time.Time
+	expectedStatus  string
+}{
+	{"scheduled before start", Scheduled, "2023-04-20T14:00:00Z", eventStart.Add(-time.Minute), Scheduled},
+	{"scheduled at start", Scheduled, "2023-04-20T14:00:00Z", eventStart, Live},
+	{"scheduled after end", Scheduled, "2023-04-20T14:00:00Z", eventStart.Add(3 * time.Hour), Ended},
+	{"live after end", Live, "2023-04-20T14:00:00Z", eventStart.Add(3 * time.Hour), Ended},
+	{"legacy event without status", "", "2023-04-20T14:00:00Z", eventStart.Add(-time.Minute), Scheduled},
+	{"draft is not auto-transitioned", Draft, "2023-04-20T14:00:00Z", eventStart.Add(time.Minute), Draft},
+	{"cancelled is not auto-transitioned", Cancelled, "2023-04-20T14:00:00Z", eventStart.Add(time.Minute), Cancelled},
+	{"invalid timestamp", Scheduled, "invalid-time-string", eventStart, Scheduled},
+}
+
+func TestResolve(t *testing.T) {
+	for _, testCase := range ResolveTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			status := Resolve(testCase.submitStatus, testCase.submitTimestamp, testCase.submitNow)
+			assert.Equal(t, testCase.expectedStatus, status)
+		})
+	}
+}
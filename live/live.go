@@ -0,0 +1,195 @@
+package live
+
+import (
+	"app/db"
+	"app/health"
+	"app/metrics"
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	MessageAnnouncement = "announcement"
+	MessagePresence     = "presence"
+)
+
+const maxMessageSize = 4096
+
+var heartbeatInterval = 10 * time.Second
+
+// presenceTTL is how long a connection counts as watching without heartbeat
+var presenceTTL = 30 * time.Second
+
+type Message struct {
+	Type    string     `json:"type"`
+	Message string     `json:"message,omitempty"`
+	SentAt  *time.Time `json:"sentAt,omitempty"`
+	Count   int        `json:"count"`
+	Viewers []string   `json:"viewers,omitempty"`
+}
+
+type Viewer struct {
+	EventId string
+	// Identity is invitee email, empty for organizers
+	Identity  string
+	Organizer bool
+}
+
+// Viewers returns invitees currently watching the event, across all replicas.
+var Viewers = func(c context.Context, eventId string) ([]string, error) {
+	members, err := db.GetPresence(c, eventId, time.Now().UTC(), presenceTTL)
+	if err != nil {
+		return nil, err
+	}
+	viewers := []string{}
+	for _, member := range members {
+		identity := strings.SplitN(member, "#", 2)[0]
+		if !slices.Contains(viewers, identity) {
+			viewers = append(viewers, identity)
+		}
+	}
+	slices.Sort(viewers)
+	return viewers, nil
+}
+
+// Serve runs the live channel on `conn` until the client leaves.
+// Viewers receive announcements and viewer count, organizers can also announce and see who is watching.
+func Serve(conn *websocket.Conn, viewer Viewer) {
+	c, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer conn.Close()
+	logger := log.Logger.With().Str("event_id", viewer.EventId).Bool("organizer", viewer.Organizer).Logger()
+
+	messages, err := db.SubscribeLiveMessages(c, viewer.EventId)
+	if err != nil {
+		logger.Error().Msgf("error subscribing to live channel: %v", err)
+		return
+	}
+	metrics.LiveConnections.Inc()
+	defer metrics.LiveConnections.Dec()
+	member := viewer.Identity + "#" + uuid.NewString()
+	if !viewer.Organizer {
+		touchPresence(c, viewer.EventId, member)
+		defer func() {
+			if err := db.RemovePresence(c, viewer.EventId, member); err != nil {
+				logger.Error().Msgf("error removing presence: %v", err)
+			}
+		}()
+	}
+
+	readErr := make(chan error, 1)
+	go func() {
+		readErr <- read(c, conn, viewer)
+	}()
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	if !sendPresence(c, conn, viewer) {
+		return
+	}
+	for {
+		select {
+		case <-health.Draining():
+			deadline := time.Now().Add(heartbeatInterval)
+			err := conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseServiceRestart, "server is shutting down"), deadline)
+			if err != nil {
+				logger.Debug().Msgf("close frame not sent while draining: %v", err)
+			}
+			return
+		case err := <-readErr:
+			if err != nil && !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logger.Debug().Msgf("live channel closed: %v", err)
+			}
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			// connection is closed on return when the deadline cannot be set
+			if err := conn.SetWriteDeadline(time.Now().Add(heartbeatInterval)); err != nil {
+				return
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if !viewer.Organizer {
+				touchPresence(c, viewer.EventId, member)
+			}
+			deadline := time.Now().Add(heartbeatInterval)
+			if err := conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+				return
+			}
+			if !sendPresence(c, conn, viewer) {
+				return
+			}
+		}
+	}
+}
+
+// read handles client messages, only organizer announcements are acted upon.
+func read(c context.Context, conn *websocket.Conn, viewer Viewer) error {
+	conn.SetReadLimit(maxMessageSize)
+	if err := conn.SetReadDeadline(time.Now().Add(presenceTTL)); err != nil {
+		return err
+	}
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(presenceTTL))
+	})
+	for {
+		var message Message
+		if err := conn.ReadJSON(&message); err != nil {
+			if _, isJsonErr := err.(*json.SyntaxError); isJsonErr {
+				continue
+			}
+			return err
+		}
+		if !viewer.Organizer || message.Type != MessageAnnouncement || message.Message == "" {
+			continue
+		}
+		if err := Announce(c, viewer.EventId, message.Message); err != nil {
+			log.Logger.Error().Msgf("error publishing announcement of event `%v`: %v", viewer.EventId, err)
+		}
+	}
+}
+
+// Announce fans message out to every viewer connected to any replica.
+var Announce = func(c context.Context, eventId string, text string) error {
+	sentAt := time.Now().UTC()
+	message, err := json.Marshal(Message{Type: MessageAnnouncement, Message: text, SentAt: &sentAt})
+	if err != nil {
+		return err
+	}
+	return db.PublishLiveMessage(c, eventId, string(message))
+}
+
+func touchPresence(c context.Context, eventId string, member string) {
+	if err := db.TouchPresence(c, eventId, member, time.Now().UTC(), presenceTTL); err != nil {
+		log.Logger.Error().Msgf("error updating presence of event `%v`: %v", eventId, err)
+	}
+}
+
+// sendPresence sends viewer count, organizers also get the list of viewers.
+func sendPresence(c context.Context, conn *websocket.Conn, viewer Viewer) bool {
+	viewers, err := Viewers(c, viewer.EventId)
+	if err != nil {
+		log.Logger.Error().Msgf("error reading presence of event `%v`: %v", viewer.EventId, err)
+		return true
+	}
+	message := Message{Type: MessagePresence, Count: len(viewers)}
+	if viewer.Organizer {
+		message.Viewers = viewers
+	}
+	if err := conn.SetWriteDeadline(time.Now().Add(heartbeatInterval)); err != nil {
+		return false
+	}
+	return conn.WriteJSON(message) == nil
+}
@@ -0,0 +1,271 @@
+package logging
+
+import (
+	"app/config"
+	"app/utils"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+type Config struct {
+	// Format is `json` for log shippers or `console` for humans
+	Format string
+	Level  zerolog.Level
+	// PackageLevels overrides Level for packages of this module, e.g. `db` or `webhooks`
+	PackageLevels map[string]zerolog.Level
+	// SampleEvery keeps only every n-th successful request log, 0 and 1 keep all of them
+	SampleEvery uint32
+	Environment string
+	Version     string
+	Out         io.Writer
+}
+
+// NewConfig parses `settings`, `build` identifies the deployment in every log line.
+func NewConfig(settings config.Log, build config.Build) (Config, error) {
+	logConfig := Config{
+		Format:        settings.Format,
+		PackageLevels: map[string]zerolog.Level{},
+		SampleEvery:   settings.SampleEvery,
+		Environment:   build.Environment,
+		Version:       build.CommitTag,
+		Out:           os.Stderr,
+	}
+	var err error
+	if logConfig.Level, err = ParseLevel(settings.Level); err != nil {
+		return logConfig, err
+	}
+	for name, level := range settings.PackageLevels {
+		if logConfig.PackageLevels[name], err = ParseLevel(level); err != nil {
+			return logConfig, err
+		}
+	}
+	return logConfig, nil
+}
+
+// ParseLevel accepts zerolog level names, e.g. `debug` or `warn`.
+func ParseLevel(level string) (zerolog.Level, error) {
+	parsed, err := zerolog.ParseLevel(strings.ToLower(level))
+	if err != nil || level == "" {
+		return zerolog.NoLevel, fmt.Errorf("unknown log level `%v`", level)
+	}
+	return parsed, nil
+}
+
+// Configure replaces global logger, request loggers created afterwards use the new setup.
+func Configure(config Config) error {
+	var out io.Writer
+	switch config.Format {
+	case FormatJSON:
+		out = config.Out
+	case FormatConsole:
+		out = zerolog.ConsoleWriter{Out: config.Out}
+	default:
+		return fmt.Errorf("unknown log format `%v`", config.Format)
+	}
+	log.Logger = zerolog.New(redactingWriter{out: out}).
+		Hook(packageLevelHook{}).
+		With().Caller().
+		Str("environment", config.Environment).
+		Str("service", utils.APP_NAME).
+		Str("version", config.Version).
+		Timestamp().Logger()
+	levels.set(config.Level, config.PackageLevels)
+	atomic.StoreUint32(&requestSampleEvery, config.SampleEvery)
+	return nil
+}
+
+// Reconfigure applies levels and sampling of `settings` to the running logger,
+// levels changed at runtime through admin API are replaced.
+func Reconfigure(settings config.Log) error {
+	logConfig, err := NewConfig(settings, config.Build{})
+	if err != nil {
+		return err
+	}
+	levels.set(logConfig.Level, logConfig.PackageLevels)
+	atomic.StoreUint32(&requestSampleEvery, logConfig.SampleEvery)
+	return nil
+}
+
+// requestSampleEvery is read by Middleware for every request
+var requestSampleEvery uint32
+var requestCounter uint32
+
+// sampleRequest reports whether start and successful end of a request are logged,
+// failed requests are logged regardless.
+func sampleRequest() bool {
+	every := atomic.LoadUint32(&requestSampleEvery)
+	if every <= 1 {
+		return true
+	}
+	return (atomic.AddUint32(&requestCounter, 1)-1)%every == 0
+}
+
+// levelConfig holds levels changeable at runtime, zerolog global level is kept at the lowest of them
+// so that events of more verbose packages are created and filtered by packageLevelHook.
+type levelConfig struct {
+	mutex    sync.RWMutex
+	level    zerolog.Level
+	packages map[string]zerolog.Level
+	// highest of the levels, events at or above it are written whichever package logs them
+	highest zerolog.Level
+}
+
+var levels = &levelConfig{level: zerolog.InfoLevel, packages: map[string]zerolog.Level{}}
+
+func (l *levelConfig) set(level zerolog.Level, packages map[string]zerolog.Level) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.level = level
+	l.packages = map[string]zerolog.Level{}
+	for name, packageLevel := range packages {
+		l.packages[name] = packageLevel
+	}
+	l.updateGlobalLevel()
+}
+
+func (l *levelConfig) updateGlobalLevel() {
+	lowest, highest := l.level, l.level
+	for _, packageLevel := range l.packages {
+		if packageLevel < lowest {
+			lowest = packageLevel
+		}
+		if packageLevel > highest {
+			highest = packageLevel
+		}
+	}
+	l.highest = highest
+	zerolog.SetGlobalLevel(lowest)
+}
+
+func (l *levelConfig) forPackage(name string) zerolog.Level {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	if level, found := l.packages[name]; found {
+		return level
+	}
+	return l.level
+}
+
+func (l *levelConfig) hasPackageLevels() bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return len(l.packages) > 0
+}
+
+func (l *levelConfig) highestLevel() zerolog.Level {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.highest
+}
+
+// Levels returns global level and package overrides.
+func Levels() (zerolog.Level, map[string]zerolog.Level) {
+	levels.mutex.RLock()
+	defer levels.mutex.RUnlock()
+	packages := map[string]zerolog.Level{}
+	for name, level := range levels.packages {
+		packages[name] = level
+	}
+	return levels.level, packages
+}
+
+// SetLevel changes level of `packageName` at runtime, empty name changes global level.
+func SetLevel(packageName string, level zerolog.Level) {
+	levels.mutex.Lock()
+	defer levels.mutex.Unlock()
+	if packageName == "" {
+		levels.level = level
+	} else {
+		levels.packages[packageName] = level
+	}
+	levels.updateGlobalLevel()
+}
+
+// ResetLevel removes override of `packageName`, so it logs at global level again.
+func ResetLevel(packageName string) {
+	levels.mutex.Lock()
+	defer levels.mutex.Unlock()
+	delete(levels.packages, packageName)
+	levels.updateGlobalLevel()
+}
+
+const modulePrefix = "app/"
+
+// packageLevelHook discards events below level of the package that logged them.
+type packageLevelHook struct{}
+
+func (packageLevelHook) Run(e *zerolog.Event, level zerolog.Level, _ string) {
+	// walking the stack is costly, caller is resolved only when its package decides whether the event is written
+	if level == zerolog.NoLevel || level >= levels.highestLevel() {
+		return
+	}
+	packageName := ""
+	if levels.hasPackageLevels() {
+		packageName = callerPackage()
+	}
+	if level < levels.forPackage(packageName) {
+		e.Discard()
+	}
+}
+
+// callerPackage returns package of this module the log event comes from, empty for other packages.
+var callerPackage = func() string {
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		function := frame.Function
+		isLogging := strings.HasPrefix(function, modulePrefix+"logging.") && !strings.HasSuffix(frame.File, "_test.go")
+		if strings.HasPrefix(function, modulePrefix) && !isLogging {
+			name := strings.TrimPrefix(function, modulePrefix)
+			return name[:strings.IndexAny(name, ".")]
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// emailRegex matches addresses also when they are escaped in urls
+var emailRegex = regexp.MustCompile(`[A-Za-z0-9._%+-]+(?:@|%40)([A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+
+// secretRegex matches values of auth headers and tokens, both as JSON fields and in query strings
+var secretRegex = regexp.MustCompile(`(?i)("(?:authorization|` + regexp.QuoteMeta(utils.API_AUTH_HEADER_KEY) +
+	`|token|secret)"\s*:\s*")(?:[^"\\]|\\.)*"|([?&](?:token|secret)=)[^&"\s\\]*`)
+
+// Redact masks invitee emails and credentials in serialized log event before it is written.
+var Redact = func(p []byte) []byte {
+	p = emailRegex.ReplaceAll(p, []byte("***@$1"))
+	return secretRegex.ReplaceAllFunc(p, func(match []byte) []byte {
+		submatches := secretRegex.FindSubmatch(match)
+		if len(submatches[1]) > 0 {
+			return append(append([]byte{}, submatches[1]...), []byte(`***"`)...)
+		}
+		return append(append([]byte{}, submatches[2]...), []byte("***")...)
+	})
+}
+
+type redactingWriter struct {
+	out io.Writer
+}
+
+func (w redactingWriter) Write(p []byte) (int, error) {
+	if _, err := w.out.Write(Redact(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,218 @@
+package logging
+
+import (
+	"app/config"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func configureForTest(t *testing.T, config Config) *bytes.Buffer {
+	var buf bytes.Buffer
+	config.Out = &buf
+	assert.Nil(t, Configure(config))
+	t.Cleanup(func() {
+		Configure(Config{Format: FormatConsole, Level: zerolog.InfoLevel, Out: os.Stderr})
+	})
+	return &buf
+}
+
+func TestNewConfig(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		defaults := config.Default()
+		logConfig, err := NewConfig(defaults.Log, defaults.Build)
+		assert.Nil(t, err)
+		assert.Equal(t, FormatConsole, logConfig.Format)
+		assert.Equal(t, zerolog.InfoLevel, logConfig.Level)
+		assert.Empty(t, logConfig.PackageLevels)
+		assert.Zero(t, logConfig.SampleEvery)
+	})
+
+	t.Run("Configured", func(t *testing.T) {
+		logConfig, err := NewConfig(config.Log{
+			Format:        FormatJSON,
+			Level:         "WARN",
+			PackageLevels: map[string]string{"db": "debug", "webhooks": "error"},
+			SampleEvery:   10,
+		}, config.Build{Environment: "prod", CommitTag: "v1.2.3"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, FormatJSON, logConfig.Format)
+		assert.Equal(t, zerolog.WarnLevel, logConfig.Level)
+		assert.Equal(t, map[string]zerolog.Level{"db": zerolog.DebugLevel, "webhooks": zerolog.ErrorLevel},
+			logConfig.PackageLevels)
+		assert.Equal(t, uint32(10), logConfig.SampleEvery)
+		assert.Equal(t, "prod", logConfig.Environment)
+		assert.Equal(t, "v1.2.3", logConfig.Version)
+	})
+
+	t.Run("Fail - invalid levels", func(t *testing.T) {
+		_, err := NewConfig(config.Log{Format: FormatJSON, Level: "verbose"}, config.Build{})
+		assert.Error(t, err)
+		_, err = NewConfig(config.Log{Format: FormatJSON, Level: "info", PackageLevels: map[string]string{"db": "loud"}},
+			config.Build{})
+		assert.Error(t, err)
+	})
+
+	t.Run("Fail - unknown format", func(t *testing.T) {
+		assert.Error(t, Configure(Config{Format: "xml", Out: os.Stderr}))
+	})
+}
+
+func TestFormat(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		output := configureForTest(t, Config{Format: FormatJSON, Level: zerolog.InfoLevel})
+		log.Info().Str("field", "value").Msg("Testing")
+		assert.Contains(t, output.String(), `"field":"value"`)
+		assert.Contains(t, output.String(), `"message":"Testing"`)
+	})
+
+	t.Run("Console", func(t *testing.T) {
+		output := configureForTest(t, Config{Format: FormatConsole, Level: zerolog.InfoLevel})
+		log.Info().Str("field", "value").Msg("Testing")
+		assert.Contains(t, output.String(), "Testing")
+		assert.NotContains(t, output.String(), `"message"`)
+	})
+}
+
+func TestLevels(t *testing.T) {
+	t.Run("Package level overrides global level", func(t *testing.T) {
+		output := configureForTest(t, Config{
+			Format:        FormatJSON,
+			Level:         zerolog.WarnLevel,
+			PackageLevels: map[string]zerolog.Level{"logging": zerolog.DebugLevel},
+		})
+		log.Debug().Msg("Debug of package")
+		assert.Contains(t, output.String(), "Debug of package")
+
+		ResetLevel("logging")
+		log.Info().Msg("Info after reset")
+		assert.NotContains(t, output.String(), "Info after reset")
+	})
+
+	t.Run("Global level changed at runtime", func(t *testing.T) {
+		output := configureForTest(t, Config{Format: FormatJSON, Level: zerolog.InfoLevel})
+		log.Debug().Msg("Debug before")
+		SetLevel("", zerolog.DebugLevel)
+		log.Debug().Msg("Debug after")
+
+		assert.NotContains(t, output.String(), "Debug before")
+		assert.Contains(t, output.String(), "Debug after")
+		level, packages := Levels()
+		assert.Equal(t, zerolog.DebugLevel, level)
+		assert.Empty(t, packages)
+	})
+
+	t.Run("Other packages keep global level", func(t *testing.T) {
+		configureForTest(t, Config{
+			Format:        FormatJSON,
+			Level:         zerolog.InfoLevel,
+			PackageLevels: map[string]zerolog.Level{"db": zerolog.DebugLevel},
+		})
+		assert.Equal(t, zerolog.DebugLevel, zerolog.GlobalLevel(), "debug events of db have to be created")
+		assert.Equal(t, zerolog.InfoLevel, levels.forPackage(callerPackage()))
+	})
+
+	t.Run("Caller is resolved only for events its package decides about", func(t *testing.T) {
+		output := configureForTest(t, Config{
+			Format:        FormatJSON,
+			Level:         zerolog.InfoLevel,
+			PackageLevels: map[string]zerolog.Level{"db": zerolog.DebugLevel},
+		})
+		originalCallerPackage := callerPackage
+		defer func() { callerPackage = originalCallerPackage }()
+		lookups := 0
+		callerPackage = func() string {
+			lookups++
+			return originalCallerPackage()
+		}
+
+		log.Info().Msg("Info of any package")
+		log.Trace().Msg("Trace of any package")
+		assert.Equal(t, 0, lookups)
+		log.Debug().Msg("Debug of logging")
+		assert.Equal(t, 1, lookups)
+		assert.Contains(t, output.String(), "Info of any package")
+		assert.NotContains(t, output.String(), "Debug of logging")
+	})
+
+	t.Run("Reconfigured levels replace runtime changes", func(t *testing.T) {
+		output := configureForTest(t, Config{Format: FormatJSON, Level: zerolog.InfoLevel})
+		SetLevel("webhooks", zerolog.TraceLevel)
+
+		assert.Nil(t, Reconfigure(config.Log{Level: "warn", PackageLevels: map[string]string{"db": "debug"}}))
+		log.Info().Msg("Info after reload")
+
+		assert.NotContains(t, output.String(), "Info after reload")
+		level, packages := Levels()
+		assert.Equal(t, zerolog.WarnLevel, level)
+		assert.Equal(t, map[string]zerolog.Level{"db": zerolog.DebugLevel}, packages)
+		assert.Error(t, Reconfigure(config.Log{Level: "loud"}))
+	})
+}
+
+func TestRequestSampling(t *testing.T) {
+	output := configureForTest(t, Config{Format: FormatJSON, Level: zerolog.InfoLevel, SampleEvery: 3})
+	app := gin.New()
+	app.Use(Middleware())
+	app.GET("/ok", func(gctx *gin.Context) {})
+	app.GET("/fail", func(gctx *gin.Context) { gctx.Status(http.StatusInternalServerError) })
+
+	for i := 0; i < 6; i++ {
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	}
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	assert.Equal(t, 2, strings.Count(output.String(), `"route":"/ok"`)/2, "should log every 3rd request")
+	assert.Contains(t, output.String(), `"route":"/fail"`, "should log failed requests")
+}
+
+var redactTestCases = []struct {
+	description string
+	logged      string
+	expected    string
+}{
+	{
+		description: "Email",
+		logged:      `{"message":"invitee valid-email@mail.com joined"}`,
+		expected:    `{"message":"invitee ***@mail.com joined"}`,
+	},
+	{
+		description: "Email escaped in url",
+		logged:      `{"url":"/event/1/ws?email=valid-email%40mail.com"}`,
+		expected:    `{"url":"/event/1/ws?email=***@mail.com"}`,
+	},
+	{
+		description: "Token in url",
+		logged:      `{"url":"/event/1/ws?token=5f2b8c&x=1"}`,
+		expected:    `{"url":"/event/1/ws?token=***&x=1"}`,
+	},
+	{
+		description: "Auth headers",
+		logged:      `{"API-AUTHENTICATION":"admin-token","authorization":"Bearer \"x\"","other":"kept"}`,
+		expected:    `{"API-AUTHENTICATION":"***","authorization":"***","other":"kept"}`,
+	},
+}
+
+func TestRedact(t *testing.T) {
+	for _, testCase := range redactTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, string(Redact([]byte(testCase.logged))))
+		})
+	}
+
+	t.Run("Should redact before writing", func(t *testing.T) {
+		output := configureForTest(t, Config{Format: FormatJSON, Level: zerolog.InfoLevel})
+		log.Info().Str("email", "valid-email@mail.com").Msg("Testing")
+		assert.Contains(t, output.String(), `"email":"***@mail.com"`)
+		assert.NotContains(t, output.String(), "valid-email")
+	})
+}
@@ -1,16 +1,17 @@
 package logging
 
 import (
-	"app/utils"
+	"app/config"
 	"context"
 	"net/http"
-	"os"
+	"regexp"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -19,9 +20,43 @@ const (
 	requestStartedMessage   = "Request started"
 	requestEndedMessage     = "Request ended"
 	correlationIdContextKey = "correlationId"
+	actorContextKey         = "actor"
+	tenantContextKey        = "tenant"
+	clientIpContextKey      = "clientIp"
 	logKey                  = "log"
 )
 
+const (
+	RequestIdHeader     = "X-Request-ID"
+	CorrelationIdHeader = "X-Correlation-ID"
+	TenantHeader        = "X-Tenant-ID"
+)
+
+// incoming ids are accepted only if they are reasonably short and cannot break log or header formats
+var correlationIdRegex = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// TenantRegex matches tenant ids, they end up in storage fields and paths, so no separators or dots are allowed
+var TenantRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// correlationIdKey carries correlation id in contexts derived from request context
+type correlationIdKey struct{}
+
+// tenantKey carries tenant in contexts derived from request context
+type tenantKey struct{}
+
+// clientIpKey carries client ip in contexts derived from request context
+type clientIpKey struct{}
+
+// actorKey carries actor in contexts derived from request context
+type actorKey struct{}
+
+const (
+	// ActorAnonymous is the actor of requests without credentials
+	ActorAnonymous = "anonymous"
+	// ActorSystem is the actor of changes made outside of requests, e.g. by background workers
+	ActorSystem = "system"
+)
+
 func init() {
 	zerolog.LevelFieldName = levelFieldName
 	zerolog.TimeFieldFormat = time.RFC3339Nano
@@ -29,32 +64,158 @@ func init() {
 	zerolog.TimestampFunc = func() time.Time {
 		return time.Now().UTC()
 	}
-	zerolog.SetGlobalLevel(zerolog.InfoLevel)
-
-	log.Logger = zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).
-		With().Caller().
-		Str("environment", os.Getenv("ENV")).
-		Str("service", utils.APP_NAME).
-		Str("version", utils.GetEnvOrDefault("COMMIT_TAG", "main")).
-		Timestamp().Logger()
+	// main configures logging once settings are loaded, until then defaults apply
+	defaults := config.Default()
+	logConfig, err := NewConfig(defaults.Log, defaults.Build)
+	if err == nil {
+		err = Configure(logConfig)
+	}
+	if err != nil {
+		log.Logger.Error().Msgf("invalid default logging config, logger is left as is: %v", err)
+		return
+	}
 
 	log.Logger.Info().Msg("Finished logger setup")
 }
 
+// WithContext returns logger of the request `c` belongs to, enriched with correlation id, route, principal and tenant.
+// Outside of requests it is the global logger with ids of the current trace span if there is one.
 func WithContext(c context.Context) *zerolog.Logger {
+	if ctxLogger := zerolog.Ctx(c); ctxLogger.GetLevel() != zerolog.Disabled {
+		return ctxLogger
+	}
 	if ctxLogger, found := c.Value(logKey).(zerolog.Logger); found {
 		return &ctxLogger
 	}
-	return &log.Logger
+	logger := log.Logger
+	if correlationId := CorrelationId(c); correlationId != "" {
+		logger = logger.With().Str("correlation_id", correlationId).Logger()
+	}
+	if spanContext := trace.SpanContextFromContext(c); spanContext.IsValid() {
+		logger = withSpan(logger.With(), spanContext).Logger()
+	}
+	return &logger
+}
+
+func withSpan(logContext zerolog.Context, spanContext trace.SpanContext) zerolog.Context {
+	if !spanContext.IsValid() {
+		return logContext
+	}
+	return logContext.
+		Str("trace_id", spanContext.TraceID().String()).
+		Str("span_id", spanContext.SpanID().String())
+}
+
+// CorrelationId returns correlation id of the request `c` belongs to, empty outside of requests.
+func CorrelationId(c context.Context) string {
+	if correlationId, found := c.Value(correlationIdKey{}).(string); found {
+		return correlationId
+	}
+	correlationId, _ := c.Value(correlationIdContextKey).(string)
+	return correlationId
+}
+
+// incomingCorrelationId returns id sent by upstream, empty if there is none or it is invalid.
+func incomingCorrelationId(gctx *gin.Context) string {
+	for _, header := range []string{RequestIdHeader, CorrelationIdHeader} {
+		correlationId := gctx.GetHeader(header)
+		if correlationId == "" {
+			continue
+		}
+		if correlationIdRegex.MatchString(correlationId) {
+			return correlationId
+		}
+		log.Logger.Warn().Msgf("ignoring invalid `%v` header of length %v", header, len(correlationId))
+	}
+	return ""
+}
+
+// Actor returns who made the request `c` belongs to.
+func Actor(c context.Context) string {
+	if actor, found := c.Value(actorKey{}).(string); found {
+		return actor
+	}
+	if actor, found := c.Value(actorContextKey).(string); found {
+		return actor
+	}
+	return ActorSystem
+}
+
+// SetActor records who made the request, request logger is updated to log them as principal.
+func SetActor(gctx *gin.Context, actor string) {
+	gctx.Set(actorContextKey, actor)
+	if gctx.Request != nil {
+		gctx.Request = gctx.Request.WithContext(context.WithValue(gctx.Request.Context(), actorKey{}, actor))
+	}
+	attachLogger(gctx)
+}
+
+// Tenant returns tenant sent in `X-Tenant-ID` header of the request `c` belongs to, empty if there is none.
+func Tenant(c context.Context) string {
+	if tenant, found := c.Value(tenantKey{}).(string); found {
+		return tenant
+	}
+	tenant, _ := c.Value(tenantContextKey).(string)
+	return tenant
+}
+
+// WithTenant returns context of work done on behalf of `tenant`, e.g. by a background task.
+func WithTenant(c context.Context, tenant string) context.Context {
+	return context.WithValue(c, tenantKey{}, tenant)
+}
+
+// ClientIp returns address of the client that made the request `c` belongs to, empty outside of requests.
+func ClientIp(c context.Context) string {
+	if clientIp, found := c.Value(clientIpKey{}).(string); found {
+		return clientIp
+	}
+	clientIp, _ := c.Value(clientIpContextKey).(string)
+	return clientIp
+}
+
+// attachLogger stores logger with current request attributes in gin context and in `Request.Context()`,
+// so it is found by WithContext with either of them, and by zerolog.Ctx in packages logging cannot be imported to.
+func attachLogger(gctx *gin.Context) {
+	if gctx.Request == nil {
+		return
+	}
+	logContext := withSpan(log.Logger.With(), trace.SpanContextFromContext(gctx.Request.Context())).
+		Str("correlation_id", CorrelationId(gctx))
+	if route := gctx.FullPath(); route != "" {
+		logContext = logContext.Str("route", route)
+	}
+	if actor, found := gctx.Get(actorContextKey); found {
+		logContext = logContext.Str("principal", actor.(string))
+	}
+	if tenant := Tenant(gctx); tenant != "" {
+		logContext = logContext.Str("tenant", tenant)
+	}
+	logger := logContext.Logger()
+	gctx.Set(logKey, logger)
+	gctx.Request = gctx.Request.WithContext(logger.WithContext(gctx.Request.Context()))
 }
 
 func Middleware() gin.HandlerFunc {
 	return func(gctx *gin.Context) {
 		startTime := time.Now().UTC()
-		correlationId := uuid.NewString()
+		correlationId := incomingCorrelationId(gctx)
+		if correlationId == "" {
+			correlationId = uuid.NewString()
+		}
 		gctx.Set(correlationIdContextKey, correlationId)
-		innitialLog := log.Logger.With().
-			Str("correlation_id", correlationId).
+		gctx.Request = gctx.Request.WithContext(
+			context.WithValue(gctx.Request.Context(), correlationIdKey{}, correlationId))
+		gctx.Header(CorrelationIdHeader, correlationId)
+		gctx.Set(clientIpContextKey, gctx.ClientIP())
+		gctx.Request = gctx.Request.WithContext(
+			context.WithValue(gctx.Request.Context(), clientIpKey{}, gctx.ClientIP()))
+		if tenant := gctx.GetHeader(TenantHeader); TenantRegex.MatchString(tenant) {
+			gctx.Set(tenantContextKey, tenant)
+			gctx.Request = gctx.Request.WithContext(WithTenant(gctx.Request.Context(), tenant))
+		}
+		SetActor(gctx, ActorAnonymous)
+		sampled := sampleRequest()
+		innitialLog := WithContext(gctx).With().
 			Dict("http", zerolog.Dict().
 				Str("method", gctx.Request.Method).
 				Str("url", gctx.Request.URL.String()).
@@ -66,12 +227,14 @@ func Middleware() gin.HandlerFunc {
 			Str("rq_content_type", gctx.Request.Header.Get("Content-Type")).
 			Timestamp().Logger()
 
-		innitialLog.Info().Msg(requestStartedMessage)
+		if sampled {
+			innitialLog.Info().Msg(requestStartedMessage)
+		}
 
 		gctx.Next()
 
-		exitLog := log.Logger.With().
-			Str("correlation_id", correlationId).
+		// principal may have been set by authentication in the meantime
+		exitLog := WithContext(gctx).With().
 			Dur("rs_time_ms", time.Since(startTime)).
 			Timestamp().Logger()
 
@@ -87,7 +250,7 @@ func Middleware() gin.HandlerFunc {
 				exitLog.WithLevel(zerolog.ErrorLevel).
 					Msg(requestEndedMessage)
 			}
-		default:
+		case sampled:
 			exitLog.WithLevel(zerolog.InfoLevel).
 				Msg(requestEndedMessage)
 		}
@@ -3,16 +3,19 @@ package logging
 import (
 	"app/utils"
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func captureLogOutput(f func()) string {
@@ -83,6 +86,8 @@ func TestMiddleware(t *testing.T) {
 
 			correlationId, _ := ginContext.Get(correlationIdContextKey)
 			assert.NotEmpty(t, correlationId)
+			assert.Equal(t, correlationId, CorrelationId(ginContext))
+			assert.Equal(t, ActorAnonymous, Actor(ginContext))
 
 			assert.Contains(t, logOutput, requestStartedMessage,
 				"should log start of the request")
@@ -100,3 +105,178 @@ func TestMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestActor(t *testing.T) {
+	t.Run("Outside of request", func(t *testing.T) {
+		assert.Equal(t, ActorSystem, Actor(context.Background()))
+		assert.Empty(t, CorrelationId(context.Background()))
+	})
+	t.Run("Actor set by authentication", func(t *testing.T) {
+		ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+		SetActor(ginContext, "admin")
+		assert.Equal(t, "admin", Actor(ginContext))
+	})
+	t.Run("Actor in request context", func(t *testing.T) {
+		ginContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		SetActor(ginContext, ActorAnonymous)
+		SetActor(ginContext, "admin")
+		assert.Equal(t, "admin", Actor(ginContext.Request.Context()))
+	})
+}
+
+func TestWithContextSpan(t *testing.T) {
+	t.Run("Should add ids of current span", func(t *testing.T) {
+		spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35},
+			SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa},
+		})
+		c := trace.ContextWithSpanContext(context.Background(), spanContext)
+
+		output := captureLogOutput(func() {
+			WithContext(c).Info().Msg("Testing")
+		})
+
+		assert.Contains(t, output, spanContext.TraceID().String(), "should add trace id")
+		assert.Contains(t, output, spanContext.SpanID().String(), "should add span id")
+	})
+
+	t.Run("Should not add ids without span", func(t *testing.T) {
+		output := captureLogOutput(func() {
+			WithContext(context.Background()).Info().Msg("Testing")
+		})
+
+		assert.NotContains(t, output, "trace_id")
+	})
+}
+
+var correlationIdTestCases = []struct {
+	description           string
+	headers               map[string]string
+	expectedCorrelationId string
+}{
+	{
+		description:           "Accepts X-Request-ID",
+		headers:               map[string]string{RequestIdHeader: "upstream-request-1"},
+		expectedCorrelationId: "upstream-request-1",
+	},
+	{
+		description:           "Accepts X-Correlation-ID",
+		headers:               map[string]string{CorrelationIdHeader: "trace:42.a_b"},
+		expectedCorrelationId: "trace:42.a_b",
+	},
+	{
+		description: "Prefers X-Request-ID",
+		headers: map[string]string{
+			RequestIdHeader:     "upstream-request-1",
+			CorrelationIdHeader: "upstream-correlation-1",
+		},
+		expectedCorrelationId: "upstream-request-1",
+	},
+	{
+		description: "Falls back to valid X-Correlation-ID",
+		headers: map[string]string{
+			RequestIdHeader:     "invalid id",
+			CorrelationIdHeader: "upstream-correlation-1",
+		},
+		expectedCorrelationId: "upstream-correlation-1",
+	},
+	{
+		description: "Rejects invalid characters",
+		headers:     map[string]string{RequestIdHeader: "id\nfake log line"},
+	},
+	{
+		description: "Rejects too long id",
+		headers:     map[string]string{RequestIdHeader: strings.Repeat("a", 129)},
+	},
+}
+
+func TestCorrelationId(t *testing.T) {
+	for _, testCase := range correlationIdTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			ginContext, _ := gin.CreateTestContext(recorder)
+			request, _ := http.NewRequest(http.MethodGet, "/", nil)
+			for header, value := range testCase.headers {
+				request.Header.Set(header, value)
+			}
+			ginContext.Request = request
+
+			captureLogOutput(func() {
+				Middleware()(ginContext)
+			})
+
+			correlationId := CorrelationId(ginContext)
+			if testCase.expectedCorrelationId != "" {
+				assert.Equal(t, testCase.expectedCorrelationId, correlationId)
+			} else {
+				assert.Regexp(t, "^[0-9a-f-]{36}$", correlationId, "should mint new id")
+			}
+			assert.Equal(t, correlationId, recorder.Header().Get(CorrelationIdHeader),
+				"should echo id in response")
+			assert.Equal(t, correlationId, CorrelationId(ginContext.Request.Context()),
+				"should propagate id through request context")
+		})
+	}
+
+	t.Run("Should add correlation id to context logger", func(t *testing.T) {
+		c := context.WithValue(context.Background(), correlationIdKey{}, "upstream-request-1")
+		output := captureLogOutput(func() {
+			WithContext(c).Info().Msg("Testing")
+		})
+		assert.Contains(t, output, "upstream-request-1")
+	})
+}
+
+func TestRequestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app := gin.New()
+	app.Use(Middleware())
+	var requestContext context.Context
+	app.GET("/event/:id", func(gctx *gin.Context) {
+		SetActor(gctx, "admin")
+		WithContext(gctx).Info().Msg("Logged through gin context")
+		requestContext = gctx.Request.Context()
+		WithContext(requestContext).Info().Msg("Logged through request context")
+	})
+	request, _ := http.NewRequest(http.MethodGet, "/event/event-id", nil)
+	request.Header.Set(RequestIdHeader, "upstream-request-1")
+	request.Header.Set(TenantHeader, "tenant-1")
+
+	output := captureLogOutput(func() {
+		app.ServeHTTP(httptest.NewRecorder(), request)
+	})
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	assert.Len(t, lines, 4)
+	for _, line := range lines[1:] {
+		assert.Contains(t, line, `"correlation_id":"upstream-request-1"`)
+		assert.Contains(t, line, `"route":"/event/:id"`)
+		assert.Contains(t, line, `"principal":"admin"`)
+		assert.Contains(t, line, `"tenant":"tenant-1"`)
+	}
+	assert.Contains(t, lines[0], `"principal":"anonymous"`, "should log principal known at the time")
+	assert.Equal(t, "tenant-1", Tenant(requestContext))
+
+	t.Run("Should not log invalid tenant", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/event/event-id", nil)
+		request.Header.Set(TenantHeader, "tenant\nfake log line")
+
+		output := captureLogOutput(func() {
+			app.ServeHTTP(httptest.NewRecorder(), request)
+		})
+
+		assert.NotContains(t, output, "tenant")
+	})
+
+	t.Run("Should not accept tenant with separators", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/event/event-id", nil)
+		request.Header.Set(TenantHeader, "tenant:1")
+
+		output := captureLogOutput(func() {
+			app.ServeHTTP(httptest.NewRecorder(), request)
+		})
+
+		assert.NotContains(t, output, "tenant")
+	})
+}
@@ -1,12 +1,29 @@
 package main
 
 import (
+	"app/auth"
+	"app/catalog"
+	"app/config"
 	"app/db"
 	_ "app/docs"
+	lg "app/logging"
+	"app/notifications"
 	"app/routes"
+	"app/scheduler"
+	"app/server"
+	"app/streaming"
+	"app/tracing"
+	"app/utils"
+	"app/webhooks"
+	"context"
+	"flag"
 	"net/http"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
 )
 
 // @title		EventHandler API
@@ -15,15 +32,72 @@ import (
 // @description    An event management service API in Go using Gin framework.
 // @contact.name  Marek Beck
 func main() {
+	settings, err := config.Load(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Logger.Fatal().Msgf("invalid configuration:\n%v", err)
+	}
+	logConfig, err := lg.NewConfig(settings.Log, settings.Build)
+	if err == nil {
+		err = lg.Configure(logConfig)
+	}
+	if err != nil {
+		log.Logger.Fatal().Msgf("failed to set up logging: %v", err)
+	}
+	shutdownTracing, err := tracing.Init(context.Background(), settings.Tracing, settings.Build)
+	if err != nil {
+		log.Logger.Fatal().Msgf("failed to set up tracing: %v", err)
+	}
+	db.Connect(settings.Redis)
+	auth.Configure(settings.Auth)
+	notifications.Configure(settings.Notifier)
+	scheduler.Configure(settings.Scheduler)
+	streaming.Configure(settings.Streams)
+	applyReloadable(settings)
+	if err := catalog.Load(context.Background()); err != nil {
+		log.Logger.Error().Msgf("failed to load quality catalog, using default profiles: %v", err)
+	}
+
+	watchConfig := func(c context.Context) {
+		config.Watch(c, settings, os.Args[1:], applyReloadable)
+	}
+
 	app := gin.New()
-	routes.InitApp(app)
-	db.Init()
-	server := &http.Server{
-		Addr:    ":3000",
-		Handler: app,
+	routes.InitApp(app, settings)
+	srv := server.Server{
+		HTTP: &http.Server{
+			Addr:    ":" + strconv.Itoa(settings.Server.Port),
+			Handler: app,
+		},
+		Workers:    []func(context.Context){scheduler.Run, notifications.Run, webhooks.Run, catalog.Run, watchConfig},
+		Close:      db.Close,
+		DrainDelay: time.Duration(settings.Server.DrainDelay),
+		Timeout:    time.Duration(settings.Server.ShutdownTimeout),
+	}
+	err = srv.Run(context.Background())
+	if err != nil {
+		log.Logger.Error().Msgf("server stopped with error: %v", err)
 	}
-	err := server.ListenAndServe()
+	// the only place tracing is shut down, os.Exit skips deferred calls
+	stopTracing(shutdownTracing, time.Duration(settings.Server.ShutdownTimeout))
 	if err != nil {
-		panic("failed to start gin server")
+		os.Exit(1)
+	}
+}
+
+// stopTracing flushes pending spans, giving up after `timeout` so that unreachable collector does not block exit.
+func stopTracing(shutdown func(context.Context) error, timeout time.Duration) {
+	c, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := shutdown(c); err != nil {
+		log.Logger.Error().Msgf("failed to shut down tracing: %v", err)
+	}
+}
+
+// applyReloadable swaps settings which can change while the service runs.
+func applyReloadable(settings config.Config) {
+	utils.SetQualities(settings.Qualities)
+	routes.SetLimits(settings.Limits)
+	if err := lg.Reconfigure(settings.Log); err != nil {
+		log.Logger.Error().Msgf("failed to apply log levels: %v", err)
 	}
 }
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"app/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// unmatchedRoute labels requests which did not match any route, raw URLs would make label cardinality unbounded
+const unmatchedRoute = "unmatched"
+
+// Registry holds metrics of this service, exposed by `Handler`
+var Registry = prometheus.NewRegistry()
+
+var HttpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: utils.APP_NAME,
+	Name:      "http_requests_total",
+	Help:      "Handled HTTP requests by route template, method and status code.",
+}, []string{"method", "route", "status"})
+
+var HttpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: utils.APP_NAME,
+	Name:      "http_request_duration_seconds",
+	Help:      "HTTP request latency by route template and method.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"method", "route"})
+
+var RedisCommandDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: utils.APP_NAME,
+	Name:      "redis_command_duration_seconds",
+	Help:      "Redis command latency by command, transactions and pipelines are measured as a whole.",
+	Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 16),
+}, []string{"command"})
+
+var RedisCommandErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: utils.APP_NAME,
+	Name:      "redis_command_errors_total",
+	Help:      "Failed Redis commands by command, missing keys are not counted.",
+}, []string{"command"})
+
+var AppErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: utils.APP_NAME,
+	Name:      "app_errors_total",
+	Help:      "Error responses by error name.",
+}, []string{"error"})
+
+var EventsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: utils.APP_NAME,
+	Name:      "events_created_total",
+	Help:      "Created events.",
+})
+
+var EventsDeleted = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: utils.APP_NAME,
+	Name:      "events_deleted_total",
+	Help:      "Deleted events.",
+})
+
+var EventTransitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: utils.APP_NAME,
+	Name:      "event_transitions_total",
+	Help:      "Event lifecycle transitions by resulting status.",
+}, []string{"status"})
+
+var LiveConnections = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: utils.APP_NAME,
+	Name:      "live_connections",
+	Help:      "Open live channel connections on this replica.",
+})
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HttpRequests,
+		HttpRequestDuration,
+		RedisCommandDuration,
+		RedisCommandErrors,
+		AppErrors,
+		EventsCreated,
+		EventsDeleted,
+		EventTransitions,
+		LiveConnections,
+	)
+}
+
+func Middleware() gin.HandlerFunc {
+	return func(gctx *gin.Context) {
+		startTime := time.Now()
+		gctx.Next()
+
+		route := gctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := gctx.Request.Method
+		HttpRequests.WithLabelValues(method, route, strconv.Itoa(gctx.Writer.Status())).Inc()
+		HttpRequestDuration.WithLabelValues(method, route).Observe(time.Since(startTime).Seconds())
+	}
+}
+
+// Handler serves metrics in Prometheus text format.
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+}
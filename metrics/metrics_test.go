@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	app := gin.New()
+	app.Use(Middleware())
+	app.GET("/event/:id", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNotFound)
+	})
+	app.GET("/metrics", Handler())
+
+	t.Run("Requests are labelled by route template", func(t *testing.T) {
+		before := testutil.ToFloat64(HttpRequests.WithLabelValues(http.MethodGet, "/event/:id", "404"))
+		for _, id := range []string{"first-id", "second-id"} {
+			app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/event/"+id, nil))
+		}
+		assert.Equal(t, before+2, testutil.ToFloat64(HttpRequests.WithLabelValues(http.MethodGet, "/event/:id", "404")))
+	})
+
+	t.Run("Unmatched requests share one label", func(t *testing.T) {
+		before := testutil.ToFloat64(HttpRequests.WithLabelValues(http.MethodGet, unmatchedRoute, "404"))
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/non-existent-route", nil))
+		assert.Equal(t, before+1, testutil.ToFloat64(HttpRequests.WithLabelValues(http.MethodGet, unmatchedRoute, "404")))
+	})
+
+	t.Run("Metrics are exposed in text format", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		app.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Header().Get("Content-Type"), "text/plain")
+		assert.Contains(t, recorder.Body.String(), `event_handler_http_requests_total{method="GET",route="/event/:id",status="404"}`)
+		assert.Contains(t, recorder.Body.String(), `event_handler_http_request_duration_seconds_bucket{method="GET",route="/event/:id"`)
+		assert.Contains(t, recorder.Body.String(), "go_goroutines")
+		assert.NotContains(t, recorder.Body.String(), "first-id", "raw URLs are never used as labels")
+	})
+}
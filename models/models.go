@@ -1,23 +1,67 @@
 package models
 
+import "time"
+
 // @Description If not provided, `videoQuality` & `audioQuality` default to `["720p"]` & `["Low"]`, respectively.
 // @Description If provided, first item in the list is event's default quality.
+// @Description `streaming` is derived from them if not provided, otherwise they are derived from `streaming`.
 type EventData struct {
 	Id string `json:"-"`
 	//allowed chars: A-Za-z0-9 _-
 	Name string `json:"name" example:"A event-Name3_x" binding:"required,min=1,max=255,checkEventName"`
 	//YYYY-MM-DDTHH:MM:SSZ
-	Timestamp    string   `json:"date" example:"2006-01-02T15:04:05Z" binding:"required,checkTimeFieldFormat"`
-	Languages    []string `json:"languages" example:"English,French" binding:"required,min=1,unique"`
-	VideoQuality []string `json:"videoQuality" example:"720p,1080p,1440p,2160p" binding:"checkVideoQuality,unique"`
-	AudioQuality []string `json:"audioQuality" example:"Low,Mid,High" binding:"checkAudioQuality,unique"`
-	Invitees     []string `json:"invitees" example:"example@mail.com" binding:"required,min=1,max=100,unique,checkEmail"`
-	Description  string   `json:"description"  binding:"max=512"`
+	Timestamp string `json:"date" example:"2006-01-02T15:04:05Z" binding:"required,checkTimeFieldFormat"`
+	//BCP 47 tags, English or native language names are accepted and stored as tags
+	Languages    []string         `json:"languages" example:"en,fr" binding:"required,min=1,unique,checkLanguage"`
+	VideoQuality []string         `json:"videoQuality" example:"720p,1080p,1440p,2160p" binding:"checkVideoQuality,unique"`
+	AudioQuality []string         `json:"audioQuality" example:"Low,Mid,High" binding:"checkAudioQuality,unique"`
+	Invitees     []string         `json:"invitees" example:"example@mail.com" binding:"required,min=1,max=100,unique,checkEmail"`
+	Description  string           `json:"description"  binding:"max=512"`
+	Streaming    *StreamingConfig `json:"streaming,omitempty"`
+	//read-only, new events always start as `draft` (draft, scheduled, live, ended, cancelled)
+	Status string `json:"status" example:"draft"`
+}
+
+const (
+	LatencyStandard = "standard"
+	LatencyLow      = "low"
+	LatencyUltraLow = "ultra-low"
+)
+
+const (
+	SubtitleWebVTT = "webvtt"
+	SubtitleTTML   = "ttml"
+)
+
+// StreamingConfig describes renditions viewers get, `defaultRendition` has to be part of `ladder`
+// and every audio track language one of event `languages`.
+type StreamingConfig struct {
+	DefaultRendition string `json:"defaultRendition" example:"720p" binding:"required"`
+	//adaptive bitrate ladder, video qualities player switches between
+	Ladder []string `json:"ladder" example:"720p,1080p" binding:"required,min=1,unique,checkVideoQuality"`
+	//one track per language, defaults to a track of `audioQuality` for every event language
+	AudioTracks []AudioTrack    `json:"audioTracks" binding:"unique=Language,dive"`
+	Subtitles   []SubtitleTrack `json:"subtitles" binding:"unique=Language,dive"`
+	//standard, low or ultra-low, defaults to standard
+	LatencyMode string `json:"latencyMode" example:"standard" binding:"omitempty,oneof=standard low ultra-low"`
+}
+
+type AudioTrack struct {
+	Language  string   `json:"language" example:"en" binding:"required,checkLanguage"`
+	Qualities []string `json:"qualities" example:"Low,Mid" binding:"required,min=1,unique,checkAudioQuality"`
+}
+
+type SubtitleTrack struct {
+	Language string `json:"language" example:"fr" binding:"required,max=64,checkLanguage"`
+	//webvtt or ttml, defaults to webvtt
+	Format string `json:"format" example:"webvtt" binding:"omitempty,oneof=webvtt ttml"`
 }
 
 type EventResponseData struct {
 	Id string `json:"id" example:"db6bed50-7172-4051-86ab-d1e90705c692"`
 	EventData
+	//names of `languages` in locale of `Accept-Language` header, English by default
+	LanguageNames map[string]string `json:"languageNames,omitempty" example:"en:English,fr:French"`
 }
 
 type JsonHealthCheckStatus struct {
@@ -25,3 +69,194 @@ type JsonHealthCheckStatus struct {
 	DeployDate string `json:"deployDate"`
 	Version    string `json:"version"`
 }
+
+// Health is result of readiness checks, `status` is `ok`, `degraded` or `failing`.
+type Health struct {
+	Status string        `json:"status" example:"ok"`
+	Checks []HealthCheck `json:"checks"`
+}
+
+type HealthCheck struct {
+	Name      string  `json:"name" example:"redis"`
+	Status    string  `json:"status" example:"ok"`
+	Critical  bool    `json:"critical" example:"true"`
+	LatencyMs float64 `json:"latencyMs" example:"0.42"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type Job struct {
+	Id        string    `json:"id" example:"reminder-db6bed50-7172-4051-86ab-d1e90705c692"`
+	Type      string    `json:"type" example:"reminder"`
+	EventId   string    `json:"eventId" example:"db6bed50-7172-4051-86ab-d1e90705c692"`
+	RunAt     time.Time `json:"runAt" example:"2023-04-20T13:45:00Z"`
+	Status    string    `json:"status" example:"pending"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"lastError,omitempty"`
+}
+
+const (
+	NotificationEventCreated   = "event.created"
+	NotificationEventUpdated   = "event.updated"
+	NotificationEventCancelled = "event.cancelled"
+	NotificationEventReminder  = "event.reminder"
+)
+
+type Notification struct {
+	Id        string            `json:"id" example:"0b1d34a7-8bcd-47f3-8923-d472510d8da4"`
+	Kind      string            `json:"kind" example:"event.created"`
+	Event     EventResponseData `json:"event"`
+	CreatedAt time.Time         `json:"createdAt" example:"2023-04-20T13:45:00Z"`
+	Attempts  int               `json:"attempts"`
+	LastError string            `json:"lastError,omitempty"`
+}
+
+const (
+	WebhookEventCreated = "event.created"
+	WebhookEventUpdated = "event.updated"
+	WebhookEventDeleted = "event.deleted"
+)
+
+type WebhookSubscriptionData struct {
+	Url string `json:"url" example:"https://example.com/hooks/events" binding:"required,url"`
+	//used as HMAC-SHA256 key of `X-Signature-256` header, never returned
+	Secret     string   `json:"secret,omitempty" example:"a-long-random-secret" binding:"required,min=16,max=255"`
+	EventTypes []string `json:"eventTypes" example:"event.created,event.deleted" binding:"required,min=1,unique,dive,oneof=event.created event.updated event.deleted"`
+}
+
+type WebhookSubscription struct {
+	Id        string    `json:"id" example:"0b1d34a7-8bcd-47f3-8923-d472510d8da4"`
+	CreatedAt time.Time `json:"createdAt" example:"2023-04-20T13:45:00Z"`
+	WebhookSubscriptionData
+}
+
+type WebhookDelivery struct {
+	Id             string     `json:"id" example:"90a04b08-d820-4106-8ced-2cbc940728a3"`
+	SubscriptionId string     `json:"subscriptionId" example:"0b1d34a7-8bcd-47f3-8923-d472510d8da4"`
+	EventType      string     `json:"eventType" example:"event.created"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status" example:"delivered"`
+	Attempts       int        `json:"attempts"`
+	ResponseStatus int        `json:"responseStatus,omitempty" example:"200"`
+	LastError      string     `json:"lastError,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt" example:"2023-04-20T13:45:00Z"`
+	DeliveredAt    *time.Time `json:"deliveredAt,omitempty" example:"2023-04-20T13:45:01Z"`
+}
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+type WebhookPayload struct {
+	Id         string            `json:"id"`
+	Type       string            `json:"type"`
+	OccurredAt time.Time         `json:"occurredAt"`
+	Event      EventResponseData `json:"event"`
+}
+
+// change feed message types
+const (
+	ChangeCreated = "created"
+	ChangeUpdated = "updated"
+	ChangeDeleted = "deleted"
+)
+
+const (
+	DomainEventCreated = "EventCreated"
+	DomainEventUpdated = "EventUpdated"
+	DomainEventDeleted = "EventDeleted"
+)
+
+// DomainEvent is an entry of the event log, `Id` is its position in the log.
+type DomainEvent struct {
+	Id            string            `json:"id" example:"1682000000000-0"`
+	Type          string            `json:"type" example:"EventCreated"`
+	OccurredAt    time.Time         `json:"occurredAt" example:"2023-04-20T13:45:00Z"`
+	Actor         string            `json:"actor" example:"admin"`
+	CorrelationId string            `json:"correlationId,omitempty" example:"0b8a1f5e-4c1d-4f0e-9a57-1e6f4d7e2c11"`
+	Event         EventResponseData `json:"event"`
+}
+
+type EventAccessRequest struct {
+	Email string `json:"email" binding:"required,email" example:"valid-email@mail.com"`
+}
+
+// EventAccess grants invitee access to event's live channel.
+type EventAccess struct {
+	Email string `json:"email" example:"valid-email@mail.com"`
+	Token string `json:"token" example:"5f2b8c..."`
+}
+
+type Presence struct {
+	Count   int      `json:"count" example:"1"`
+	Viewers []string `json:"viewers" example:"valid-email@mail.com"`
+}
+
+// LogLevels are levels logs are currently written at, `packages` override `level` for single packages.
+type LogLevels struct {
+	Level    string            `json:"level" example:"info"`
+	Packages map[string]string `json:"packages"`
+}
+
+type LogLevelRequest struct {
+	Level string `json:"level" binding:"required,oneof=trace debug info warn error disabled" example:"debug"`
+	// Package is package of the service, e.g. `db`, global level is changed if it is empty
+	Package string `json:"package,omitempty" example:"db"`
+}
+
+// audited actions
+const (
+	AuditEventCreate      = "event.create"
+	AuditEventUpdate      = "event.update"
+	AuditEventDelete      = "event.delete"
+	AuditEventAccessGrant = "event.access.grant"
+	AuditWebhookCreate    = "webhook.create"
+	AuditWebhookDelete    = "webhook.delete"
+	AuditWebhookRedeliver = "webhook.redeliver"
+	AuditLogLevelChange   = "logLevel.change"
+	AuditAuthFailure      = "auth.failure"
+	AuditQualityCreate    = "quality.create"
+	AuditQualityUpdate    = "quality.update"
+	AuditQualityDelete    = "quality.delete"
+	AuditTenantQualities  = "tenant.qualities"
+)
+
+// AuditEntry records who changed what, hashes are hex SHA-256 of the stored documents, empty if there is none.
+type AuditEntry struct {
+	Id            string    `json:"id" example:"1682000000000-0"`
+	OccurredAt    time.Time `json:"occurredAt" example:"2023-04-20T13:45:00Z"`
+	Actor         string    `json:"actor" example:"admin"`
+	Action        string    `json:"action" example:"event.delete"`
+	TargetId      string    `json:"targetId" example:"5b8d3c5e-2d0a-4a8f-8b57-7c1e2a3b4c5d"`
+	BeforeHash    string    `json:"beforeHash,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	AfterHash     string    `json:"afterHash,omitempty"`
+	ClientIp      string    `json:"clientIp,omitempty" example:"192.0.2.10"`
+	CorrelationId string    `json:"correlationId,omitempty" example:"0b8a1f5e-4c1d-4f0e-9a57-1e6f4d7e2c11"`
+}
+
+// kinds of quality profiles
+const (
+	QualityVideo = "video"
+	QualityAudio = "audio"
+)
+
+// QualityProfile is a rendition events can be streamed in, events refer to it by `name` in `videoQuality` or `audioQuality`.
+// @Description `width` & `height` are required for video, `channels` & `sampleRateHz` for audio profiles.
+type QualityProfile struct {
+	Kind string `json:"kind" example:"video" binding:"required,oneof=video audio"`
+	Name string `json:"name" example:"1080p" binding:"required,alphanum,max=32"`
+	// RFC 6381 codec string as used in HLS `CODECS` and DASH `codecs`
+	Codec        string `json:"codec" example:"avc1.640028" binding:"required,max=64"`
+	BitrateKbps  int    `json:"bitrateKbps" example:"6000" binding:"required,min=1"`
+	Width        int    `json:"width,omitempty" example:"1920" binding:"required_if=Kind video,min=0"`
+	Height       int    `json:"height,omitempty" example:"1080" binding:"required_if=Kind video,min=0"`
+	Channels     int    `json:"channels,omitempty" example:"2" binding:"required_if=Kind audio,min=0,max=16"`
+	SampleRateHz int    `json:"sampleRateHz,omitempty" example:"48000" binding:"required_if=Kind audio,min=0"`
+}
+
+// TenantQualities are names of profiles enabled for a tenant, empty list enables every profile of its kind.
+type TenantQualities struct {
+	Video []string `json:"video" example:"720p,1080p" binding:"unique"`
+	Audio []string `json:"audio" example:"Low,Mid" binding:"unique"`
+}
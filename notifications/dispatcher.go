@@ -0,0 +1,77 @@
+package notifications
+
+import (
+	"app/config"
+	"app/db"
+	"app/health"
+	"app/worker"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	maxAttempts = 5
+	// maxOutboxLag is how long past due time notifications may wait before outbox is reported unhealthy
+	maxOutboxLag = 5 * time.Minute
+)
+
+var notifier Notifier = &LogNotifier{}
+
+// Configure selects delivery channel, messages are logged until it is called.
+func Configure(settings config.Notifier) {
+	notifier = NewNotifier(settings)
+}
+
+// Run delivers notifications from the outbox until `c` is done.
+func Run(c context.Context) {
+	heartbeat := &health.Heartbeat{}
+	heartbeat.Beat(time.Now())
+	health.Register("outbox", false, 0, worker.Check(heartbeat, outbox(), maxOutboxLag))
+	defer health.Unregister("outbox")
+	worker.Run(c, "notification dispatcher", heartbeat, DispatchDueNotifications)
+}
+
+// outbox is built on every use, so that replaced storage functions take effect.
+func outbox() worker.Queue {
+	return worker.Queue{
+		Item:    "notification",
+		Due:     db.GetDueNotificationIds,
+		Acquire: db.AcquireNotificationLease,
+		Release: db.ReleaseNotificationLease,
+	}
+}
+
+var DispatchDueNotifications = func(c context.Context, now time.Time) {
+	worker.ProcessDue(c, now, outbox(), dispatch)
+}
+
+func dispatch(c context.Context, id string, now time.Time) {
+	notification, err := db.GetNotification(c, id)
+	if err != nil {
+		log.Logger.Error().Msgf("error reading notification `%v`: %v", id, err)
+		return
+	}
+	message, err := Render(notification)
+	if err == nil {
+		err = notifier.Notify(message)
+	}
+	if err == nil {
+		if err := db.AckNotification(c, id); err != nil {
+			log.Logger.Error().Msgf("error acknowledging notification `%v`: %v", id, err)
+		}
+		return
+	}
+	notification.Attempts++
+	notification.LastError = err.Error()
+	log.Logger.Warn().Msgf("notification `%v` failed (attempt %v): %v", id, notification.Attempts, err)
+	if notification.Attempts >= maxAttempts {
+		err = db.DeadLetterNotification(c, notification)
+	} else {
+		err = db.RetryNotification(c, notification, now.Add(worker.Backoff(notification.Attempts)))
+	}
+	if err != nil {
+		log.Logger.Error().Msgf("error requeueing notification `%v`: %v", id, err)
+	}
+}
@@ -0,0 +1,109 @@
+package notifications
+
+import (
+	"app/db"
+	"app/models"
+	"app/worker"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type notifierMock struct {
+	sent []Message
+	err  error
+}
+
+func (n *notifierMock) Notify(message Message) error {
+	n.sent = append(n.sent, message)
+	return n.err
+}
+
+var now = time.Date(2023, 4, 20, 13, 45, 0, 0, time.UTC)
+
+var DispatchDueNotificationsTestCases = []struct {
+	description      string
+	storedAttempts   int
+	notifyErr        error
+	expectedAcked    bool
+	expectedRetryAt  time.Time
+	expectedAttempts int
+	expectedDead     bool
+}{
+	{
+		description:   "Success - delivered notification is acknowledged",
+		expectedAcked: true,
+	},
+	{
+		description:      "Fail - first failure is retried after base backoff",
+		notifyErr:        errors.New("any error"),
+		expectedRetryAt:  now.Add(worker.BaseBackoff),
+		expectedAttempts: 1,
+	},
+	{
+		description:      "Fail - backoff grows exponentially",
+		storedAttempts:   2,
+		notifyErr:        errors.New("any error"),
+		expectedRetryAt:  now.Add(4 * worker.BaseBackoff),
+		expectedAttempts: 3,
+	},
+	{
+		description:      "Fail - notification is dead-lettered after last attempt",
+		storedAttempts:   maxAttempts - 1,
+		notifyErr:        errors.New("any error"),
+		expectedAttempts: maxAttempts,
+		expectedDead:     true,
+	},
+}
+
+func TestDispatchDueNotifications(t *testing.T) {
+	for _, testCase := range DispatchDueNotificationsTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			mock := &notifierMock{err: testCase.notifyErr}
+			notifier = mock
+			acked, dead := false, false
+			var retried models.Notification
+			var retryAt time.Time
+			stored := testNotification
+			stored.Attempts = testCase.storedAttempts
+
+			db.GetDueNotificationIds = func(c context.Context, dueAt time.Time, limit int64) ([]string, error) {
+				return []string{stored.Id}, nil
+			}
+			db.AcquireNotificationLease = func(c context.Context, id string, leaseOwner string, ttl time.Duration) (bool, error) {
+				return true, nil
+			}
+			db.ReleaseNotificationLease = func(c context.Context, id string, owner string) error {
+				return nil
+			}
+			db.GetNotification = func(c context.Context, id string) (models.Notification, error) {
+				assert.Equal(t, stored.Id, id)
+				return stored, nil
+			}
+			db.AckNotification = func(c context.Context, id string) error {
+				acked = true
+				return nil
+			}
+			db.RetryNotification = func(c context.Context, notification models.Notification, at time.Time) error {
+				retried, retryAt = notification, at
+				return nil
+			}
+			db.DeadLetterNotification = func(c context.Context, notification models.Notification) error {
+				dead = true
+				retried = notification
+				return nil
+			}
+
+			DispatchDueNotifications(context.Background(), now)
+
+			assert.Len(t, mock.sent, 1)
+			assert.Equal(t, testCase.expectedAcked, acked)
+			assert.Equal(t, testCase.expectedDead, dead)
+			assert.Equal(t, testCase.expectedRetryAt, retryAt)
+			assert.Equal(t, testCase.expectedAttempts, retried.Attempts)
+		})
+	}
+}
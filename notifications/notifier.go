@@ -0,0 +1,131 @@
+package notifications
+
+import (
+	"app/config"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type Message struct {
+	Kind    string   `json:"kind"`
+	EventId string   `json:"eventId"`
+	To      []string `json:"to"`
+	Subject string   `json:"subject"`
+	Body    string   `json:"body"`
+}
+
+// Notifier delivers rendered message to its recipients.
+type Notifier interface {
+	Notify(message Message) error
+}
+
+// NewNotifier selects delivery channel by `settings.Channel` (log, file, smtp or webhook).
+func NewNotifier(settings config.Notifier) Notifier {
+	switch channel := settings.Channel; channel {
+	case "file":
+		return &FileNotifier{Path: settings.File}
+	case "smtp":
+		return &SMTPNotifier{
+			Addr:     settings.SMTP.Addr,
+			From:     settings.SMTP.From,
+			Username: settings.SMTP.Username,
+			Password: settings.SMTP.Password,
+		}
+	case "webhook":
+		return &WebhookNotifier{
+			URL:    settings.WebhookURL,
+			Client: &http.Client{Timeout: 10 * time.Second},
+		}
+	default:
+		if channel != "log" {
+			log.Logger.Error().Msgf("unknown notifier `%v`, falling back to log", channel)
+		}
+		return &LogNotifier{}
+	}
+}
+
+// LogNotifier writes messages to application log, meant for development.
+type LogNotifier struct{}
+
+func (n *LogNotifier) Notify(message Message) error {
+	log.Logger.Info().
+		Str("kind", message.Kind).
+		Str("event_id", message.EventId).
+		Strs("to", message.To).
+		Str("subject", message.Subject).
+		Msg("notification sent")
+	return nil
+}
+
+// FileNotifier appends messages to a file as JSON lines, meant for development.
+type FileNotifier struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func (n *FileNotifier) Notify(message Message) error {
+	line, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	file, err := os.OpenFile(n.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+type SMTPNotifier struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+func (n *SMTPNotifier) Notify(message Message) error {
+	var auth smtp.Auth
+	if n.Username != "" {
+		host := strings.Split(n.Addr, ":")[0]
+		auth = smtp.PlainAuth("", n.Username, n.Password, host)
+	}
+	body := fmt.Sprintf(
+		"From: %v\r\nTo: %v\r\nSubject: %v\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%v\r\n",
+		n.From, strings.Join(message.To, ", "), message.Subject, message.Body,
+	)
+	return smtp.SendMail(n.Addr, auth, n.From, message.To, []byte(body))
+}
+
+// WebhookNotifier posts messages as JSON to a generic endpoint.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func (n *WebhookNotifier) Notify(message Message) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	response, err := n.Client.Post(n.URL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status %v", response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package notifications
+
+import (
+	"app/config"
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testMessage = Message{
+	Kind:    "event.created",
+	EventId: "event-id",
+	To:      []string{"a@mail.com", "b@mail.com"},
+	Subject: "You are invited to My Event",
+	Body:    "Event body.",
+}
+
+func TestNewNotifier(t *testing.T) {
+	settings := config.Default().Notifier
+	settings.WebhookURL = "http://127.0.0.1/notify"
+	for channel, expected := range map[string]Notifier{
+		"log":     &LogNotifier{},
+		"file":    &FileNotifier{Path: "notifications.log"},
+		"smtp":    &SMTPNotifier{Addr: "127.0.0.1:1025", From: "noreply@event-handler.local"},
+		"webhook": &WebhookNotifier{},
+	} {
+		settings.Channel = channel
+		notifier := NewNotifier(settings)
+		assert.IsType(t, expected, notifier, channel)
+		if channel != "webhook" {
+			assert.Equal(t, expected, notifier, channel)
+		}
+	}
+	settings.Channel = "webhook"
+	assert.Equal(t, "http://127.0.0.1/notify", NewNotifier(settings).(*WebhookNotifier).URL)
+}
+
+func TestFileNotifier(t *testing.T) {
+	t.Run("Messages are appended as JSON lines", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "notifications.log")
+		notifier := &FileNotifier{Path: path}
+
+		assert.Nil(t, notifier.Notify(testMessage))
+		assert.Nil(t, notifier.Notify(testMessage))
+
+		content, err := os.ReadFile(path)
+		assert.Nil(t, err)
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		assert.Len(t, lines, 2)
+		var written Message
+		assert.Nil(t, json.Unmarshal([]byte(lines[0]), &written))
+		assert.Equal(t, testMessage, written)
+	})
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	t.Run("Message is posted as JSON", func(t *testing.T) {
+		var received Message
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			json.NewDecoder(r.Body).Decode(&received)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+		notifier := &WebhookNotifier{URL: server.URL, Client: server.Client()}
+
+		assert.Nil(t, notifier.Notify(testMessage))
+		assert.Equal(t, testMessage, received)
+	})
+
+	t.Run("Fail - error status is returned as error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+		notifier := &WebhookNotifier{URL: server.URL, Client: server.Client()}
+
+		assert.EqualError(t, notifier.Notify(testMessage), "webhook responded with status 502")
+	})
+}
+
+// runTestSMTPServer accepts single SMTP session and sends received transcript to returned channel.
+func runTestSMTPServer(t *testing.T) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	transcript := make(chan string, 1)
+	go func() {
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var received strings.Builder
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+		inData := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				break
+			}
+			received.WriteString(line)
+			switch {
+			case inData && line == ".\r\n":
+				inData = false
+				reply("250 OK")
+			case inData:
+			case strings.HasPrefix(line, "EHLO"), strings.HasPrefix(line, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(line, "DATA"):
+				inData = true
+				reply("354 End data with <CR><LF>.<CR><LF>")
+			case strings.HasPrefix(line, "QUIT"):
+				reply("221 Bye")
+				transcript <- received.String()
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+		transcript <- received.String()
+	}()
+	return listener.Addr().String(), transcript
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	t.Run("Message is sent to every recipient", func(t *testing.T) {
+		addr, transcript := runTestSMTPServer(t)
+		notifier := &SMTPNotifier{Addr: addr, From: "noreply@event-handler.local"}
+
+		assert.Nil(t, notifier.Notify(testMessage))
+
+		received := <-transcript
+		assert.Contains(t, received, "MAIL FROM:<noreply@event-handler.local>")
+		assert.Contains(t, received, "RCPT TO:<a@mail.com>")
+		assert.Contains(t, received, "RCPT TO:<b@mail.com>")
+		assert.Contains(t, received, "Subject: You are invited to My Event")
+		assert.Contains(t, received, "Event body.")
+	})
+}
@@ -0,0 +1,61 @@
+package notifications
+
+import (
+	"app/models"
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+type messageTemplate struct {
+	Subject *template.Template
+	Body    *template.Template
+}
+
+func newMessageTemplate(subject string, body string) messageTemplate {
+	return messageTemplate{
+		Subject: template.Must(template.New("subject").Parse(subject)),
+		Body:    template.Must(template.New("body").Parse(body)),
+	}
+}
+
+// templates are executed with `models.Notification` as data
+var templates = map[string]messageTemplate{
+	models.NotificationEventCreated: newMessageTemplate(
+		"You are invited to {{.Event.Name}}",
+		"You have been invited to {{.Event.Name}} starting at {{.Event.Timestamp}}.\n{{.Event.Description}}",
+	),
+	models.NotificationEventUpdated: newMessageTemplate(
+		"{{.Event.Name}} has been updated",
+		"Event {{.Event.Name}} starting at {{.Event.Timestamp}} is now {{.Event.Status}}.",
+	),
+	models.NotificationEventCancelled: newMessageTemplate(
+		"{{.Event.Name}} has been cancelled",
+		"Event {{.Event.Name}} planned for {{.Event.Timestamp}} has been cancelled.",
+	),
+	models.NotificationEventReminder: newMessageTemplate(
+		"{{.Event.Name}} starts soon",
+		"Event {{.Event.Name}} starts at {{.Event.Timestamp}}.",
+	),
+}
+
+var Render = func(notification models.Notification) (Message, error) {
+	tmpl, found := templates[notification.Kind]
+	if !found {
+		return Message{}, fmt.Errorf("no template for notification kind `%v`", notification.Kind)
+	}
+	var subject, body bytes.Buffer
+	if err := tmpl.Subject.Execute(&subject, notification); err != nil {
+		return Message{}, err
+	}
+	if err := tmpl.Body.Execute(&body, notification); err != nil {
+		return Message{}, err
+	}
+	return Message{
+		Kind:    notification.Kind,
+		EventId: notification.Event.Id,
+		To:      notification.Event.Invitees,
+		Subject: subject.String(),
+		Body:    body.String(),
+	}, nil
+}
@@ -0,0 +1,57 @@
+package notifications
+
+import (
+	"app/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testNotification = models.Notification{
+	Id:   "notification-id",
+	Kind: models.NotificationEventCancelled,
+	Event: models.EventResponseData{
+		Id: "event-id",
+		EventData: models.EventData{
+			Name:      "My Event",
+			Timestamp: "2023-04-20T14:00:00Z",
+			Invitees:  []string{"a@mail.com"},
+		},
+	},
+}
+
+func TestRender(t *testing.T) {
+	t.Run("Message is rendered from kind's template", func(t *testing.T) {
+		message, err := Render(testNotification)
+
+		assert.Nil(t, err)
+		assert.Equal(t, Message{
+			Kind:    models.NotificationEventCancelled,
+			EventId: "event-id",
+			To:      []string{"a@mail.com"},
+			Subject: "My Event has been cancelled",
+			Body:    "Event My Event planned for 2023-04-20T14:00:00Z has been cancelled.",
+		}, message)
+	})
+
+	t.Run("Every notification kind has a template", func(t *testing.T) {
+		for _, kind := range []string{
+			models.NotificationEventCreated,
+			models.NotificationEventUpdated,
+			models.NotificationEventCancelled,
+			models.NotificationEventReminder,
+		} {
+			notification := testNotification
+			notification.Kind = kind
+			_, err := Render(notification)
+			assert.Nil(t, err, kind)
+		}
+	})
+
+	t.Run("Fail - unknown kind", func(t *testing.T) {
+		notification := testNotification
+		notification.Kind = "unknown"
+		_, err := Render(notification)
+		assert.EqualError(t, err, "no template for notification kind `unknown`")
+	})
+}
@@ -0,0 +1,113 @@
+package routes
+
+import (
+	"app/db"
+	lg "app/logging"
+	"app/utils"
+	"app/weberrors"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ndjsonContentType = "application/x-ndjson"
+
+const auditExportBatchSize = 500
+
+// audit records action made by the request, `before` and `after` are documents it changed, nil if there is none.
+// Event mutations are recorded by db in their own transaction.
+func audit(ctx *gin.Context, action string, targetId string, before interface{}, after interface{}) {
+	documents := []string{"", ""}
+	for i, document := range []interface{}{before, after} {
+		if document == nil {
+			continue
+		}
+		documentJson, _ := json.Marshal(document)
+		documents[i] = string(documentJson)
+	}
+	if err := db.AppendAudit(ctx, action, targetId, documents[0], documents[1]); err != nil {
+		lg.WithContext(ctx).Error().Msgf("`%v` of `%v` not recorded in audit trail: %v", action, targetId, err)
+	}
+}
+
+// GetAuditHandler lists audit trail.
+// @Summary	Lists audit trail of data changes and failed authentications
+// @Description `from` and `to` are inclusive. Whole range is exported as NDJSON with `format=ndjson`
+// @Description or `Accept: application/x-ndjson`, `limit` does not apply then.
+// @Tags		Admin
+// @Produce json
+// @Produce application/x-ndjson
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param from query string false "RFC 3339 timestamp"
+// @Param to query string false "RFC 3339 timestamp"
+// @Param limit query int false "max number of entries (1-1000, default 100 unless `limits` are configured)"
+// @Param format query string false "`ndjson` for export"
+// @Success	200 {array} models.AuditEntry
+// @Failure 400,500 {object} weberrors.AppError
+// @Router		/admin/audit [get]
+func GetAuditHandler(ctx *gin.Context) {
+	start, end := "-", "+"
+	for _, bound := range []struct {
+		query string
+		id    *string
+	}{{"from", &start}, {"to", &end}} {
+		value := ctx.Query(bound.query)
+		if value == "" {
+			continue
+		}
+		boundTime, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			utils.AppendContextError(ctx, weberrors.ValidationError.WithMessage(
+				weberrors.InvalidTimestampQueryCode, bound.query))
+			return
+		}
+		*bound.id = strconv.FormatInt(boundTime.UnixMilli(), 10)
+	}
+	if ctx.Query("format") == "ndjson" || strings.Contains(ctx.GetHeader("Accept"), ndjsonContentType) {
+		exportAudit(ctx, start, end)
+		return
+	}
+	limit, ok := pageSize(ctx)
+	if !ok {
+		return
+	}
+	entries, err := db.GetAuditEntries(ctx, start, end, limit)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, entries)
+}
+
+// exportAudit writes entries one JSON per line in batches, so that export of any size fits in memory.
+func exportAudit(ctx *gin.Context, start string, end string) {
+	entries, err := db.GetAuditEntries(ctx, start, end, auditExportBatchSize)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusOK)
+	ctx.Header("Content-Type", ndjsonContentType)
+	encoder := json.NewEncoder(ctx.Writer)
+	for len(entries) > 0 {
+		for _, entry := range entries {
+			if err := encoder.Encode(entry); err != nil {
+				return
+			}
+		}
+		ctx.Writer.Flush()
+		if len(entries) < auditExportBatchSize {
+			return
+		}
+		start, _ = db.NextChangeId(entries[len(entries)-1].Id)
+		if entries, err = db.GetAuditEntries(ctx, start, end, auditExportBatchSize); err != nil {
+			// status is sent already, truncated export is all that can be done
+			lg.WithContext(ctx).Error().Msgf("error exporting audit trail: %v", err)
+			return
+		}
+	}
+}
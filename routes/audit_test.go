@@ -0,0 +1,208 @@
+package routes
+
+import (
+	"app/auth"
+	"app/db"
+	"app/models"
+	"app/utils"
+	"app/weberrors"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect"
+	"github.com/stretchr/testify/assert"
+)
+
+var auditEntries = []models.AuditEntry{
+	{
+		Id:            "1682000000000-0",
+		OccurredAt:    time.Date(2023, 4, 20, 14, 13, 20, 0, time.UTC),
+		Actor:         auth.AdminActor,
+		Action:        models.AuditEventDelete,
+		TargetId:      "5b8d3c5e-2d0a-4a8f-8b57-7c1e2a3b4c5d",
+		BeforeHash:    "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		ClientIp:      "192.0.2.10",
+		CorrelationId: "0b8a1f5e-4c1d-4f0e-9a57-1e6f4d7e2c11",
+	},
+}
+
+var GetAuditTestCases = []struct {
+	description      string
+	query            map[string]string
+	expectedStart    string
+	expectedEnd      string
+	expectedLimit    int64
+	expectedStatus   int
+	expectedResp     interface{}
+	expectDbGetAudit bool
+}{
+	{
+		description:      "Success - whole trail",
+		expectedStart:    "-",
+		expectedEnd:      "+",
+		expectedLimit:    100,
+		expectedStatus:   http.StatusOK,
+		expectedResp:     auditEntries,
+		expectDbGetAudit: true,
+	},
+	{
+		description:      "Success - time range",
+		query:            map[string]string{"from": "2023-04-20T14:13:20Z", "to": "2023-04-20T14:13:21.5Z", "limit": "10"},
+		expectedStart:    "1682000000000",
+		expectedEnd:      "1682000001500",
+		expectedLimit:    10,
+		expectedStatus:   http.StatusOK,
+		expectedResp:     auditEntries,
+		expectDbGetAudit: true,
+	},
+	{
+		description:    "Fail - invalid from",
+		query:          map[string]string{"from": "yesterday"},
+		expectedStatus: http.StatusBadRequest,
+		expectedResp: expectedAppError(weberrors.ValidationError.WithMessage(
+			weberrors.InvalidTimestampQueryCode, "from")),
+	},
+	{
+		description:    "Fail - invalid limit",
+		query:          map[string]string{"limit": "0"},
+		expectedStatus: http.StatusBadRequest,
+		expectedResp: expectedAppError(weberrors.ValidationError.WithMessage(
+			weberrors.InvalidLimitCode, 1000)),
+	},
+}
+
+func TestGetAudit(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	for _, testCase := range GetAuditTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			called := false
+			db.GetAuditEntries = func(c context.Context, start string, end string, limit int64) ([]models.AuditEntry, error) {
+				called = true
+				assert.Equal(t, testCase.expectedStart, start)
+				assert.Equal(t, testCase.expectedEnd, end)
+				assert.Equal(t, testCase.expectedLimit, limit)
+				return auditEntries, nil
+			}
+			request := testClient(t).GET("/admin/audit").
+				WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString)
+			for key, value := range testCase.query {
+				request = request.WithQuery(key, value)
+			}
+			res := request.Expect()
+			res.Status(testCase.expectedStatus)
+			res.JSON().Equal(testCase.expectedResp)
+			assert.Equal(t, testCase.expectDbGetAudit, called)
+		})
+	}
+	auth.AdminToken = originalToken
+}
+
+func TestExportAudit(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	var starts []string
+	db.GetAuditEntries = func(c context.Context, start string, end string, limit int64) ([]models.AuditEntry, error) {
+		starts = append(starts, start)
+		assert.Equal(t, "+", end)
+		entries := []models.AuditEntry{}
+		if len(starts) == 1 {
+			for i := int64(0); i < limit; i++ {
+				entries = append(entries, models.AuditEntry{Id: fmt.Sprintf("1682000000000-%v", i)})
+			}
+			return entries, nil
+		}
+		return auditEntries, nil
+	}
+
+	for description, request := range map[string]func(*httpexpect.Request) *httpexpect.Request{
+		"Export by query": func(request *httpexpect.Request) *httpexpect.Request {
+			return request.WithQuery("format", "ndjson")
+		},
+		"Export by Accept header": func(request *httpexpect.Request) *httpexpect.Request {
+			return request.WithHeader("Accept", ndjsonContentType)
+		},
+	} {
+		t.Run(description, func(t *testing.T) {
+			starts = nil
+			res := request(testClient(t).GET("/admin/audit").
+				WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString)).
+				Expect()
+
+			res.Status(http.StatusOK)
+			res.Header("Content-Type").Equal(ndjsonContentType)
+			lines := strings.Split(strings.TrimSpace(res.Body().Raw()), "\n")
+			assert.Len(t, lines, auditExportBatchSize+1, "should export all batches")
+			var lastEntry models.AuditEntry
+			assert.Nil(t, json.Unmarshal([]byte(lines[len(lines)-1]), &lastEntry))
+			assert.Equal(t, auditEntries[0], lastEntry)
+			assert.Equal(t, []string{"-", "1682000000000-500"}, starts, "should continue after last exported entry")
+		})
+	}
+	auth.AdminToken = originalToken
+}
+
+func TestAuditedActions(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	type auditCall struct{ action, targetId, before, after string }
+	t.Run("Webhook subscription deletion", func(t *testing.T) {
+		var audited []auditCall
+		client := testClient(t)
+		db.AppendAudit = func(c context.Context, action string, targetId string, before string, after string) error {
+			audited = append(audited, auditCall{action, targetId, before, after})
+			return nil
+		}
+		db.DeleteWebhookSubscription = func(c context.Context, id string) error {
+			return nil
+		}
+
+		client.DELETE(fmt.Sprintf("/webhooks/%v", webhookSubscriptionId)).
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			Expect().
+			Status(http.StatusNoContent)
+
+		assert.Equal(t, []auditCall{{models.AuditWebhookDelete, webhookSubscriptionId, "", ""}}, audited)
+	})
+
+	t.Run("Log level change records levels before and after", func(t *testing.T) {
+		var audited []auditCall
+		client := testClient(t)
+		db.AppendAudit = func(c context.Context, action string, targetId string, before string, after string) error {
+			audited = append(audited, auditCall{action, targetId, before, after})
+			return nil
+		}
+
+		client.DELETE("/admin/log-level/db").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			Expect().
+			Status(http.StatusOK)
+
+		assert.Equal(t, []auditCall{{
+			models.AuditLogLevelChange, "db",
+			`{"level":"info","packages":{}}`, `{"level":"info","packages":{}}`,
+		}}, audited)
+	})
+
+	t.Run("Failed authentication", func(t *testing.T) {
+		var audited []auditCall
+		client := testClient(t)
+		db.AppendAudit = func(c context.Context, action string, targetId string, before string, after string) error {
+			audited = append(audited, auditCall{action, targetId, before, after})
+			return nil
+		}
+
+		client.GET("/admin/audit").
+			WithHeader(utils.API_AUTH_HEADER_KEY, "invalid-token").
+			Expect().
+			Status(http.StatusUnauthorized)
+
+		assert.Equal(t, []auditCall{{models.AuditAuthFailure, "/admin/audit", "", ""}}, audited)
+	})
+	auth.AdminToken = originalToken
+}
@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"app/db"
+	"app/utils"
+	"app/weberrors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetChangesHandler lists event log.
+// @Summary	Lists domain events recorded for every event mutation
+// @Description `since` is exclusive when it is id of a change, inclusive when it is a timestamp.
+// @Description Next page is requested with id of the last returned change.
+// @Tags		Event
+// @Produce json
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param since query string false "change id or RFC 3339 timestamp"
+// @Param limit query int false "max number of changes (1-1000, default 100 unless `limits` are configured)"
+// @Success	200 {array} models.DomainEvent
+// @Failure 400,500 {object} weberrors.AppError
+// @Router		/admin/changes [get]
+func GetChangesHandler(ctx *gin.Context) {
+	start := "-"
+	if since := ctx.Query("since"); since != "" {
+		if streamIdRegex.MatchString(since) {
+			start, _ = db.NextChangeId(since)
+		} else if sinceTime, err := time.Parse(time.RFC3339, since); err == nil {
+			start = fmt.Sprintf("%v-0", sinceTime.UnixMilli())
+		} else {
+			utils.AppendContextError(ctx, weberrors.ValidationError.WithMessage(weberrors.InvalidSinceQueryCode))
+			return
+		}
+	}
+	limit, ok := pageSize(ctx)
+	if !ok {
+		return
+	}
+	changes, err := db.GetChanges(ctx, start, limit)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, changes)
+}
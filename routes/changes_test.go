@@ -0,0 +1,107 @@
+package routes
+
+import (
+	"app/auth"
+	"app/db"
+	"app/models"
+	"app/utils"
+	"app/weberrors"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var GetChangesTestCases = []struct {
+	description        string
+	since              string
+	limit              string
+	dbGetChangesErr    error
+	expectedStart      string
+	expectedLimit      int64
+	expectedStatus     int
+	expectedResp       interface{}
+	expectDbGetChanges bool
+}{
+	{
+		description:        "Success - from the oldest change",
+		expectedStart:      "-",
+		expectedLimit:      100,
+		expectedStatus:     http.StatusOK,
+		expectedResp:       streamedChanges,
+		expectDbGetChanges: true,
+	},
+	{
+		description:        "Success - after change id",
+		since:              "1682000000000-0",
+		limit:              "2",
+		expectedStart:      "1682000000000-1",
+		expectedLimit:      2,
+		expectedStatus:     http.StatusOK,
+		expectedResp:       streamedChanges,
+		expectDbGetChanges: true,
+	},
+	{
+		description:        "Success - since timestamp",
+		since:              "2023-04-20T14:13:20Z",
+		expectedStart:      "1682000000000-0",
+		expectedLimit:      100,
+		expectedStatus:     http.StatusOK,
+		expectedResp:       streamedChanges,
+		expectDbGetChanges: true,
+	},
+	{
+		description:    "Fail - invalid since",
+		since:          "yesterday",
+		expectedStatus: http.StatusBadRequest,
+		expectedResp: expectedAppError(weberrors.ValidationError.WithMessage(
+			weberrors.InvalidSinceQueryCode)),
+	},
+	{
+		description:    "Fail - limit out of range",
+		limit:          "1001",
+		expectedStatus: http.StatusBadRequest,
+		expectedResp: expectedAppError(weberrors.ValidationError.WithMessage(
+			weberrors.InvalidLimitCode, 1000)),
+	},
+	{
+		description:        "Fail - db error",
+		dbGetChangesErr:    errors.New("redis connection error"),
+		expectedStart:      "-",
+		expectedLimit:      100,
+		expectedStatus:     http.StatusInternalServerError,
+		expectedResp:       expectedAppError(&weberrors.InternalError),
+		expectDbGetChanges: true,
+	},
+}
+
+func TestGetChanges(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	for _, testCase := range GetChangesTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			called := false
+			db.GetChanges = func(c context.Context, start string, limit int64) ([]models.DomainEvent, error) {
+				called = true
+				assert.Equal(t, testCase.expectedStart, start)
+				assert.Equal(t, testCase.expectedLimit, limit)
+				return streamedChanges, testCase.dbGetChangesErr
+			}
+			request := testClient(t).GET("/admin/changes").
+				WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString)
+			if testCase.since != "" {
+				request = request.WithQuery("since", testCase.since)
+			}
+			if testCase.limit != "" {
+				request = request.WithQuery("limit", testCase.limit)
+			}
+			res := request.Expect()
+			res.Status(testCase.expectedStatus)
+			res.JSON().Equal(testCase.expectedResp)
+			assert.Equal(t, testCase.expectDbGetChanges, called)
+		})
+	}
+	auth.AdminToken = originalToken
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"app/db"
+	"app/streaming"
+	"app/weberrors"
+)
+
+// domainErrors maps errors of storage and streaming to errors reported to client, see weberrors.FromError
+var domainErrors = []weberrors.DomainError{
+	{Err: db.ErrNotFound, AppError: &weberrors.NotFound},
+	{Err: db.ErrConflict, AppError: &weberrors.ConcurrentUpdate},
+	{Err: db.ErrUnavailable, AppError: &weberrors.ServiceUnavailable},
+	{Err: db.ErrInvalid, AppError: &weberrors.InternalError},
+	{Err: streaming.ErrNoRenditions, AppError: &weberrors.NoRenditions},
+}
+
+func init() {
+	weberrors.MapErrors(domainErrors...)
+}
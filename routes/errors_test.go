@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"app/db"
+	"app/streaming"
+	"app/weberrors"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		err         error
+		expected    *weberrors.AppErrorWithCode
+	}{
+		{description: "Storage not found", err: &db.Error{Kind: db.ErrNotFound, Op: "get event", Key: "id"},
+			expected: &weberrors.NotFound},
+		{description: "Wrapped storage conflict", err: fmt.Errorf("updating: %w", &db.Error{Kind: db.ErrConflict}),
+			expected: &weberrors.ConcurrentUpdate},
+		{description: "Storage unavailable", err: db.ErrUnavailable, expected: &weberrors.ServiceUnavailable},
+		{description: "Unreadable stored data", err: &db.Error{Kind: db.ErrInvalid}, expected: &weberrors.InternalError},
+		{description: "No renditions", err: streaming.ErrNoRenditions, expected: &weberrors.NoRenditions},
+		{description: "Unknown error", err: errors.New("unexpected"), expected: &weberrors.InternalError},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, weberrors.FromError(testCase.err))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"app/config"
+	"app/utils"
+	"app/weberrors"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// limits are replaced as a whole on configuration reload
+var limits atomic.Pointer[config.Limits]
+
+func init() {
+	SetLimits(config.Default().Limits)
+}
+
+// SetLimits replaces page sizes of listing endpoints, `settings` are validated already.
+func SetLimits(settings config.Limits) {
+	limits.Store(&settings)
+}
+
+// pageSize returns `limit` query or the default, error is appended to `ctx` if it is out of range.
+func pageSize(ctx *gin.Context) (int64, bool) {
+	current := limits.Load()
+	query := ctx.Query("limit")
+	if query == "" {
+		return current.DefaultPageSize, true
+	}
+	limit, err := strconv.ParseInt(query, 10, 64)
+	if err != nil || limit < 1 || limit > current.MaxPageSize {
+		utils.AppendContextError(ctx, weberrors.ValidationError.WithMessage(weberrors.InvalidLimitCode, current.MaxPageSize))
+		return 0, false
+	}
+	return limit, true
+}
@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"app/auth"
+	"app/config"
+	"app/db"
+	"app/models"
+	"app/utils"
+	"app/weberrors"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetLimits(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	defer func() {
+		auth.AdminToken = originalToken
+		SetLimits(config.Default().Limits)
+	}()
+	var requestedLimit int64
+	db.GetChanges = func(c context.Context, start string, limit int64) ([]models.DomainEvent, error) {
+		requestedLimit = limit
+		return []models.DomainEvent{}, nil
+	}
+
+	SetLimits(config.Limits{DefaultPageSize: 5, MaxPageSize: 10})
+
+	t.Run("Configured default applies", func(t *testing.T) {
+		testClient(t).GET("/admin/changes").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			Expect().
+			Status(http.StatusOK)
+		assert.Equal(t, int64(5), requestedLimit)
+	})
+
+	t.Run("Fail - configured maximum applies", func(t *testing.T) {
+		res := testClient(t).GET("/admin/changes").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			WithQuery("limit", "11").
+			Expect()
+		res.Status(http.StatusBadRequest)
+		res.JSON().Equal(expectedAppError(weberrors.ValidationError.WithMessage(
+			weberrors.InvalidLimitCode, 10)))
+	})
+}
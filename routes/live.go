@@ -0,0 +1,163 @@
+package routes
+
+import (
+	"app/auth"
+	"app/db"
+	"app/lifecycle"
+	"app/live"
+	lg "app/logging"
+	"app/models"
+	"app/utils"
+	"app/validations"
+	"app/weberrors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// pages of other origins than the service's own host have to be allowed by LIVE_ALLOWED_ORIGINS
+var upgrader = websocket.Upgrader{
+	CheckOrigin: auth.CheckOrigin,
+}
+
+func initLiveRoutes(app *gin.Engine, adminGroup *gin.RouterGroup) {
+	app.GET("/event/:id/ws", LiveEventHandler)
+	adminGroup.POST("/event/:id/access", CreateEventAccessHandler)
+	adminGroup.GET("/event/:id/presence", GetEventPresenceHandler)
+}
+
+// getEvent retrieves event with resolved status, appends error to context on failure.
+func getEvent(ctx *gin.Context) (models.EventResponseData, bool) {
+	id := ctx.Param("id")
+	if !validations.CheckUuidFormat(id) {
+		utils.AppendContextError(ctx, &weberrors.NotFound)
+		return models.EventResponseData{}, false
+	}
+	event, err := db.GetEvent(ctx, id)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return event, false
+	}
+	resolveEventStatus(&event)
+	return event, true
+}
+
+func findInvitee(event models.EventResponseData, email string) (string, bool) {
+	for _, invitee := range event.Invitees {
+		if strings.EqualFold(invitee, email) {
+			return invitee, true
+		}
+	}
+	return "", false
+}
+
+// LiveEventHandler opens live channel of event.
+// @Summary	Opens WebSocket live channel of `live` event
+// @Description Invitees authenticate by `email` & `token` query (see `POST /event/{id}/access`), organizers by admin token header.
+// @Description Server sends JSON messages `{"type":"announcement","message":"...","sentAt":"..."}` and `{"type":"presence","count":1}`, organizers also receive `viewers`.
+// @Description Organizers broadcast by sending `{"type":"announcement","message":"..."}`.
+// @Description Browsers may connect from pages of the service's own host or of origins allowed by `LIVE_ALLOWED_ORIGINS`, others get 403.
+// @Tags		Live
+// @Param API-AUTHENTICATION header 	string 	false "token string value"
+// @Param id path string true "Event ID (uuid)"
+// @Param email query string false "Invitee email"
+// @Param token query string false "Invitee access token"
+// @Success	101
+// @Failure 401,404,409,500 {object} weberrors.AppError
+// @Failure 403
+// @Router		/event/{id}/ws [get]
+func LiveEventHandler(ctx *gin.Context) {
+	event, ok := getEvent(ctx)
+	if !ok {
+		return
+	}
+	viewer := live.Viewer{EventId: event.Id, Organizer: auth.IsAdmin(ctx)}
+	if !viewer.Organizer {
+		email := ctx.Query("email")
+		invitee, isInvitee := findInvitee(event, email)
+		if !isInvitee || !auth.VerifyEventAccess(event.Id, email, ctx.Query("token")) {
+			audit(ctx, models.AuditAuthFailure, event.Id, nil, nil)
+			utils.AppendContextError(ctx, &weberrors.InvalidEventAccess)
+			return
+		}
+		viewer.Identity = invitee
+	}
+	if event.Status != lifecycle.Live {
+		utils.AppendContextError(ctx, &weberrors.EventNotLive)
+		return
+	}
+	conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		// upgrader has already responded
+		lg.WithContext(ctx).Debug().Msgf("websocket upgrade failed: %v", err)
+		return
+	}
+	live.Serve(conn, viewer)
+}
+
+// CreateEventAccessHandler issues invitee access token.
+// @Summary	Issues token granting invitee access to event's live channel
+// @Tags		Live
+// @Accept json
+// @Produce json
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param id path string true "Event ID (uuid)"
+// @Param access body models.EventAccessRequest true "Invitee"
+// @Success	201 {object} models.EventAccess
+// @Failure 400,404,500 {object} weberrors.AppError
+// @Router		/event/{id}/access [post]
+func CreateEventAccessHandler(ctx *gin.Context) {
+	payload := models.EventAccessRequest{}
+	bindError := ctx.ShouldBind(&payload)
+	if bindError != nil {
+		if parsedErr := validations.GetBindErrors(ctx, bindError); parsedErr != nil {
+			utils.AppendContextError(ctx, parsedErr)
+			return
+		}
+		utils.AppendContextError(ctx, &weberrors.InvalidPayload)
+		return
+	}
+	event, ok := getEvent(ctx)
+	if !ok {
+		return
+	}
+	if _, isInvitee := findInvitee(event, payload.Email); !isInvitee {
+		utils.AppendContextError(ctx, weberrors.ValidationError.WithMessage(weberrors.NotInviteeCode, weberrors.Field("email")))
+		return
+	}
+	if auth.EventAccessSecret == "" {
+		lg.WithContext(ctx).Error().Err(auth.ErrNoEventAccessSecret).Msg("Event access cannot be granted")
+		utils.AppendContextError(ctx, auth.ErrNoEventAccessSecret)
+		return
+	}
+	audit(ctx, models.AuditEventAccessGrant, event.Id, nil, payload)
+	ctx.JSON(http.StatusCreated, models.EventAccess{
+		Email: payload.Email,
+		Token: auth.EventAccessToken(event.Id, payload.Email),
+	})
+}
+
+// GetEventPresenceHandler lists viewers.
+// @Summary	Lists invitees connected to event's live channel
+// @Tags		Live
+// @Produce json
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param id path string true "Event ID (uuid)"
+// @Success	200 {object} models.Presence
+// @Failure 404,500 {object} weberrors.AppError
+// @Router		/event/{id}/presence [get]
+func GetEventPresenceHandler(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if !validations.CheckUuidFormat(id) {
+		utils.AppendContextError(ctx, &weberrors.NotFound)
+		return
+	}
+	viewers, err := live.Viewers(ctx, id)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, models.Presence{Count: len(viewers), Viewers: viewers})
+}
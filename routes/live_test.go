@@ -0,0 +1,307 @@
+package routes
+
+import (
+	"app/auth"
+	"app/config"
+	"app/db"
+	"app/lifecycle"
+	"app/live"
+	lg "app/logging"
+	"app/models"
+	"app/utils"
+	"app/validations"
+	"app/weberrors"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	testFuncs "app/testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+const liveEventId = "90a04b08-d820-4106-8ced-2cbc940728a3"
+
+func liveEvent(status string) models.EventResponseData {
+	return models.EventResponseData{
+		Id: liveEventId,
+		EventData: models.EventData{
+			Name:      "event-name",
+			Timestamp: time.Now().UTC().Format(utils.TIMESTAMP_LAYOUT),
+			Invitees:  []string{"valid-email@mail.com"},
+			Status:    status,
+		},
+	}
+}
+
+// mockLiveChannel replaces Redis presence & Pub/Sub by in-memory fan-out
+func mockLiveChannel(t *testing.T) {
+	var lock sync.Mutex
+	subscribers := []chan string{}
+	presence := map[string]bool{}
+	db.SubscribeLiveMessages = func(c context.Context, eventId string) (<-chan string, error) {
+		assert.Equal(t, liveEventId, eventId)
+		messages := make(chan string, 10)
+		lock.Lock()
+		subscribers = append(subscribers, messages)
+		lock.Unlock()
+		return messages, nil
+	}
+	db.PublishLiveMessage = func(c context.Context, eventId string, message string) error {
+		lock.Lock()
+		defer lock.Unlock()
+		for _, subscriber := range subscribers {
+			subscriber <- message
+		}
+		return nil
+	}
+	db.TouchPresence = func(c context.Context, eventId string, member string, now time.Time, ttl time.Duration) error {
+		lock.Lock()
+		defer lock.Unlock()
+		presence[member] = true
+		return nil
+	}
+	db.RemovePresence = func(c context.Context, eventId string, member string) error {
+		lock.Lock()
+		defer lock.Unlock()
+		delete(presence, member)
+		return nil
+	}
+	db.GetPresence = func(c context.Context, eventId string, now time.Time, ttl time.Duration) ([]string, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		members := []string{}
+		for member := range presence {
+			members = append(members, member)
+		}
+		return members, nil
+	}
+}
+
+func dialLive(t *testing.T, server *httptest.Server, query string, adminToken string) (*websocket.Conn, *http.Response, error) {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/event/" + liveEventId + "/ws" + query
+	header := http.Header{}
+	header.Set(lg.CorrelationIdHeader, testFuncs.CorrelationId)
+	if adminToken != "" {
+		header.Set(utils.API_AUTH_HEADER_KEY, adminToken)
+	}
+	return websocket.DefaultDialer.Dial(url, header)
+}
+
+func readLiveMessage(t *testing.T, conn *websocket.Conn, messageType string) live.Message {
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		message := live.Message{}
+		if err := conn.ReadJSON(&message); err != nil {
+			t.Fatalf("reading `%v` message: %v", messageType, err)
+		}
+		if message.Type == messageType {
+			return message
+		}
+	}
+}
+
+func liveTestServer(t *testing.T, event models.EventResponseData) *httptest.Server {
+	validations.CheckUuidFormat = func(inputString string) bool {
+		return true
+	}
+	db.GetEvent = func(c context.Context, id string) (models.EventResponseData, error) {
+		assert.Equal(t, liveEventId, id)
+		return event, nil
+	}
+	db.UpdateEvent = func(c context.Context, id string, payload models.EventData) error {
+		assert.Fail(t, "reading event should not update it")
+		return nil
+	}
+	mockLiveChannel(t)
+	app := gin.New()
+	InitApp(app, config.Default())
+	return httptest.NewServer(app)
+}
+
+func TestLiveEvent(t *testing.T) {
+	originalToken, originalSecret := auth.AdminToken, auth.EventAccessSecret
+	auth.AdminToken, auth.EventAccessSecret = adminTokenTestString, "event-access-secret"
+	inviteeQuery := "?email=valid-email@mail.com&token=" + auth.EventAccessToken(liveEventId, "valid-email@mail.com")
+
+	t.Run("Success - organizer announcement is broadcast to invitee", func(t *testing.T) {
+		server := liveTestServer(t, liveEvent(lifecycle.Live))
+		defer server.Close()
+
+		invitee, _, err := dialLive(t, server, inviteeQuery, "")
+		assert.Nil(t, err)
+		defer invitee.Close()
+		assert.Equal(t, 1, readLiveMessage(t, invitee, live.MessagePresence).Count)
+
+		organizer, _, err := dialLive(t, server, "", adminTokenTestString)
+		assert.Nil(t, err)
+		defer organizer.Close()
+		presence := readLiveMessage(t, organizer, live.MessagePresence)
+		assert.Equal(t, []string{"valid-email@mail.com"}, presence.Viewers)
+
+		err = organizer.WriteJSON(live.Message{Type: live.MessageAnnouncement, Message: "starting soon"})
+		assert.Nil(t, err)
+		announcement := readLiveMessage(t, invitee, live.MessageAnnouncement)
+		assert.Equal(t, "starting soon", announcement.Message)
+		assert.NotNil(t, announcement.SentAt)
+	})
+
+	t.Run("Success - invitee messages are not broadcast", func(t *testing.T) {
+		server := liveTestServer(t, liveEvent(lifecycle.Live))
+		defer server.Close()
+
+		invitee, _, err := dialLive(t, server, inviteeQuery, "")
+		assert.Nil(t, err)
+		defer invitee.Close()
+		readLiveMessage(t, invitee, live.MessagePresence)
+		assert.Nil(t, invitee.WriteJSON(live.Message{Type: live.MessageAnnouncement, Message: "spam"}))
+
+		organizer, _, err := dialLive(t, server, "", adminTokenTestString)
+		assert.Nil(t, err)
+		defer organizer.Close()
+		readLiveMessage(t, organizer, live.MessagePresence)
+		assert.Nil(t, organizer.WriteJSON(live.Message{Type: live.MessageAnnouncement, Message: "starting soon"}))
+		assert.Equal(t, "starting soon", readLiveMessage(t, invitee, live.MessageAnnouncement).Message)
+	})
+
+	t.Run("Origins - pages of other sites have to be allowed", func(t *testing.T) {
+		originalOrigins := auth.LiveOrigins
+		auth.LiveOrigins = []string{"https://app.local"}
+		defer func() { auth.LiveOrigins = originalOrigins }()
+		server := liveTestServer(t, liveEvent(lifecycle.Live))
+		defer server.Close()
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "/event/" + liveEventId + "/ws" + inviteeQuery
+
+		_, response, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": {"https://evil.local"}})
+		assert.Equal(t, websocket.ErrBadHandshake, err)
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+		invitee, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": {"https://app.local"}})
+		assert.Nil(t, err)
+		defer invitee.Close()
+		readLiveMessage(t, invitee, live.MessagePresence)
+	})
+
+	failCases := []struct {
+		description    string
+		event          models.EventResponseData
+		query          string
+		expectedStatus int
+		expectedResp   *weberrors.AppErrorWithCode
+	}{
+		{
+			description:    "Fail - invalid token",
+			event:          liveEvent(lifecycle.Live),
+			query:          "?email=valid-email@mail.com&token=invalid",
+			expectedStatus: http.StatusUnauthorized,
+			expectedResp:   &weberrors.InvalidEventAccess,
+		},
+		{
+			description:    "Fail - not an invitee",
+			event:          liveEvent(lifecycle.Live),
+			query:          "?email=other-email@mail.com&token=" + auth.EventAccessToken(liveEventId, "other-email@mail.com"),
+			expectedStatus: http.StatusUnauthorized,
+			expectedResp:   &weberrors.InvalidEventAccess,
+		},
+		{
+			description:    "Fail - event not live",
+			event:          liveEvent(lifecycle.Draft),
+			query:          inviteeQuery,
+			expectedStatus: http.StatusConflict,
+			expectedResp:   &weberrors.EventNotLive,
+		},
+	}
+	for _, testCase := range failCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			server := liveTestServer(t, testCase.event)
+			defer server.Close()
+
+			_, response, err := dialLive(t, server, testCase.query, "")
+			assert.Equal(t, websocket.ErrBadHandshake, err)
+			assert.Equal(t, testCase.expectedStatus, response.StatusCode)
+			body, _ := io.ReadAll(response.Body)
+			expectedBody, _ := json.Marshal(expectedAppError(testCase.expectedResp))
+			assert.JSONEq(t, string(expectedBody), string(body))
+		})
+	}
+	auth.AdminToken, auth.EventAccessSecret = originalToken, originalSecret
+}
+
+func TestCreateEventAccess(t *testing.T) {
+	originalToken, originalSecret := auth.AdminToken, auth.EventAccessSecret
+	auth.AdminToken, auth.EventAccessSecret = adminTokenTestString, "event-access-secret"
+	validations.CheckUuidFormat = func(inputString string) bool {
+		return true
+	}
+	db.GetEvent = func(c context.Context, id string) (models.EventResponseData, error) {
+		return liveEvent(lifecycle.Scheduled), nil
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		res := testClient(t).POST("/event/"+liveEventId+"/access").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			WithJSON(models.EventAccessRequest{Email: "valid-email@mail.com"}).
+			Expect()
+		res.Status(http.StatusCreated)
+		res.JSON().Equal(models.EventAccess{
+			Email: "valid-email@mail.com",
+			Token: auth.EventAccessToken(liveEventId, "valid-email@mail.com"),
+		})
+	})
+	t.Run("Fail - not an invitee", func(t *testing.T) {
+		res := testClient(t).POST("/event/"+liveEventId+"/access").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			WithJSON(models.EventAccessRequest{Email: "other-email@mail.com"}).
+			Expect()
+		res.Status(http.StatusBadRequest)
+	})
+	t.Run("Fail - access secret not set", func(t *testing.T) {
+		auth.EventAccessSecret = ""
+		res := testClient(t).POST("/event/"+liveEventId+"/access").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			WithJSON(models.EventAccessRequest{Email: "valid-email@mail.com"}).
+			Expect()
+		res.Status(http.StatusInternalServerError)
+		res.JSON().Equal(expectedAppError(&weberrors.InternalError))
+	})
+	auth.AdminToken, auth.EventAccessSecret = originalToken, originalSecret
+}
+
+func TestGetEventPresence(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	validations.CheckUuidFormat = func(inputString string) bool {
+		return true
+	}
+
+	t.Run("Success - connections of same invitee count once", func(t *testing.T) {
+		db.GetPresence = func(c context.Context, eventId string, now time.Time, ttl time.Duration) ([]string, error) {
+			assert.Equal(t, liveEventId, eventId)
+			return []string{"b@mail.com#1", "a@mail.com#2", "b@mail.com#3"}, nil
+		}
+		res := testClient(t).GET("/event/"+liveEventId+"/presence").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			Expect()
+		res.Status(http.StatusOK)
+		res.JSON().Equal(models.Presence{Count: 2, Viewers: []string{"a@mail.com", "b@mail.com"}})
+	})
+	t.Run("Fail - db error", func(t *testing.T) {
+		db.GetPresence = func(c context.Context, eventId string, now time.Time, ttl time.Duration) ([]string, error) {
+			return nil, errors.New("redis connection error")
+		}
+		res := testClient(t).GET("/event/"+liveEventId+"/presence").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			Expect()
+		res.Status(http.StatusInternalServerError)
+	})
+	auth.AdminToken = originalToken
+}
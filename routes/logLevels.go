@@ -0,0 +1,84 @@
+package routes
+
+import (
+	lg "app/logging"
+	"app/models"
+	"app/utils"
+	"app/validations"
+	"app/weberrors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func initLogLevelRoutes(adminGroup *gin.RouterGroup) {
+	adminGroup.GET("/admin/log-level", GetLogLevelsHandler)
+	adminGroup.PUT("/admin/log-level", SetLogLevelHandler)
+	adminGroup.DELETE("/admin/log-level/:package", ResetLogLevelHandler)
+}
+
+func currentLogLevels() models.LogLevels {
+	level, packageLevels := lg.Levels()
+	logLevels := models.LogLevels{Level: level.String(), Packages: map[string]string{}}
+	for name, packageLevel := range packageLevels {
+		logLevels.Packages[name] = packageLevel.String()
+	}
+	return logLevels
+}
+
+// GetLogLevelsHandler shows log levels.
+// @Summary	Shows levels logs are written at
+// @Tags		Admin
+// @Produce json
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Success	200 {object} models.LogLevels
+// @Router		/admin/log-level [get]
+func GetLogLevelsHandler(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, currentLogLevels())
+}
+
+// SetLogLevelHandler changes log level.
+// @Summary	Changes global or package log level until restart
+// @Tags		Admin
+// @Accept json
+// @Produce json
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param level body models.LogLevelRequest true "Log level"
+// @Success	200 {object} models.LogLevels
+// @Failure 400 {object} weberrors.AppError
+// @Router		/admin/log-level [put]
+func SetLogLevelHandler(ctx *gin.Context) {
+	payload := models.LogLevelRequest{}
+	bindError := ctx.ShouldBind(&payload)
+	if bindError != nil {
+		if parsedErr := validations.GetBindErrors(ctx, bindError); parsedErr != nil {
+			utils.AppendContextError(ctx, parsedErr)
+			return
+		}
+		utils.AppendContextError(ctx, &weberrors.InvalidPayload)
+		return
+	}
+	before := currentLogLevels()
+	level, _ := lg.ParseLevel(payload.Level)
+	lg.SetLevel(payload.Package, level)
+	lg.WithContext(ctx).Info().Msgf("log level of `%v` changed to %v", payload.Package, level)
+	after := currentLogLevels()
+	audit(ctx, models.AuditLogLevelChange, payload.Package, before, after)
+	ctx.JSON(http.StatusOK, after)
+}
+
+// ResetLogLevelHandler removes package log level.
+// @Summary	Makes package log at global level again
+// @Tags		Admin
+// @Produce json
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param package path string true "package name"
+// @Success	200 {object} models.LogLevels
+// @Router		/admin/log-level/{package} [delete]
+func ResetLogLevelHandler(ctx *gin.Context) {
+	before := currentLogLevels()
+	lg.ResetLevel(ctx.Param("package"))
+	after := currentLogLevels()
+	audit(ctx, models.AuditLogLevelChange, ctx.Param("package"), before, after)
+	ctx.JSON(http.StatusOK, after)
+}
@@ -0,0 +1,81 @@
+package routes
+
+import (
+	"app/auth"
+	lg "app/logging"
+	"app/models"
+	"app/utils"
+	"app/weberrors"
+	"net/http"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+var SetLogLevelTestCases = []struct {
+	description    string
+	payload        interface{}
+	expectedStatus int
+	expectedResp   interface{}
+}{
+	{
+		description:    "Success - package level",
+		payload:        models.LogLevelRequest{Level: "debug", Package: "db"},
+		expectedStatus: http.StatusOK,
+		expectedResp: models.LogLevels{
+			Level:    "info",
+			Packages: map[string]string{"db": "debug"},
+		},
+	},
+	{
+		description:    "Success - global level",
+		payload:        models.LogLevelRequest{Level: "warn"},
+		expectedStatus: http.StatusOK,
+		expectedResp:   models.LogLevels{Level: "warn", Packages: map[string]string{}},
+	},
+	{
+		description:    "Fail - unknown level",
+		payload:        models.LogLevelRequest{Level: "verbose"},
+		expectedStatus: http.StatusBadRequest,
+		expectedResp: expectedAppError(weberrors.ValidationError.ChangeDesc(
+			"field `level` needs to be one of values: trace debug info warn error disabled")),
+	},
+}
+
+func TestSetLogLevel(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	for _, testCase := range SetLogLevelTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			defer lg.SetLevel("", zerolog.InfoLevel)
+			defer lg.ResetLevel("db")
+
+			res := testClient(t).PUT("/admin/log-level").
+				WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+				WithJSON(testCase.payload).
+				Expect()
+
+			res.Status(testCase.expectedStatus)
+			res.JSON().Equal(testCase.expectedResp)
+		})
+	}
+	auth.AdminToken = originalToken
+}
+
+func TestResetLogLevel(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	lg.SetLevel("db", zerolog.DebugLevel)
+
+	testClient(t).GET("/admin/log-level").
+		WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Equal(models.LogLevels{Level: "info", Packages: map[string]string{"db": "debug"}})
+	testClient(t).DELETE("/admin/log-level/db").
+		WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+		Expect().
+		Status(http.StatusOK).
+		JSON().Equal(models.LogLevels{Level: "info", Packages: map[string]string{}})
+	auth.AdminToken = originalToken
+}
@@ -0,0 +1,64 @@
+package routes
+
+import (
+	"app/db"
+	"app/models"
+	"app/streaming"
+	"app/utils"
+	"app/validations"
+	"app/weberrors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const hlsContentType = "application/vnd.apple.mpegurl"
+const dashContentType = "application/dash+xml"
+
+// GetHLSMasterPlaylistHandler generates HLS master playlist of event.
+// @Summary	Generates HLS master playlist of event renditions
+// @Description Variants pair every video quality with every audio quality, bandwidth, resolution and codecs
+// @Description come from the quality catalog. Stream URLs are configured by `streams` settings.
+// @Tags		Event
+// @Param id path string true "Event ID (uuid)"
+// @Produce application/vnd.apple.mpegurl
+// @Success	200 {string} string
+// @Failure 404,409,500 {object} weberrors.AppError
+// @Router		/event/{id}/master.m3u8 [get]
+func GetHLSMasterPlaylistHandler(ctx *gin.Context) {
+	writeManifest(ctx, hlsContentType, streaming.HLSMaster)
+}
+
+// GetDASHManifestHandler generates MPEG-DASH manifest of event.
+// @Summary	Generates live MPEG-DASH manifest of event renditions
+// @Description Bandwidth, resolution and codecs come from the quality catalog, segment duration follows
+// @Description latency mode. Stream URLs are configured by `streams` settings.
+// @Tags		Event
+// @Param id path string true "Event ID (uuid)"
+// @Produce application/dash+xml
+// @Success	200 {string} string
+// @Failure 404,409,500 {object} weberrors.AppError
+// @Router		/event/{id}/manifest.mpd [get]
+func GetDASHManifestHandler(ctx *gin.Context) {
+	writeManifest(ctx, dashContentType, streaming.DASHManifest)
+}
+
+func writeManifest(ctx *gin.Context, contentType string, generate func(models.EventResponseData) (string, error)) {
+	id := ctx.Param("id")
+	if !validations.CheckUuidFormat(id) {
+		utils.AppendContextError(ctx, &weberrors.NotFound)
+		return
+	}
+	event, err := db.GetEvent(ctx, id)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	validations.NormalizeLanguages(&event.EventData)
+	manifest, err := generate(event)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	ctx.Data(http.StatusOK, contentType, []byte(manifest))
+}
@@ -0,0 +1,111 @@
+package routes
+
+import (
+	"app/catalog"
+	"app/db"
+	"app/models"
+	"app/validations"
+	"app/weberrors"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+var manifestEvent = models.EventResponseData{
+	Id: "90a04b08-d820-4106-8ced-2cbc940728a3",
+	EventData: models.EventData{
+		Name:         "event-name",
+		Timestamp:    "2023-04-20T14:00:00Z",
+		Languages:    []string{"English"},
+		VideoQuality: []string{"1080p"},
+		AudioQuality: []string{"Mid"},
+	},
+}
+
+var ManifestTestCases = []struct {
+	description                    string
+	submitIdPathParam              string
+	validationsCheckUuidFormatResp bool
+	catalogProfiles                []models.QualityProfile
+	dbGetEventMockErr              error
+	expectedStatus                 int
+	expectedResp                   interface{}
+}{
+	{
+		description:                    "Success",
+		submitIdPathParam:              "90a04b08-d820-4106-8ced-2cbc940728a3",
+		validationsCheckUuidFormatResp: true,
+		catalogProfiles:                catalog.DefaultProfiles,
+		expectedStatus:                 http.StatusOK,
+	},
+	{
+		description:                    "Fail - invalid uuid - resource not found",
+		submitIdPathParam:              "invalid-uuid-string",
+		validationsCheckUuidFormatResp: false,
+		catalogProfiles:                catalog.DefaultProfiles,
+		expectedStatus:                 http.StatusNotFound,
+		expectedResp:                   expectedAppError(&weberrors.NotFound),
+	},
+	{
+		description:                    "Fail - event does not exist",
+		submitIdPathParam:              "90a04b08-d820-4106-8ced-2cbc940728a3",
+		validationsCheckUuidFormatResp: true,
+		catalogProfiles:                catalog.DefaultProfiles,
+		dbGetEventMockErr:              db.ErrNotFound,
+		expectedStatus:                 http.StatusNotFound,
+		expectedResp:                   expectedAppError(&weberrors.NotFound),
+	},
+	{
+		description:                    "Fail - video qualities removed from the catalog",
+		submitIdPathParam:              "90a04b08-d820-4106-8ced-2cbc940728a3",
+		validationsCheckUuidFormatResp: true,
+		catalogProfiles:                catalog.DefaultProfiles[4:],
+		expectedStatus:                 http.StatusConflict,
+		expectedResp:                   expectedAppError(&weberrors.NoRenditions),
+	},
+	{
+		description:                    "Fail - db unexpected error",
+		submitIdPathParam:              "90a04b08-d820-4106-8ced-2cbc940728a3",
+		validationsCheckUuidFormatResp: true,
+		catalogProfiles:                catalog.DefaultProfiles,
+		dbGetEventMockErr:              errors.New("redis connection error"),
+		expectedStatus:                 http.StatusInternalServerError,
+		expectedResp:                   expectedAppError(&weberrors.InternalError),
+	},
+}
+
+func TestManifests(t *testing.T) {
+	defer catalog.Set(catalog.DefaultProfiles, map[string]models.TenantQualities{})
+	manifests := []struct {
+		path        string
+		contentType string
+		contains    string
+	}{
+		{path: "master.m3u8", contentType: hlsContentType, contains: "RESOLUTION=1920x1080"},
+		{path: "manifest.mpd", contentType: dashContentType, contains: `width="1920" height="1080"`},
+	}
+	for _, manifest := range manifests {
+		for _, testCase := range ManifestTestCases {
+			t.Run(manifest.path+" "+testCase.description, func(t *testing.T) {
+				catalog.Set(testCase.catalogProfiles, map[string]models.TenantQualities{})
+				validations.CheckUuidFormat = func(inputString string) bool {
+					return testCase.validationsCheckUuidFormatResp
+				}
+				db.GetEvent = func(c context.Context, id string) (models.EventResponseData, error) {
+					return manifestEvent, testCase.dbGetEventMockErr
+				}
+				res := testClient(t).GET(
+					fmt.Sprintf("/event/%v/%v", testCase.submitIdPathParam, manifest.path)).Expect()
+				res.Status(testCase.expectedStatus)
+				if testCase.expectedStatus != http.StatusOK {
+					res.JSON().Equal(testCase.expectedResp)
+					return
+				}
+				res.Header("Content-Type").Equal(manifest.contentType)
+				res.Body().Contains(manifest.contains)
+			})
+		}
+	}
+}
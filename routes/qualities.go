@@ -0,0 +1,322 @@
+package routes
+
+import (
+	"app/catalog"
+	"app/db"
+	lg "app/logging"
+	"app/models"
+	"app/utils"
+	"app/validations"
+	"app/weberrors"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func initQualityRoutes(adminGroup *gin.RouterGroup) {
+	adminGroup.GET("/admin/qualities", GetQualityProfilesHandler)
+	adminGroup.POST("/admin/qualities", CreateQualityProfileHandler)
+	adminGroup.GET("/admin/qualities/:kind/:name", GetQualityProfileHandler)
+	adminGroup.PUT("/admin/qualities/:kind/:name", UpdateQualityProfileHandler)
+	adminGroup.DELETE("/admin/qualities/:kind/:name", DeleteQualityProfileHandler)
+	adminGroup.GET("/admin/tenants/:tenant/qualities", GetTenantQualitiesHandler)
+	adminGroup.PUT("/admin/tenants/:tenant/qualities", SetTenantQualitiesHandler)
+	adminGroup.DELETE("/admin/tenants/:tenant/qualities", DeleteTenantQualitiesHandler)
+}
+
+// refreshCatalog applies a change to validations right away, other replicas pick it up on their next refresh
+func refreshCatalog(ctx *gin.Context) {
+	if err := catalog.Refresh(ctx); err != nil {
+		lg.WithContext(ctx).Warn().Msgf("quality catalog not refreshed after change: %v", err)
+	}
+}
+
+// tenantParam reads tenant from the path, it has to match the format of the X-Tenant-ID header
+func tenantParam(ctx *gin.Context) (string, bool) {
+	tenant := ctx.Param("tenant")
+	if !lg.TenantRegex.MatchString(tenant) {
+		utils.AppendContextError(ctx, weberrors.ValidationError.WithMessage(weberrors.InvalidTenantCode))
+		return "", false
+	}
+	return tenant, true
+}
+
+func qualityProfileId(kind string, name string) string {
+	return kind + ":" + name
+}
+
+// GetQualityProfilesHandler lists quality profiles.
+// @Summary	Lists quality profiles events may use, video first, by bitrate
+// @Tags		Admin
+// @Produce json
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Success	200 {array} models.QualityProfile
+// @Failure 500 {object} weberrors.AppError
+// @Router		/admin/qualities [get]
+func GetQualityProfilesHandler(ctx *gin.Context) {
+	profiles, err := db.GetQualityProfiles(ctx)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, profiles)
+}
+
+// CreateQualityProfileHandler creates quality profile.
+// @Summary	Adds quality profile to the catalog
+// @Tags		Admin
+// @Accept json
+// @Produce json
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param profile body models.QualityProfile true "Quality profile"
+// @Success	201 {object} models.QualityProfile
+// @Failure 400,409,500 {object} weberrors.AppError
+// @Router		/admin/qualities [post]
+func CreateQualityProfileHandler(ctx *gin.Context) {
+	profile := models.QualityProfile{}
+	if !bindQualityProfile(ctx, &profile) {
+		return
+	}
+	if err := db.SaveQualityProfile(ctx, profile, true); err != nil {
+		if errors.Is(err, db.ErrConflict) {
+			utils.AppendContextError(ctx, &weberrors.QualityProfileExists)
+			return
+		}
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	audit(ctx, models.AuditQualityCreate, qualityProfileId(profile.Kind, profile.Name), nil, profile)
+	refreshCatalog(ctx)
+	ctx.JSON(http.StatusCreated, profile)
+}
+
+// GetQualityProfileHandler retrieves quality profile.
+// @Summary	Retrieves quality profile
+// @Tags		Admin
+// @Produce json
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param kind path string true "video or audio"
+// @Param name path string true "profile name"
+// @Success	200 {object} models.QualityProfile
+// @Failure 404,500 {object} weberrors.AppError
+// @Router		/admin/qualities/{kind}/{name} [get]
+func GetQualityProfileHandler(ctx *gin.Context) {
+	profile, ok := getQualityProfile(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, profile)
+}
+
+// UpdateQualityProfileHandler replaces quality profile.
+// @Summary	Replaces attributes of quality profile
+// @Description `kind` and `name` may be left out of the body, they cannot be changed.
+// @Tags		Admin
+// @Accept json
+// @Produce json
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param kind path string true "video or audio"
+// @Param name path string true "profile name"
+// @Param profile body models.QualityProfile true "Quality profile"
+// @Success	200 {object} models.QualityProfile
+// @Failure 400,404,500 {object} weberrors.AppError
+// @Router		/admin/qualities/{kind}/{name} [put]
+func UpdateQualityProfileHandler(ctx *gin.Context) {
+	before, ok := getQualityProfile(ctx)
+	if !ok {
+		return
+	}
+	profile := models.QualityProfile{Kind: before.Kind, Name: before.Name}
+	if !bindQualityProfile(ctx, &profile) {
+		return
+	}
+	if profile.Kind != before.Kind || profile.Name != before.Name {
+		utils.AppendContextError(ctx, weberrors.ValidationError.WithMessage(
+			weberrors.ImmutableQualityFieldsCode, weberrors.Field("kind"), weberrors.Field("name")))
+		return
+	}
+	if err := db.SaveQualityProfile(ctx, profile, false); err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	audit(ctx, models.AuditQualityUpdate, qualityProfileId(profile.Kind, profile.Name), before, profile)
+	refreshCatalog(ctx)
+	ctx.JSON(http.StatusOK, profile)
+}
+
+// DeleteQualityProfileHandler removes quality profile.
+// @Summary	Removes quality profile from the catalog
+// @Description Existing events keep the quality, new events cannot use it.
+// @Tags		Admin
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param kind path string true "video or audio"
+// @Param name path string true "profile name"
+// @Success	204
+// @Failure 404,500 {object} weberrors.AppError
+// @Router		/admin/qualities/{kind}/{name} [delete]
+func DeleteQualityProfileHandler(ctx *gin.Context) {
+	before, ok := getQualityProfile(ctx)
+	if !ok {
+		return
+	}
+	if err := db.DeleteQualityProfile(ctx, before.Kind, before.Name); err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	audit(ctx, models.AuditQualityDelete, qualityProfileId(before.Kind, before.Name), before, nil)
+	refreshCatalog(ctx)
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetTenantQualitiesHandler shows qualities enabled for tenant.
+// @Summary	Shows qualities tenant may use
+// @Description Empty list means every profile of the kind is enabled.
+// @Tags		Admin
+// @Produce json
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param tenant path string true "tenant sent in X-Tenant-ID header"
+// @Success	200 {object} models.TenantQualities
+// @Failure 400,500 {object} weberrors.AppError
+// @Router		/admin/tenants/{tenant}/qualities [get]
+func GetTenantQualitiesHandler(ctx *gin.Context) {
+	tenant, ok := tenantParam(ctx)
+	if !ok {
+		return
+	}
+	tenants, err := db.GetTenantQualities(ctx)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, withEmptyLists(tenants[tenant]))
+}
+
+// SetTenantQualitiesHandler changes qualities enabled for tenant.
+// @Summary	Changes qualities tenant may use
+// @Description Empty list enables every profile of the kind, including ones added later.
+// @Tags		Admin
+// @Accept json
+// @Produce json
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param tenant path string true "tenant sent in X-Tenant-ID header"
+// @Param qualities body models.TenantQualities true "Enabled qualities"
+// @Success	200 {object} models.TenantQualities
+// @Failure 400,500 {object} weberrors.AppError
+// @Router		/admin/tenants/{tenant}/qualities [put]
+func SetTenantQualitiesHandler(ctx *gin.Context) {
+	tenant, ok := tenantParam(ctx)
+	if !ok {
+		return
+	}
+	enabled := models.TenantQualities{}
+	bindError := ctx.ShouldBind(&enabled)
+	if bindError != nil {
+		if parsedErr := validations.GetBindErrors(ctx, bindError); parsedErr != nil {
+			utils.AppendContextError(ctx, parsedErr)
+			return
+		}
+		utils.AppendContextError(ctx, &weberrors.InvalidPayload)
+		return
+	}
+	enabled = withEmptyLists(enabled)
+	for _, list := range []struct {
+		field string
+		kind  string
+		names []string
+	}{
+		{"video", models.QualityVideo, enabled.Video},
+		{"audio", models.QualityAudio, enabled.Audio},
+	} {
+		for _, name := range list.names {
+			if _, found := catalog.Profile(list.kind, name); !found {
+				utils.AppendContextError(ctx, weberrors.ValidationError.WithMessage(
+					weberrors.UnknownQualityCode, weberrors.Field(list.field), name))
+				return
+			}
+		}
+	}
+	tenants, err := db.GetTenantQualities(ctx)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	if err := db.SetTenantQualities(ctx, tenant, enabled); err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	var before interface{}
+	if previous, found := tenants[tenant]; found {
+		before = previous
+	}
+	audit(ctx, models.AuditTenantQualities, tenant, before, enabled)
+	refreshCatalog(ctx)
+	ctx.JSON(http.StatusOK, enabled)
+}
+
+// DeleteTenantQualitiesHandler removes qualities enabled for tenant.
+// @Summary	Lets tenant use every quality profile again
+// @Tags		Admin
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param tenant path string true "tenant sent in X-Tenant-ID header"
+// @Success	204
+// @Failure 400,404,500 {object} weberrors.AppError
+// @Router		/admin/tenants/{tenant}/qualities [delete]
+func DeleteTenantQualitiesHandler(ctx *gin.Context) {
+	tenant, ok := tenantParam(ctx)
+	if !ok {
+		return
+	}
+	tenants, err := db.GetTenantQualities(ctx)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	if err := db.DeleteTenantQualities(ctx, tenant); err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	audit(ctx, models.AuditTenantQualities, tenant, tenants[tenant], nil)
+	refreshCatalog(ctx)
+	ctx.Status(http.StatusNoContent)
+}
+
+// bindQualityProfile reports validation errors of the payload, `profile` may be prefilled with values from the path
+func bindQualityProfile(ctx *gin.Context, profile *models.QualityProfile) bool {
+	bindError := ctx.ShouldBind(profile)
+	if bindError != nil {
+		if parsedErr := validations.GetBindErrors(ctx, bindError); parsedErr != nil {
+			utils.AppendContextError(ctx, parsedErr)
+			return false
+		}
+		utils.AppendContextError(ctx, &weberrors.InvalidPayload)
+		return false
+	}
+	return true
+}
+
+// getQualityProfile loads profile addressed by the path, reporting 404 if there is none
+func getQualityProfile(ctx *gin.Context) (models.QualityProfile, bool) {
+	kind, name := ctx.Param("kind"), ctx.Param("name")
+	if kind != models.QualityVideo && kind != models.QualityAudio {
+		utils.AppendContextError(ctx, &weberrors.NotFound)
+		return models.QualityProfile{}, false
+	}
+	profile, err := db.GetQualityProfile(ctx, kind, name)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return models.QualityProfile{}, false
+	}
+	return profile, true
+}
+
+// withEmptyLists makes missing lists `[]` in the response, meaning every profile of the kind is enabled
+func withEmptyLists(enabled models.TenantQualities) models.TenantQualities {
+	if enabled.Video == nil {
+		enabled.Video = []string{}
+	}
+	if enabled.Audio == nil {
+		enabled.Audio = []string{}
+	}
+	return enabled
+}
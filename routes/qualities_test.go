@@ -0,0 +1,260 @@
+package routes
+
+import (
+	"app/auth"
+	"app/catalog"
+	"app/db"
+	lg "app/logging"
+	"app/models"
+	"app/utils"
+	"app/weberrors"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var qualityProfile = models.QualityProfile{
+	Kind: models.QualityVideo, Name: "1080p", Codec: "avc1.640028", BitrateKbps: 6000, Width: 1920, Height: 1080,
+}
+
+// mockQualityCatalog stubs stored catalog and counts refreshes of the in-memory one
+func mockQualityCatalog(t *testing.T, profiles []models.QualityProfile) *int {
+	originalRefresh := catalog.Refresh
+	refreshed := 0
+	catalog.Refresh = func(c context.Context) error {
+		refreshed++
+		return nil
+	}
+	db.GetQualityProfiles = func(c context.Context) ([]models.QualityProfile, error) {
+		return profiles, nil
+	}
+	db.GetQualityProfile = func(c context.Context, kind string, name string) (models.QualityProfile, error) {
+		for _, profile := range profiles {
+			if profile.Kind == kind && profile.Name == name {
+				return profile, nil
+			}
+		}
+		return models.QualityProfile{}, db.ErrNotFound
+	}
+	t.Cleanup(func() { catalog.Refresh = originalRefresh })
+	return &refreshed
+}
+
+var CreateQualityProfileTestCases = []struct {
+	description      string
+	submitedPayload  interface{}
+	dbSaveErr        error
+	expectedStatus   int
+	expectedResponse interface{}
+}{
+	{
+		description:      "Success",
+		submitedPayload:  qualityProfile,
+		expectedStatus:   http.StatusCreated,
+		expectedResponse: qualityProfile,
+	},
+	{
+		description: "Fail - video without resolution",
+		submitedPayload: models.QualityProfile{
+			Kind: models.QualityVideo, Name: "1080p", Codec: "avc1.640028", BitrateKbps: 6000,
+		},
+		expectedStatus: http.StatusBadRequest,
+		expectedResponse: expectedAppError(weberrors.ValidationError.ChangeDesc(
+			"field `width` is required when `kind` is video, field `height` is required when `kind` is video")),
+	},
+	{
+		description:     "Fail - unknown kind",
+		submitedPayload: models.QualityProfile{Kind: "subtitles", Name: "en", Codec: "wvtt", BitrateKbps: 1},
+		expectedStatus:  http.StatusBadRequest,
+		expectedResponse: expectedAppError(weberrors.ValidationError.ChangeDesc(
+			"field `kind` needs to be one of values: video audio")),
+	},
+	{
+		description:      "Fail - profile exists",
+		submitedPayload:  qualityProfile,
+		dbSaveErr:        db.ErrConflict,
+		expectedStatus:   http.StatusConflict,
+		expectedResponse: expectedAppError(&weberrors.QualityProfileExists),
+	},
+}
+
+func TestCreateQualityProfile(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	defer func() { auth.AdminToken = originalToken }()
+	for _, testCase := range CreateQualityProfileTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			refreshed := mockQualityCatalog(t, nil)
+			db.SaveQualityProfile = func(c context.Context, profile models.QualityProfile, create bool) error {
+				assert.True(t, create)
+				assert.Equal(t, testCase.submitedPayload, profile)
+				return testCase.dbSaveErr
+			}
+			res := testClient(t).POST("/admin/qualities").
+				WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+				WithJSON(testCase.submitedPayload).Expect()
+			res.Status(testCase.expectedStatus)
+			res.JSON().Equal(testCase.expectedResponse)
+			if testCase.expectedStatus == http.StatusCreated {
+				assert.Equal(t, 1, *refreshed, "catalog should be refreshed after change")
+			}
+		})
+	}
+}
+
+func TestUpdateQualityProfile(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	defer func() { auth.AdminToken = originalToken }()
+
+	t.Run("Success - kind and name taken from path", func(t *testing.T) {
+		refreshed := mockQualityCatalog(t, []models.QualityProfile{qualityProfile})
+		updated := qualityProfile
+		updated.BitrateKbps = 8000
+		db.SaveQualityProfile = func(c context.Context, profile models.QualityProfile, create bool) error {
+			assert.False(t, create)
+			assert.Equal(t, updated, profile)
+			return nil
+		}
+		res := testClient(t).PUT("/admin/qualities/video/1080p").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			WithJSON(map[string]interface{}{
+				"codec": updated.Codec, "bitrateKbps": 8000, "width": updated.Width, "height": updated.Height,
+			}).Expect()
+		res.Status(http.StatusOK)
+		res.JSON().Equal(updated)
+		assert.Equal(t, 1, *refreshed)
+	})
+
+	t.Run("Fail - name cannot be changed", func(t *testing.T) {
+		mockQualityCatalog(t, []models.QualityProfile{qualityProfile})
+		renamed := qualityProfile
+		renamed.Name = "1080p60"
+		testClient(t).PUT("/admin/qualities/video/1080p").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			WithJSON(renamed).Expect().
+			Status(http.StatusBadRequest).
+			JSON().Equal(expectedAppError(weberrors.ValidationError.WithMessage(
+			weberrors.ImmutableQualityFieldsCode, "kind", "name")))
+	})
+
+	t.Run("Fail - profile does not exist", func(t *testing.T) {
+		mockQualityCatalog(t, []models.QualityProfile{qualityProfile})
+		testClient(t).PUT("/admin/qualities/audio/1080p").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			WithJSON(qualityProfile).Expect().
+			Status(http.StatusNotFound)
+	})
+}
+
+func TestDeleteQualityProfile(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	defer func() { auth.AdminToken = originalToken }()
+
+	t.Run("Success", func(t *testing.T) {
+		refreshed := mockQualityCatalog(t, []models.QualityProfile{qualityProfile})
+		db.DeleteQualityProfile = func(c context.Context, kind string, name string) error {
+			assert.Equal(t, []string{models.QualityVideo, "1080p"}, []string{kind, name})
+			return nil
+		}
+		testClient(t).DELETE("/admin/qualities/video/1080p").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			Expect().
+			Status(http.StatusNoContent)
+		assert.Equal(t, 1, *refreshed)
+	})
+
+	t.Run("Fail - unknown kind", func(t *testing.T) {
+		mockQualityCatalog(t, []models.QualityProfile{qualityProfile})
+		testClient(t).DELETE("/admin/qualities/subtitles/1080p").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+}
+
+func TestSetTenantQualities(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	defer func() { auth.AdminToken = originalToken }()
+	db.GetTenantQualities = func(c context.Context) (map[string]models.TenantQualities, error) {
+		return map[string]models.TenantQualities{}, nil
+	}
+
+	t.Run("Success - missing list enables every profile", func(t *testing.T) {
+		refreshed := mockQualityCatalog(t, nil)
+		expected := models.TenantQualities{Video: []string{"720p", "1080p"}, Audio: []string{}}
+		db.SetTenantQualities = func(c context.Context, tenant string, enabled models.TenantQualities) error {
+			assert.Equal(t, "tenant-1", tenant)
+			assert.Equal(t, expected, enabled)
+			return nil
+		}
+		res := testClient(t).PUT("/admin/tenants/tenant-1/qualities").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			WithJSON(map[string]interface{}{"video": []string{"720p", "1080p"}}).Expect()
+		res.Status(http.StatusOK)
+		res.JSON().Equal(expected)
+		assert.Equal(t, 1, *refreshed)
+	})
+
+	t.Run("Fail - quality missing in catalog", func(t *testing.T) {
+		mockQualityCatalog(t, nil)
+		testClient(t).PUT("/admin/tenants/tenant-1/qualities").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			WithJSON(models.TenantQualities{Video: []string{"720p"}, Audio: []string{"Loud"}}).Expect().
+			Status(http.StatusBadRequest).
+			JSON().Equal(expectedAppError(weberrors.ValidationError.WithMessage(
+			weberrors.UnknownQualityCode, "audio", "Loud")))
+	})
+
+	t.Run("Fail - invalid tenant", func(t *testing.T) {
+		mockQualityCatalog(t, nil)
+		db.SetTenantQualities = func(c context.Context, tenant string, enabled models.TenantQualities) error {
+			t.Fatal("qualities of invalid tenant should not be stored")
+			return nil
+		}
+		testClient(t).PUT("/admin/tenants/tenant.1/qualities").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			WithJSON(models.TenantQualities{Video: []string{"720p"}}).Expect().
+			Status(http.StatusBadRequest).
+			JSON().Equal(expectedAppError(weberrors.ValidationError.WithMessage(weberrors.InvalidTenantCode)))
+	})
+}
+
+func TestCreateEventForTenant(t *testing.T) {
+	defer catalog.Set(catalog.DefaultProfiles, map[string]models.TenantQualities{})
+	catalog.Set(catalog.DefaultProfiles, map[string]models.TenantQualities{
+		"tenant-1": {Video: []string{"1080p", "1440p"}},
+	})
+	payload := models.EventData{
+		Name:      "event-name",
+		Timestamp: "2023-04-20T14:00:00Z",
+		Languages: []string{"English"},
+		Invitees:  []string{"valid-email@mail.com"},
+	}
+
+	t.Run("Success - lowest enabled quality is the default", func(t *testing.T) {
+		db.CreateEvent = func(c context.Context, eventData models.EventData) (string, error) {
+			assert.Equal(t, []string{"1080p"}, eventData.VideoQuality)
+			assert.Equal(t, []string{"Low"}, eventData.AudioQuality)
+			return "b8d2b2e4-2ca0-4fe9-8e8c-1e6b4f6f7e2b", nil
+		}
+		testClient(t).POST("/event").
+			WithHeader(lg.TenantHeader, "tenant-1").
+			WithJSON(payload).Expect().
+			Status(http.StatusCreated)
+	})
+
+	t.Run("Fail - quality not enabled for tenant", func(t *testing.T) {
+		payload.VideoQuality = []string{"720p"}
+		testClient(t).POST("/event").
+			WithHeader(lg.TenantHeader, "tenant-1").
+			WithJSON(payload).Expect().
+			Status(http.StatusBadRequest).
+			JSON().Equal(expectedAppError(weberrors.ValidationError.ChangeDesc(
+			"field `videoQuality` contains invalid resolution (allowed values: 1080p, 1440p)")))
+	})
+}
@@ -2,13 +2,25 @@ package routes
 
 import (
 	"app/auth"
+	"app/catalog"
+	"app/config"
 	"app/db"
+	"app/health"
+	"app/lifecycle"
 	lg "app/logging"
+	"app/metrics"
 	"app/models"
+	"app/scheduler"
+	"app/streaming"
+	"app/tracing"
 	"app/utils"
 	"app/validations"
 	"app/weberrors"
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	_ "app/docs"
 
@@ -17,20 +29,49 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func InitApp(app *gin.Engine) {
+// build is reported by health check
+var build config.Build
+
+// InitApp registers middlewares and routes, `settings` are validated already.
+func InitApp(app *gin.Engine, settings config.Config) {
+	build = settings.Build
+	// lets gin context carry values of request context, e.g. trace span
+	app.ContextWithFallback = true
 	app.Use(gin.Recovery())
+	app.Use(tracing.Middleware())
+	app.Use(metrics.Middleware())
 	app.Use(lg.Middleware())
+	app.Use(validations.Middleware())
 	app.Use(weberrors.JSONAppErrorReporter())
 
 	app.GET("/healthcheck", HealthCheckHandler)
+	app.GET("/livez", LivenessHandler)
+	app.GET("/readyz", ReadinessHandler)
+	app.GET("/metrics", metrics.Handler())
 	app.POST("/event", CreateEventHandler)
 	app.GET("/event/:id", GetEventHandler)
+	app.GET("/event/:id/master.m3u8", GetHLSMasterPlaylistHandler)
+	app.GET("/event/:id/manifest.mpd", GetDASHManifestHandler)
+	app.GET("/events/stream", StreamEventsHandler)
 
 	app.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	adminGroup := app.Group("/")
 	adminGroup.Use(auth.Middleware())
 	adminGroup.DELETE("/event/:id", DeleteEventHandler)
+	adminGroup.POST("/event/:id/publish", PublishEventHandler)
+	adminGroup.POST("/event/:id/start", StartEventHandler)
+	adminGroup.POST("/event/:id/end", EndEventHandler)
+	adminGroup.POST("/event/:id/cancel", CancelEventHandler)
+	adminGroup.GET("/jobs", GetQueuedJobsHandler)
+	adminGroup.GET("/jobs/:id", GetJobHandler)
+	adminGroup.GET("/notifications/dead", GetDeadNotificationsHandler)
+	adminGroup.GET("/admin/changes", GetChangesHandler)
+	adminGroup.GET("/admin/audit", GetAuditHandler)
+	initWebhookRoutes(adminGroup)
+	initQualityRoutes(adminGroup)
+	initLogLevelRoutes(adminGroup)
+	initLiveRoutes(app, adminGroup)
 
 	app.NoRoute(func(ctx *gin.Context) {
 		utils.AppendContextError(ctx, &weberrors.RouteNotFoundError)
@@ -40,6 +81,8 @@ func InitApp(app *gin.Engine) {
 
 // CreateEventHandler creates event.
 // @Summary	Creates event to database
+// @Description Errors are returned as RFC 7807 problem details, with failed validations per field,
+// @Description when `Accept` header has `application/problem+json`.
 // @Tags		Event
 // @Accept json
 // @Produce json
@@ -51,28 +94,37 @@ func CreateEventHandler(ctx *gin.Context) {
 	eventData := models.EventData{}
 	bindError := ctx.ShouldBind(&eventData)
 	if bindError != nil {
-		if parsedErr := validations.GetBindErrors(bindError); parsedErr != nil {
+		if parsedErr := validations.GetBindErrors(ctx, bindError); parsedErr != nil {
 			utils.AppendContextError(ctx, parsedErr)
 			return
 		}
 		utils.AppendContextError(ctx, &weberrors.InvalidPayload)
 		return
 	}
-	// setting default values if not provided in payload
-	if len(eventData.VideoQuality) == 0 {
-		eventData.VideoQuality = []string{utils.DEFAULT_RESOLUTION}
+	if err := validations.ValidateForTenant(ctx, eventData); err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	validations.NormalizeLanguages(&eventData)
+	// setting default values if not provided in payload, streaming config takes precedence over them
+	if len(eventData.VideoQuality) == 0 && eventData.Streaming == nil {
+		eventData.VideoQuality = []string{
+			catalog.Default(ctx, models.QualityVideo, utils.Qualities().DefaultResolution)}
 	}
 	if len(eventData.AudioQuality) == 0 {
-		eventData.AudioQuality = []string{utils.DEVAULT_AUDIO}
+		eventData.AudioQuality = []string{catalog.Default(ctx, models.QualityAudio, utils.Qualities().DefaultAudio)}
 	}
-	id, err := db.CreateEvent(eventData)
+	streaming.Resolve(&eventData)
+	eventData.Status = lifecycle.Draft
+	id, err := db.CreateEvent(ctx, eventData)
 	if err != nil {
-		utils.AppendContextError(ctx, &weberrors.InternalError)
+		utils.AppendContextError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusCreated, models.EventResponseData{
-		Id:        id,
-		EventData: eventData,
+		Id:            id,
+		EventData:     eventData,
+		LanguageNames: validations.LanguageNames(eventData.Languages, ctx.GetHeader("Accept-Language")),
 	})
 }
 
@@ -90,18 +142,24 @@ func GetEventHandler(ctx *gin.Context) {
 		utils.AppendContextError(ctx, &weberrors.NotFound)
 		return
 	}
-	response, err := db.GetEvent(id)
+	response, err := db.GetEvent(ctx, id)
 	if err != nil {
-		if err.Error() == "not found" {
-			utils.AppendContextError(ctx, &weberrors.NotFound)
-			return
-		}
-		utils.AppendContextError(ctx, &weberrors.InternalError)
+		utils.AppendContextError(ctx, err)
 		return
 	}
+	resolveEventStatus(&response)
+	// events created before streaming config existed or languages were normalized
+	validations.NormalizeLanguages(&response.EventData)
+	streaming.Resolve(&response.EventData)
+	response.LanguageNames = validations.LanguageNames(response.Languages, ctx.GetHeader("Accept-Language"))
 	ctx.JSON(http.StatusOK, response)
 }
 
+// resolveEventStatus applies time based transitions to event read by client, they are stored by scheduler.
+func resolveEventStatus(event *models.EventResponseData) {
+	event.Status = lifecycle.Resolve(event.Status, event.Timestamp, time.Now().UTC())
+}
+
 // DeleteEventHandler removes event.
 // @Summary	Delete event from database
 // @Tags		Event
@@ -116,16 +174,164 @@ func DeleteEventHandler(ctx *gin.Context) {
 	if !validations.CheckUuidFormat(id) {
 		return
 	}
-	err := db.DeleteEvent(id)
+	err := db.DeleteEvent(ctx, id)
 	if err != nil {
-		if err.Error() != "not found" {
-			utils.AppendContextError(ctx, &weberrors.InternalError)
+		if !errors.Is(err, db.ErrNotFound) {
+			utils.AppendContextError(ctx, err)
 		}
+		return
+	}
+	if err := errors.Join(scheduler.CancelReminder(ctx, id), scheduler.CancelTransitions(ctx, id)); err != nil {
+		utils.AppendPrivateContextError(ctx, fmt.Errorf("cancelling jobs of event `%v`: %w", id, err))
 	}
 }
 
-// HealthCheckHandler checks the status of the server.
-// @Summary	Checks health of this service
+// PublishEventHandler publishes event.
+// @Summary	Moves event from `draft` to `scheduled`
+// @Tags		Event
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param id path string true "Event ID (uuid)"
+// @Produce json
+// @Success	200 {object} models.EventResponseData
+// @Failure 404,409,500 {object} weberrors.AppError
+// @Router		/event/{id}/publish [post]
+func PublishEventHandler(ctx *gin.Context) {
+	transitionEvent(ctx, lifecycle.ActionPublish)
+}
+
+// StartEventHandler starts event.
+// @Summary	Moves event from `scheduled` to `live`
+// @Tags		Event
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param id path string true "Event ID (uuid)"
+// @Produce json
+// @Success	200 {object} models.EventResponseData
+// @Failure 404,409,500 {object} weberrors.AppError
+// @Router		/event/{id}/start [post]
+func StartEventHandler(ctx *gin.Context) {
+	transitionEvent(ctx, lifecycle.ActionStart)
+}
+
+// EndEventHandler ends event.
+// @Summary	Moves event from `live` to `ended`
+// @Tags		Event
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param id path string true "Event ID (uuid)"
+// @Produce json
+// @Success	200 {object} models.EventResponseData
+// @Failure 404,409,500 {object} weberrors.AppError
+// @Router		/event/{id}/end [post]
+func EndEventHandler(ctx *gin.Context) {
+	transitionEvent(ctx, lifecycle.ActionEnd)
+}
+
+// CancelEventHandler cancels event.
+// @Summary	Moves `draft`, `scheduled` or `live` event to `cancelled`
+// @Tags		Event
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param id path string true "Event ID (uuid)"
+// @Produce json
+// @Success	200 {object} models.EventResponseData
+// @Failure 404,409,500 {object} weberrors.AppError
+// @Router		/event/{id}/cancel [post]
+func CancelEventHandler(ctx *gin.Context) {
+	transitionEvent(ctx, lifecycle.ActionCancel)
+}
+
+func transitionEvent(ctx *gin.Context, action string) {
+	id := ctx.Param("id")
+	if !validations.CheckUuidFormat(id) {
+		utils.AppendContextError(ctx, &weberrors.NotFound)
+		return
+	}
+	event, err := db.GetEvent(ctx, id)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	storedStatus := event.Status
+	resolveEventStatus(&event)
+	status, err := lifecycle.Transition(event.Status, action)
+	if err != nil {
+		utils.AppendContextError(ctx, weberrors.InvalidStateTransition.WithMessage(
+			weberrors.StatusTransitionCode, weberrors.Param(event.Status), weberrors.Param(action)))
+		return
+	}
+	event.Status = status
+	err = db.UpdateEventStatus(ctx, id, storedStatus, event.EventData)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	metrics.EventTransitions.WithLabelValues(status).Inc()
+	switch action {
+	case lifecycle.ActionPublish:
+		err = errors.Join(scheduler.ScheduleReminder(ctx, event), scheduler.ScheduleTransitions(ctx, event))
+	case lifecycle.ActionCancel:
+		err = errors.Join(scheduler.CancelReminder(ctx, id), scheduler.CancelTransitions(ctx, id))
+	}
+	if err != nil {
+		utils.AppendPrivateContextError(ctx, fmt.Errorf("updating jobs of event `%v`: %w", id, err))
+	}
+	event.LanguageNames = validations.LanguageNames(event.Languages, ctx.GetHeader("Accept-Language"))
+	ctx.JSON(http.StatusOK, event)
+}
+
+// GetQueuedJobsHandler lists queued jobs.
+// @Summary	Lists jobs waiting in scheduler queue
+// @Tags		Scheduler
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Produce json
+// @Success	200 {array} models.Job
+// @Failure 500 {object} weberrors.AppError
+// @Router		/jobs [get]
+func GetQueuedJobsHandler(ctx *gin.Context) {
+	jobs, err := db.GetQueuedJobs(ctx)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, jobs)
+}
+
+// GetJobHandler retrieves job.
+// @Summary	Retrieves status of scheduler job
+// @Tags		Scheduler
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param id path string true "Job ID"
+// @Produce json
+// @Success	200 {object} models.Job
+// @Failure 404,500 {object} weberrors.AppError
+// @Router		/jobs/{id} [get]
+func GetJobHandler(ctx *gin.Context) {
+	job, err := db.GetJob(ctx, ctx.Param("id"))
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, job)
+}
+
+// GetDeadNotificationsHandler lists undeliverable notifications.
+// @Summary	Lists notifications which ran out of delivery attempts
+// @Tags		Notifications
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Produce json
+// @Success	200 {array} models.Notification
+// @Failure 500 {object} weberrors.AppError
+// @Router		/notifications/dead [get]
+func GetDeadNotificationsHandler(ctx *gin.Context) {
+	notifications, err := db.GetDeadNotifications(ctx)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, notifications)
+}
+
+// HealthCheckHandler reports build of the running server, it is a liveness check only,
+// dependencies are checked by ReadinessHandler.
+// @Summary	Liveness check reporting build, does not check dependencies
 // @Tags		Health check
 // @Produce	json
 // @Success	200	{object}	models.JsonHealthCheckStatus
@@ -133,7 +339,35 @@ func DeleteEventHandler(ctx *gin.Context) {
 func HealthCheckHandler(ctx *gin.Context) {
 	var status models.JsonHealthCheckStatus
 	status.Result = "ok"
-	status.Version = utils.GetEnvOrDefault("COMMIT_TAG", "unset")     // would be set in pipeline
-	status.DeployDate = utils.GetEnvOrDefault("DEPLOY_DATE", "unset") // would be set in pipeline
+	status.Version = build.CommitTag     // would be set in pipeline
+	status.DeployDate = build.DeployDate // would be set in pipeline
 	ctx.JSON(http.StatusOK, status)
 }
+
+// LivenessHandler reports the process is running.
+// @Summary	Liveness probe, does not check dependencies
+// @Tags		Health check
+// @Produce	json
+// @Success	200	{object}	models.Health
+// @Router		/livez [get]
+func LivenessHandler(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, models.Health{Status: health.StatusOk, Checks: []models.HealthCheck{}})
+}
+
+// ReadinessHandler checks dependencies.
+// @Summary	Readiness probe, checks Redis, scheduler, outbox and webhooks
+// @Description Results are cached for 5s. Failing non-critical check makes status `degraded` but still ready.
+// @Tags		Health check
+// @Produce	json
+// @Success	200	{object}	models.Health
+// @Failure	503	{object}	models.Health
+// @Router		/readyz [get]
+func ReadinessHandler(ctx *gin.Context) {
+	// results are shared by later probes, so checks must not be cancelled with this request
+	result := health.Readiness(context.Background())
+	status := http.StatusOK
+	if result.Status == health.StatusFailing {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.JSON(status, result)
+}
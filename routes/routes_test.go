@@ -2,11 +2,18 @@ package routes
 
 import (
 	"app/auth"
+	"app/catalog"
+	"app/config"
 	"app/db"
+	"app/health"
+	"app/lifecycle"
 	"app/models"
+	"app/scheduler"
 	"app/utils"
 	"app/validations"
 	"app/weberrors"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -26,11 +33,22 @@ import (
 func testClient(t *testing.T) *httpexpect.Expect {
 	os.Setenv("CORS_ORIGIN", "*")
 	app := gin.New()
-	InitApp(app)
+	InitApp(app, config.Default())
+	// audit trail is asserted by tests of audited routes only
+	db.AppendAudit = func(c context.Context, action string, targetId string, before string, after string) error {
+		return nil
+	}
 
 	return testFuncs.GetTestClient(t, app)
 }
 
+// expectedAppError returns error response body of request sent by testClient
+func expectedAppError(err error) weberrors.AppError {
+	appError := weberrors.ParseAppError(err)
+	appError.CorrelationId = testFuncs.CorrelationId
+	return appError
+}
+
 func TestHealthCheckRoute(t *testing.T) {
 	t.Run("Check if `ok` is returned in response", func(t *testing.T) {
 		testClient := testClient(t)
@@ -41,6 +59,33 @@ func TestHealthCheckRoute(t *testing.T) {
 		res.Header("Content-type").Contains("application/json")
 		res.JSON().Object().ValueEqual("result", "ok")
 	})
+
+	t.Run("Configured build is reported", func(t *testing.T) {
+		settings := config.Default()
+		settings.Build.CommitTag = "v1.2.3"
+		settings.Build.DeployDate = "2023-05-01"
+		app := gin.New()
+		InitApp(app, settings)
+		defer InitApp(gin.New(), config.Default())
+
+		res := testFuncs.GetTestClient(t, app).GET("/healthcheck").
+			Expect()
+		res.Status(http.StatusOK)
+		res.JSON().Object().ValueEqual("version", "v1.2.3").ValueEqual("deployDate", "2023-05-01")
+	})
+}
+
+func TestMetricsRoute(t *testing.T) {
+	t.Run("Check if metrics are exposed", func(t *testing.T) {
+		testClient := testClient(t)
+		testClient.GET("/healthcheck").Expect()
+
+		res := testClient.GET("/metrics").
+			Expect()
+		res.Status(http.StatusOK)
+		res.Header("Content-type").Contains("text/plain")
+		res.Body().Contains(`event_handler_http_requests_total{method="GET",route="/healthcheck",status="200"}`)
+	})
 }
 
 func TestNoRoute(t *testing.T) {
@@ -50,7 +95,7 @@ func TestNoRoute(t *testing.T) {
 			Expect()
 		res.Status(http.StatusNotFound)
 		res.Header("Content-type").Contains("application/json")
-		res.JSON().Equal(weberrors.ParseAppError(&weberrors.RouteNotFoundError))
+		res.JSON().Equal(expectedAppError(&weberrors.RouteNotFoundError))
 	})
 }
 
@@ -90,12 +135,23 @@ var CreateEventTestCases = []struct {
 			EventData: models.EventData{
 				Name:         "event-name",
 				Timestamp:    "2023-04-20T14:00:00Z",
-				VideoQuality: []string{utils.DEFAULT_RESOLUTION},
-				AudioQuality: []string{utils.DEVAULT_AUDIO},
-				Languages:    []string{"English"},
+				VideoQuality: []string{utils.Qualities().DefaultResolution},
+				AudioQuality: []string{utils.Qualities().DefaultAudio},
+				Languages:    []string{"en"},
 				Invitees:     []string{"valid-email@mail.com"},
 				Description:  "event-description",
+				Streaming: &models.StreamingConfig{
+					DefaultRendition: utils.Qualities().DefaultResolution,
+					Ladder:           []string{utils.Qualities().DefaultResolution},
+					AudioTracks: []models.AudioTrack{
+						{Language: "en", Qualities: []string{utils.Qualities().DefaultAudio}},
+					},
+					Subtitles:   []models.SubtitleTrack{},
+					LatencyMode: models.LatencyStandard,
+				},
+				Status: lifecycle.Draft,
 			},
+			LanguageNames: map[string]string{"en": "English"},
 		},
 	},
 	{
@@ -103,7 +159,7 @@ var CreateEventTestCases = []struct {
 		submitedPayload: models.EventData{
 			Name:         "event-name",
 			Timestamp:    "2023-04-20T14:00:00Z",
-			Languages:    []string{"English"},
+			Languages:    []string{"en"},
 			VideoQuality: []string{"2160p", "1440p"},
 			AudioQuality: []string{"High", "Mid"},
 			Invitees:     []string{"valid-email@mail.com", "valid-email2@mail.com"},
@@ -118,23 +174,32 @@ var CreateEventTestCases = []struct {
 				Timestamp:    "2023-04-20T14:00:00Z",
 				VideoQuality: []string{"2160p", "1440p"},
 				AudioQuality: []string{"High", "Mid"},
-				Languages:    []string{"English"},
+				Languages:    []string{"en"},
 				Invitees:     []string{"valid-email@mail.com", "valid-email2@mail.com"},
 				Description:  "event-description",
+				Streaming: &models.StreamingConfig{
+					DefaultRendition: "2160p",
+					Ladder:           []string{"2160p", "1440p"},
+					AudioTracks:      []models.AudioTrack{{Language: "en", Qualities: []string{"High", "Mid"}}},
+					Subtitles:        []models.SubtitleTrack{},
+					LatencyMode:      models.LatencyStandard,
+				},
+				Status: lifecycle.Draft,
 			},
+			LanguageNames: map[string]string{"en": "English"},
 		},
 	},
 	{
 		description:    "Fail - required fields validation",
 		expectedStatus: http.StatusBadRequest,
-		expectedResponse: weberrors.ParseAppError(weberrors.ValidationError.ChangeDesc(
+		expectedResponse: expectedAppError(weberrors.ValidationError.ChangeDesc(
 			"Field `name` is required, field `timestamp` is required, field `languages` is required, field `invitees` is required.")),
 	},
 	{
 		description:      "Fail - invalid Json payload",
 		submitedPayload:  "not a json string",
 		expectedStatus:   http.StatusBadRequest,
-		expectedResponse: weberrors.ParseAppError(&weberrors.InvalidPayload),
+		expectedResponse: expectedAppError(&weberrors.InvalidPayload),
 	},
 	{
 		description: "Fail - incorrect `name` format",
@@ -145,7 +210,7 @@ var CreateEventTestCases = []struct {
 			Invitees:  []string{"valid-email@mail.com"},
 		},
 		expectedStatus: http.StatusBadRequest,
-		expectedResponse: weberrors.ParseAppError(weberrors.ValidationError.ChangeDesc(
+		expectedResponse: expectedAppError(weberrors.ValidationError.ChangeDesc(
 			"field `name` contains invalid characters (use A-Za-z0-9 _- only)")),
 	},
 	{
@@ -157,7 +222,7 @@ var CreateEventTestCases = []struct {
 			Invitees:  []string{"invalid-email"},
 		},
 		expectedStatus: http.StatusBadRequest,
-		expectedResponse: weberrors.ParseAppError(weberrors.ValidationError.ChangeDesc(
+		expectedResponse: expectedAppError(weberrors.ValidationError.ChangeDesc(
 			"field `invitees` contains invalid email address")),
 	},
 	{
@@ -169,7 +234,7 @@ var CreateEventTestCases = []struct {
 			Invitees:  []string{"valid-email@mail.com"},
 		},
 		expectedStatus: http.StatusBadRequest,
-		expectedResponse: weberrors.ParseAppError(weberrors.ValidationError.ChangeDesc(
+		expectedResponse: expectedAppError(weberrors.ValidationError.ChangeDesc(
 			"field `date` does not have correct format (use YYYY-MM-DDTHH:MM:SSZ)")),
 	},
 	{
@@ -182,10 +247,10 @@ var CreateEventTestCases = []struct {
 			Invitees:     []string{"valid-email@mail.com"},
 		},
 		expectedStatus: http.StatusBadRequest,
-		expectedResponse: weberrors.ParseAppError(weberrors.ValidationError.ChangeDesc(
+		expectedResponse: expectedAppError(weberrors.ValidationError.ChangeDesc(
 			fmt.Sprintf(
 				"field `videoQuality` contains invalid resolution (allowed values: %v)",
-				strings.Join(utils.ALLOWED_RESOLUTION, ", "),
+				strings.Join(catalog.Allowed(context.Background(), models.QualityVideo), ", "),
 			))),
 	},
 	{
@@ -198,12 +263,91 @@ var CreateEventTestCases = []struct {
 			Invitees:     []string{"valid-email@mail.com"},
 		},
 		expectedStatus: http.StatusBadRequest,
-		expectedResponse: weberrors.ParseAppError(weberrors.ValidationError.ChangeDesc(
+		expectedResponse: expectedAppError(weberrors.ValidationError.ChangeDesc(
 			fmt.Sprintf(
-				"field `audioQuality` contains invalid resolution (allowed values: %v)",
-				strings.Join(utils.ALLOWED_AUDIO, ", "),
+				"field `audioQuality` contains invalid audio quality (allowed values: %v)",
+				strings.Join(catalog.Allowed(context.Background(), models.QualityAudio), ", "),
 			))),
 	},
+	{
+		description: "Success - streaming config sets quality lists",
+		submitedPayload: models.EventData{
+			Name:      "event-name",
+			Timestamp: "2023-04-20T14:00:00Z",
+			Languages: []string{"English", "French"},
+			Invitees:  []string{"valid-email@mail.com"},
+			Streaming: &models.StreamingConfig{
+				DefaultRendition: "1080p",
+				Ladder:           []string{"720p", "1080p"},
+				AudioTracks:      []models.AudioTrack{{Language: "French", Qualities: []string{"Mid"}}},
+				Subtitles:        []models.SubtitleTrack{{Language: "English"}},
+				LatencyMode:      models.LatencyLow,
+			},
+		},
+		dbCreateEventResp: "generated-uuid-string",
+		expectedStatus:    http.StatusCreated,
+		expectedResponse: models.EventResponseData{
+			Id: "generated-uuid-string",
+			EventData: models.EventData{
+				Name:         "event-name",
+				Timestamp:    "2023-04-20T14:00:00Z",
+				Languages:    []string{"en", "fr"},
+				VideoQuality: []string{"1080p", "720p"},
+				AudioQuality: []string{"Mid"},
+				Invitees:     []string{"valid-email@mail.com"},
+				Streaming: &models.StreamingConfig{
+					DefaultRendition: "1080p",
+					Ladder:           []string{"720p", "1080p"},
+					AudioTracks:      []models.AudioTrack{{Language: "fr", Qualities: []string{"Mid"}}},
+					Subtitles:        []models.SubtitleTrack{{Language: "en", Format: models.SubtitleWebVTT}},
+					LatencyMode:      models.LatencyLow,
+				},
+				Status: lifecycle.Draft,
+			},
+			LanguageNames: map[string]string{"en": "English", "fr": "French"},
+		},
+	},
+	{
+		description: "Fail - streaming config inconsistent with event",
+		submitedPayload: models.EventData{
+			Name:      "event-name",
+			Timestamp: "2023-04-20T14:00:00Z",
+			Languages: []string{"English"},
+			Invitees:  []string{"valid-email@mail.com"},
+			Streaming: &models.StreamingConfig{
+				DefaultRendition: "2160p",
+				Ladder:           []string{"720p", "1080p"},
+				AudioTracks:      []models.AudioTrack{{Language: "French", Qualities: []string{"Mid"}}},
+				LatencyMode:      "instant",
+			},
+		},
+		expectedStatus: http.StatusBadRequest,
+		expectedResponse: expectedAppError(weberrors.ValidationError.ChangeDesc(
+			"field `latencyMode` needs to be one of values: standard low ultra-low, " +
+				"field `streaming.defaultRendition` must be one of values of `streaming.ladder`, " +
+				"field `streaming.audioTracks[0].language` must be one of values of `languages`")),
+	},
+	{
+		description: "Fail - unknown language; same language twice in audio tracks",
+		submitedPayload: models.EventData{
+			Name:      "event-name",
+			Timestamp: "2023-04-20T14:00:00Z",
+			Languages: []string{"en", "Klingonese"},
+			Invitees:  []string{"valid-email@mail.com"},
+			Streaming: &models.StreamingConfig{
+				DefaultRendition: "720p",
+				Ladder:           []string{"720p"},
+				AudioTracks: []models.AudioTrack{
+					{Language: "English", Qualities: []string{"Mid"}},
+					{Language: "en", Qualities: []string{"Low"}},
+				},
+			},
+		},
+		expectedStatus: http.StatusBadRequest,
+		expectedResponse: expectedAppError(weberrors.ValidationError.ChangeDesc(
+			"field `languages` contains unknown language (use BCP 47 tags, e.g. en, fr-CA), " +
+				"field `streaming.audioTracks[1].language` contains duplicate values")),
+	},
 	{
 		description: "Fail - field `name` too long; field `invitees` has duplicates",
 		submitedPayload: models.EventData{
@@ -213,7 +357,7 @@ var CreateEventTestCases = []struct {
 			Invitees:  []string{"valid-email@mail.com", "valid-email@mail.com"},
 		},
 		expectedStatus: http.StatusBadRequest,
-		expectedResponse: weberrors.ParseAppError(weberrors.ValidationError.ChangeDesc(
+		expectedResponse: expectedAppError(weberrors.ValidationError.ChangeDesc(
 			"field `name` cannot be longer than 255, field `invitees` contains duplicate values")),
 	},
 }
@@ -221,15 +365,10 @@ var CreateEventTestCases = []struct {
 func TestCreateEventRoute(t *testing.T) {
 	for _, testCase := range CreateEventTestCases {
 		t.Run(testCase.description, func(t *testing.T) {
-			db.CreateEvent = func(payload models.EventData) (string, error) {
-				convertedTestCaseData := testCase.submitedPayload.(models.EventData)
-				if len(convertedTestCaseData.VideoQuality) == 0 {
-					convertedTestCaseData.VideoQuality = []string{utils.DEFAULT_RESOLUTION}
-				}
-				if len(convertedTestCaseData.AudioQuality) == 0 {
-					convertedTestCaseData.AudioQuality = []string{utils.DEVAULT_AUDIO}
+			db.CreateEvent = func(c context.Context, payload models.EventData) (string, error) {
+				if expected, ok := testCase.expectedResponse.(models.EventResponseData); ok {
+					assert.Equal(t, expected.EventData, payload)
 				}
-				assert.Equal(t, convertedTestCaseData, payload)
 				return testCase.dbCreateEventResp, testCase.dbCreateEventErr
 			}
 
@@ -242,6 +381,30 @@ func TestCreateEventRoute(t *testing.T) {
 	}
 }
 
+func TestCreateEventProblemDetails(t *testing.T) {
+	res := testClient(t).POST("/event").
+		WithHeader("Accept", weberrors.ProblemContentType).
+		WithJSON(models.EventData{
+			Name:      "event-name",
+			Timestamp: "2023-04-20T14:00:00Z",
+			Languages: []string{"English"},
+			Invitees:  []string{"a@b.com", "c@d.com", "invalid-email"},
+		}).Expect()
+	res.Status(http.StatusBadRequest)
+	res.Header("Content-Type").Equal(weberrors.ProblemContentType)
+	problem := weberrors.Problem{}
+	assert.NoError(t, json.Unmarshal([]byte(res.Body().Raw()), &problem))
+	assert.Equal(t, weberrors.ProblemTypePrefix+weberrors.ValidationErrorCode, problem.Type)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "/event", problem.Instance)
+	assert.Equal(t, testFuncs.CorrelationId, problem.CorrelationId)
+	assert.Equal(t, []weberrors.FieldProblem{{
+		Field:   "invitees[2]",
+		Tag:     "checkEmail",
+		Message: "Field `invitees[2]` contains invalid email address",
+	}}, problem.Errors)
+}
+
 var GetEventTestCases = []struct {
 	description                    string
 	submitIdPathParam              string
@@ -258,14 +421,62 @@ var GetEventTestCases = []struct {
 		dbGetEventMockResp: models.EventResponseData{
 			Id: "90a04b08-d820-4106-8ced-2cbc940728a3",
 			EventData: models.EventData{
-				Name: "event-name",
+				Name:         "event-name",
+				Languages:    []string{"English", "French"},
+				VideoQuality: []string{"1080p", "720p"},
+				AudioQuality: []string{"Low"},
 			},
 		},
 		expectedStatus: http.StatusOK,
 		expectedResp: models.EventResponseData{
 			Id: "90a04b08-d820-4106-8ced-2cbc940728a3",
 			EventData: models.EventData{
-				Name: "event-name",
+				Name:         "event-name",
+				Languages:    []string{"en", "fr"},
+				VideoQuality: []string{"1080p", "720p"},
+				AudioQuality: []string{"Low"},
+				Streaming: &models.StreamingConfig{
+					DefaultRendition: "1080p",
+					Ladder:           []string{"1080p", "720p"},
+					AudioTracks: []models.AudioTrack{
+						{Language: "en", Qualities: []string{"Low"}},
+						{Language: "fr", Qualities: []string{"Low"}},
+					},
+					Subtitles:   []models.SubtitleTrack{},
+					LatencyMode: models.LatencyStandard,
+				},
+				Status: lifecycle.Scheduled,
+			},
+			LanguageNames: map[string]string{"en": "English", "fr": "French"},
+		},
+	},
+	{
+		description:                    "Success - scheduled event auto-transitioned to ended",
+		submitIdPathParam:              "90a04b08-d820-4106-8ced-2cbc940728a3",
+		validationsCheckUuidFormatResp: true,
+		dbGetEventMockResp: models.EventResponseData{
+			Id: "90a04b08-d820-4106-8ced-2cbc940728a3",
+			EventData: models.EventData{
+				Name:      "event-name",
+				Timestamp: "2023-04-20T14:00:00Z",
+				Status:    lifecycle.Scheduled,
+			},
+		},
+		expectedStatus: http.StatusOK,
+		expectedResp: models.EventResponseData{
+			Id: "90a04b08-d820-4106-8ced-2cbc940728a3",
+			EventData: models.EventData{
+				Name:         "event-name",
+				Timestamp:    "2023-04-20T14:00:00Z",
+				VideoQuality: []string{},
+				AudioQuality: []string{},
+				Streaming: &models.StreamingConfig{
+					Ladder:      []string{},
+					AudioTracks: []models.AudioTrack{},
+					Subtitles:   []models.SubtitleTrack{},
+					LatencyMode: models.LatencyStandard,
+				},
+				Status: lifecycle.Ended,
 			},
 		},
 	},
@@ -274,15 +485,15 @@ var GetEventTestCases = []struct {
 		submitIdPathParam:              "invalid-uuid-string",
 		validationsCheckUuidFormatResp: false,
 		expectedStatus:                 http.StatusNotFound,
-		expectedResp:                   weberrors.ParseAppError(&weberrors.NotFound),
+		expectedResp:                   expectedAppError(&weberrors.NotFound),
 	},
 	{
 		description:                    "Fail - event does not exist",
 		submitIdPathParam:              "90a04b08-d820-4106-8ced-2cbc940728a3",
 		validationsCheckUuidFormatResp: true,
-		dbGetEventMockErr:              errors.New("not found"),
+		dbGetEventMockErr:              db.ErrNotFound,
 		expectedStatus:                 http.StatusNotFound,
-		expectedResp:                   weberrors.ParseAppError(&weberrors.NotFound),
+		expectedResp:                   expectedAppError(&weberrors.NotFound),
 	},
 	{
 		description:                    "Fail - db unexpected error",
@@ -290,7 +501,16 @@ var GetEventTestCases = []struct {
 		validationsCheckUuidFormatResp: true,
 		dbGetEventMockErr:              errors.New("redis connection error"),
 		expectedStatus:                 http.StatusInternalServerError,
-		expectedResp:                   weberrors.ParseAppError(&weberrors.InternalError),
+		expectedResp:                   expectedAppError(&weberrors.InternalError),
+	},
+	{
+		description:                    "Fail - storage unavailable",
+		submitIdPathParam:              "90a04b08-d820-4106-8ced-2cbc940728a3",
+		validationsCheckUuidFormatResp: true,
+		dbGetEventMockErr: &db.Error{Kind: db.ErrUnavailable, Op: "get event",
+			Key: "90a04b08-d820-4106-8ced-2cbc940728a3", Err: errors.New("connection refused")},
+		expectedStatus: http.StatusServiceUnavailable,
+		expectedResp:   expectedAppError(&weberrors.ServiceUnavailable),
 	},
 }
 
@@ -301,10 +521,14 @@ func TestGetEvent(t *testing.T) {
 				assert.Equal(t, testCase.submitIdPathParam, inputString)
 				return testCase.validationsCheckUuidFormatResp
 			}
-			db.GetEvent = func(id string) (models.EventResponseData, error) {
+			db.GetEvent = func(c context.Context, id string) (models.EventResponseData, error) {
 				assert.Equal(t, testCase.submitIdPathParam, id)
 				return testCase.dbGetEventMockResp, testCase.dbGetEventMockErr
 			}
+			db.UpdateEvent = func(c context.Context, id string, payload models.EventData) error {
+				assert.Fail(t, "reading event should not update it")
+				return nil
+			}
 			res := testClient(t).GET(
 				fmt.Sprintf("/event/%v", testCase.submitIdPathParam)).Expect()
 			res.Header("Content-type").Contains("application/json")
@@ -345,8 +569,10 @@ var DeleteEventTestCases = []struct {
 		adminToken:        "invalid_admin_token",
 		expectedStatus:    http.StatusUnauthorized,
 		expectedResp: &weberrors.AppError{
-			ErrorName:   http.StatusText(http.StatusUnauthorized),
-			Description: "invalid admin token",
+			ErrorName:     http.StatusText(http.StatusUnauthorized),
+			ErrorCode:     weberrors.InvalidAdminTokenCode,
+			Description:   "Invalid admin token.",
+			CorrelationId: testFuncs.CorrelationId,
 		},
 	},
 	{
@@ -356,7 +582,7 @@ var DeleteEventTestCases = []struct {
 		validationsCheckUuidFormatResp: true,
 		dbDeleteEventMockErr:           errors.New("redis connection error"),
 		expectedStatus:                 http.StatusInternalServerError,
-		expectedResp:                   weberrors.ParseAppError(&weberrors.InternalError),
+		expectedResp:                   expectedAppError(&weberrors.InternalError),
 	},
 }
 
@@ -369,10 +595,18 @@ func TestDeleteEvent(t *testing.T) {
 				assert.Equal(t, testCase.submitIdPathParam, inputString)
 				return testCase.validationsCheckUuidFormatResp
 			}
-			db.DeleteEvent = func(id string) error {
+			db.DeleteEvent = func(c context.Context, id string) error {
 				assert.Equal(t, testCase.submitIdPathParam, id)
 				return testCase.dbDeleteEventMockErr
 			}
+			scheduler.CancelReminder = func(c context.Context, eventId string) error {
+				assert.Equal(t, testCase.submitIdPathParam, eventId)
+				return nil
+			}
+			scheduler.CancelTransitions = func(c context.Context, eventId string) error {
+				assert.Equal(t, testCase.submitIdPathParam, eventId)
+				return nil
+			}
 			res := testClient(t).DELETE(
 				fmt.Sprintf("/event/%v", testCase.submitIdPathParam)).
 				WithHeader(utils.API_AUTH_HEADER_KEY, testCase.adminToken).
@@ -386,3 +620,321 @@ func TestDeleteEvent(t *testing.T) {
 	}
 	auth.AdminToken = originalToken
 }
+
+var TransitionEventTestCases = []struct {
+	description                    string
+	submitIdPathParam              string
+	submitAction                   string
+	validationsCheckUuidFormatResp bool
+	dbGetEventMockResp             models.EventResponseData
+	dbGetEventMockErr              error
+	dbUpdateEventMockErr           error
+	expectedUpdatedStatus          string
+	expectedReminderScheduled      bool
+	expectedReminderCancelled      bool
+	expectedStatus                 int
+	expectedResp                   interface{}
+}{
+	{
+		description:                    "Success - publish draft",
+		submitIdPathParam:              "90a04b08-d820-4106-8ced-2cbc940728a3",
+		submitAction:                   lifecycle.ActionPublish,
+		validationsCheckUuidFormatResp: true,
+		dbGetEventMockResp: models.EventResponseData{
+			Id:        "90a04b08-d820-4106-8ced-2cbc940728a3",
+			EventData: models.EventData{Name: "event-name", Status: lifecycle.Draft},
+		},
+		expectedUpdatedStatus:     lifecycle.Scheduled,
+		expectedReminderScheduled: true,
+		expectedStatus:            http.StatusOK,
+		expectedResp: models.EventResponseData{
+			Id:        "90a04b08-d820-4106-8ced-2cbc940728a3",
+			EventData: models.EventData{Name: "event-name", Status: lifecycle.Scheduled},
+		},
+	},
+	{
+		description:                    "Success - cancel scheduled",
+		submitIdPathParam:              "90a04b08-d820-4106-8ced-2cbc940728a3",
+		submitAction:                   lifecycle.ActionCancel,
+		validationsCheckUuidFormatResp: true,
+		dbGetEventMockResp: models.EventResponseData{
+			Id:        "90a04b08-d820-4106-8ced-2cbc940728a3",
+			EventData: models.EventData{Name: "event-name", Status: lifecycle.Scheduled},
+		},
+		expectedUpdatedStatus:     lifecycle.Cancelled,
+		expectedReminderCancelled: true,
+		expectedStatus:            http.StatusOK,
+		expectedResp: models.EventResponseData{
+			Id:        "90a04b08-d820-4106-8ced-2cbc940728a3",
+			EventData: models.EventData{Name: "event-name", Status: lifecycle.Cancelled},
+		},
+	},
+	{
+		description:                    "Fail - illegal transition",
+		submitIdPathParam:              "90a04b08-d820-4106-8ced-2cbc940728a3",
+		submitAction:                   lifecycle.ActionEnd,
+		validationsCheckUuidFormatResp: true,
+		dbGetEventMockResp: models.EventResponseData{
+			Id:        "90a04b08-d820-4106-8ced-2cbc940728a3",
+			EventData: models.EventData{Name: "event-name", Status: lifecycle.Draft},
+		},
+		expectedStatus: http.StatusConflict,
+		expectedResp: expectedAppError(weberrors.InvalidStateTransition.WithMessage(
+			weberrors.StatusTransitionCode, weberrors.Param("draft"), weberrors.Param("end"))),
+	},
+	{
+		description:                    "Fail - invalid uuid - resource not found",
+		submitIdPathParam:              "invalid-uuid-string",
+		submitAction:                   lifecycle.ActionPublish,
+		validationsCheckUuidFormatResp: false,
+		expectedStatus:                 http.StatusNotFound,
+		expectedResp:                   expectedAppError(&weberrors.NotFound),
+	},
+	{
+		description:                    "Fail - event does not exist",
+		submitIdPathParam:              "90a04b08-d820-4106-8ced-2cbc940728a3",
+		submitAction:                   lifecycle.ActionPublish,
+		validationsCheckUuidFormatResp: true,
+		dbGetEventMockErr:              db.ErrNotFound,
+		expectedStatus:                 http.StatusNotFound,
+		expectedResp:                   expectedAppError(&weberrors.NotFound),
+	},
+	{
+		description:                    "Fail - db unexpected error on update",
+		submitIdPathParam:              "90a04b08-d820-4106-8ced-2cbc940728a3",
+		submitAction:                   lifecycle.ActionPublish,
+		validationsCheckUuidFormatResp: true,
+		dbGetEventMockResp: models.EventResponseData{
+			Id:        "90a04b08-d820-4106-8ced-2cbc940728a3",
+			EventData: models.EventData{Name: "event-name", Status: lifecycle.Draft},
+		},
+		dbUpdateEventMockErr:  errors.New("redis connection error"),
+		expectedUpdatedStatus: lifecycle.Scheduled,
+		expectedStatus:        http.StatusInternalServerError,
+		expectedResp:          expectedAppError(&weberrors.InternalError),
+	},
+	{
+		description:                    "Fail - status changed concurrently",
+		submitIdPathParam:              "90a04b08-d820-4106-8ced-2cbc940728a3",
+		submitAction:                   lifecycle.ActionPublish,
+		validationsCheckUuidFormatResp: true,
+		dbGetEventMockResp: models.EventResponseData{
+			Id:        "90a04b08-d820-4106-8ced-2cbc940728a3",
+			EventData: models.EventData{Name: "event-name", Status: lifecycle.Draft},
+		},
+		dbUpdateEventMockErr:  &db.Error{Kind: db.ErrConflict, Op: "update event", Key: "90a04b08-d820-4106-8ced-2cbc940728a3"},
+		expectedUpdatedStatus: lifecycle.Scheduled,
+		expectedStatus:        http.StatusConflict,
+		expectedResp:          expectedAppError(&weberrors.ConcurrentUpdate),
+	},
+}
+
+func TestTransitionEvent(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	for _, testCase := range TransitionEventTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			validations.CheckUuidFormat = func(inputString string) bool {
+				assert.Equal(t, testCase.submitIdPathParam, inputString)
+				return testCase.validationsCheckUuidFormatResp
+			}
+			db.GetEvent = func(c context.Context, id string) (models.EventResponseData, error) {
+				assert.Equal(t, testCase.submitIdPathParam, id)
+				return testCase.dbGetEventMockResp, testCase.dbGetEventMockErr
+			}
+			db.UpdateEventStatus = func(c context.Context, id string, expectedStatus string, payload models.EventData) error {
+				assert.Equal(t, testCase.submitIdPathParam, id)
+				assert.Equal(t, testCase.dbGetEventMockResp.Status, expectedStatus)
+				assert.Equal(t, testCase.expectedUpdatedStatus, payload.Status)
+				return testCase.dbUpdateEventMockErr
+			}
+			reminderScheduled, reminderCancelled := false, false
+			transitionsScheduled, transitionsCancelled := false, false
+			scheduler.ScheduleReminder = func(c context.Context, event models.EventResponseData) error {
+				reminderScheduled = true
+				return nil
+			}
+			scheduler.CancelReminder = func(c context.Context, eventId string) error {
+				reminderCancelled = true
+				return nil
+			}
+			scheduler.ScheduleTransitions = func(c context.Context, event models.EventResponseData) error {
+				transitionsScheduled = true
+				return nil
+			}
+			scheduler.CancelTransitions = func(c context.Context, eventId string) error {
+				transitionsCancelled = true
+				return nil
+			}
+			res := testClient(t).POST(
+				fmt.Sprintf("/event/%v/%v", testCase.submitIdPathParam, testCase.submitAction)).
+				WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+				Expect()
+			res.Header("Content-type").Contains("application/json")
+			res.Status(testCase.expectedStatus)
+			res.JSON().Equal(testCase.expectedResp)
+			assert.Equal(t, testCase.expectedReminderScheduled, reminderScheduled)
+			assert.Equal(t, testCase.expectedReminderCancelled, reminderCancelled)
+			assert.Equal(t, testCase.expectedReminderScheduled, transitionsScheduled)
+			assert.Equal(t, testCase.expectedReminderCancelled, transitionsCancelled)
+		})
+	}
+	auth.AdminToken = originalToken
+}
+
+var GetJobTestCases = []struct {
+	description       string
+	submitIdPathParam string
+	dbGetJobMockResp  models.Job
+	dbGetJobMockErr   error
+	expectedStatus    int
+	expectedResp      interface{}
+}{
+	{
+		description:       "Success",
+		submitIdPathParam: "reminder-90a04b08-d820-4106-8ced-2cbc940728a3",
+		dbGetJobMockResp: models.Job{
+			Id:      "reminder-90a04b08-d820-4106-8ced-2cbc940728a3",
+			Type:    scheduler.JobTypeReminder,
+			EventId: "90a04b08-d820-4106-8ced-2cbc940728a3",
+			RunAt:   time.Date(2023, 4, 20, 13, 45, 0, 0, time.UTC),
+			Status:  scheduler.StatusPending,
+		},
+		expectedStatus: http.StatusOK,
+		expectedResp: models.Job{
+			Id:      "reminder-90a04b08-d820-4106-8ced-2cbc940728a3",
+			Type:    scheduler.JobTypeReminder,
+			EventId: "90a04b08-d820-4106-8ced-2cbc940728a3",
+			RunAt:   time.Date(2023, 4, 20, 13, 45, 0, 0, time.UTC),
+			Status:  scheduler.StatusPending,
+		},
+	},
+	{
+		description:       "Fail - job does not exist",
+		submitIdPathParam: "non-existent-id",
+		dbGetJobMockErr:   db.ErrNotFound,
+		expectedStatus:    http.StatusNotFound,
+		expectedResp:      expectedAppError(&weberrors.NotFound),
+	},
+	{
+		description:       "Fail - db unexpected error",
+		submitIdPathParam: "any-id",
+		dbGetJobMockErr:   errors.New("redis connection error"),
+		expectedStatus:    http.StatusInternalServerError,
+		expectedResp:      expectedAppError(&weberrors.InternalError),
+	},
+}
+
+func TestGetJob(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	for _, testCase := range GetJobTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			db.GetJob = func(c context.Context, id string) (models.Job, error) {
+				assert.Equal(t, testCase.submitIdPathParam, id)
+				return testCase.dbGetJobMockResp, testCase.dbGetJobMockErr
+			}
+			res := testClient(t).GET(
+				fmt.Sprintf("/jobs/%v", testCase.submitIdPathParam)).
+				WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+				Expect()
+			res.Header("Content-type").Contains("application/json")
+			res.Status(testCase.expectedStatus)
+			res.JSON().Equal(testCase.expectedResp)
+		})
+	}
+	auth.AdminToken = originalToken
+}
+
+func TestGetQueuedJobs(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	t.Run("Success", func(t *testing.T) {
+		queuedJobs := []models.Job{{Id: "job-1", Status: scheduler.StatusPending}}
+		db.GetQueuedJobs = func(c context.Context) ([]models.Job, error) {
+			return queuedJobs, nil
+		}
+		res := testClient(t).GET("/jobs").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			Expect()
+		res.Status(http.StatusOK)
+		res.JSON().Array().Length().Equal(1)
+		res.JSON().Array().Element(0).Object().ValueEqual("id", "job-1")
+	})
+	auth.AdminToken = originalToken
+}
+
+func TestGetDeadNotifications(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	t.Run("Success", func(t *testing.T) {
+		db.GetDeadNotifications = func(c context.Context) ([]models.Notification, error) {
+			return []models.Notification{{Id: "notification-id", Attempts: 5}}, nil
+		}
+		res := testClient(t).GET("/notifications/dead").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			Expect()
+		res.Status(http.StatusOK)
+		res.JSON().Array().Element(0).Object().ValueEqual("id", "notification-id")
+	})
+	t.Run("Fail - db unexpected error", func(t *testing.T) {
+		db.GetDeadNotifications = func(c context.Context) ([]models.Notification, error) {
+			return nil, errors.New("redis connection error")
+		}
+		res := testClient(t).GET("/notifications/dead").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			Expect()
+		res.Status(http.StatusInternalServerError)
+		res.JSON().Equal(expectedAppError(&weberrors.InternalError))
+	})
+	auth.AdminToken = originalToken
+}
+
+func TestProbeRoutes(t *testing.T) {
+	originalPing := db.Ping
+	defer func() { db.Ping = originalPing }()
+	t.Run("Liveness does not check dependencies", func(t *testing.T) {
+		health.Register("test-dependency", true, 0, func(c context.Context) error {
+			return errors.New("connection refused")
+		})
+		defer health.Unregister("test-dependency")
+
+		testClient(t).GET("/livez").
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().ValueEqual("status", health.StatusOk)
+	})
+
+	t.Run("Ready with degraded non-critical dependency", func(t *testing.T) {
+		db.Ping = func(c context.Context) error {
+			return nil
+		}
+		health.Register("test-dependency", false, 0, func(c context.Context) error {
+			return errors.New("connection refused")
+		})
+		defer health.Unregister("test-dependency")
+
+		res := testClient(t).GET("/readyz").Expect()
+
+		res.Status(http.StatusOK)
+		res.JSON().Object().ValueEqual("status", health.StatusDegraded)
+		res.JSON().Path("$.checks[*].name").Array().Contains("redis", "test-dependency")
+	})
+
+	t.Run("Not ready when Redis is down", func(t *testing.T) {
+		db.Ping = func(c context.Context) error {
+			return errors.New("connection refused")
+		}
+		// changing checks invalidates cached results
+		health.Unregister("test-dependency")
+
+		res := testClient(t).GET("/readyz").Expect()
+
+		res.Status(http.StatusServiceUnavailable)
+		res.JSON().Object().ValueEqual("status", health.StatusFailing)
+		redisCheck := res.JSON().Path("$.checks[0]").Object()
+		redisCheck.ValueEqual("name", "redis")
+		redisCheck.ValueEqual("critical", true)
+		redisCheck.ValueEqual("error", "connection refused")
+	})
+}
@@ -0,0 +1,165 @@
+package routes
+
+import (
+	"app/auth"
+	"app/changefeed"
+	"app/db"
+	"app/health"
+	lg "app/logging"
+	"app/models"
+	"app/utils"
+	"app/weberrors"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slices"
+)
+
+// streamKeepAliveInterval is how often keep-alive comment is sent
+var streamKeepAliveInterval = 15 * time.Second
+
+// streamBacklogBatchSize is how many changes are read at once when resuming after Last-Event-ID
+const streamBacklogBatchSize = 100
+
+var streamIdRegex = regexp.MustCompile(`^\d+-\d+$`)
+
+// messageTypes maps domain events to change feed message types
+var messageTypes = map[string]string{
+	models.DomainEventCreated: models.ChangeCreated,
+	models.DomainEventUpdated: models.ChangeUpdated,
+	models.DomainEventDeleted: models.ChangeDeleted,
+}
+
+// streamMessageTypes are values accepted by query `types`
+var streamMessageTypes = []string{models.ChangeCreated, models.ChangeUpdated, models.ChangeDeleted}
+
+// StreamEventsHandler streams event changes.
+// @Summary	Streams event changes as Server-Sent Events
+// @Description Emits `created`, `updated` and `deleted` messages, reconnecting clients resume after `Last-Event-ID`.
+// @Description Invitees are only included for callers with admin token.
+// @Description Events are not scoped to tenants, the stream includes events of every tenant.
+// @Tags		Event
+// @Produce text/event-stream
+// @Param API-AUTHENTICATION header 	string 	false "token string value"
+// @Param Last-Event-ID header string false "id of the last received message"
+// @Param types query string false "comma separated message types to receive (created,updated,deleted), unknown types are rejected"
+// @Success	200
+// @Failure 400,500,503 {object} weberrors.AppError
+// @Router		/events/stream [get]
+func StreamEventsHandler(ctx *gin.Context) {
+	lastId := ctx.GetHeader("Last-Event-ID")
+	if lastId != "" && !streamIdRegex.MatchString(lastId) {
+		utils.AppendContextError(ctx, weberrors.ValidationError.WithMessage(weberrors.InvalidLastEventIdHeaderCode))
+		return
+	}
+	types := streamMessageTypes
+	if query := ctx.Query("types"); query != "" {
+		types = strings.Split(query, ",")
+		for _, messageType := range types {
+			if !slices.Contains(streamMessageTypes, messageType) {
+				utils.AppendContextError(ctx, weberrors.ValidationError.WithMessage(
+					weberrors.InvalidTypesQueryCode, messageType, strings.Join(streamMessageTypes, ", ")))
+				return
+			}
+		}
+	}
+	// subscribe before reading the log so that changes appended meanwhile are not missed,
+	// changes received twice are skipped by their ids
+	feed, unsubscribe, err := changefeed.Subscribe(ctx)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	defer unsubscribe()
+	// resuming client gets changes already in the log first, the first batch is read before responding
+	// so that storage failure is reported
+	var backlog []models.DomainEvent
+	if lastId == "" {
+		lastId, err = db.GetLatestChangeId(ctx)
+	} else {
+		backlog, err = getBacklog(ctx, lastId)
+	}
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	includeInvitees := auth.IsAdmin(ctx)
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	for len(backlog) > 0 {
+		lastId = writeChanges(ctx, backlog, lastId, types, includeInvitees)
+		ctx.Writer.Flush()
+		if len(backlog) < streamBacklogBatchSize {
+			break
+		}
+		if backlog, err = getBacklog(ctx, lastId); err != nil {
+			lg.WithContext(ctx).Error().Msgf("error reading change log: %v", err)
+			return
+		}
+	}
+	ctx.Writer.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAliveInterval)
+	defer keepAlive.Stop()
+	requestContext := ctx.Request.Context()
+	for {
+		select {
+		case <-requestContext.Done():
+			return
+		case <-health.Draining():
+			// client reconnects to another replica with Last-Event-ID
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(ctx.Writer, ": keep-alive\n\n")
+		case changes, ok := <-feed:
+			if !ok {
+				// client lags behind or the feed failed, it resumes with Last-Event-ID
+				lg.WithContext(ctx).Warn().Msg("change feed subscription ended")
+				return
+			}
+			lastId = writeChanges(ctx, changes, lastId, types, includeInvitees)
+		}
+		ctx.Writer.Flush()
+	}
+}
+
+// getBacklog returns a batch of changes after `lastId` which are already in the log.
+func getBacklog(ctx *gin.Context, lastId string) ([]models.DomainEvent, error) {
+	start, err := db.NextChangeId(lastId)
+	if err != nil {
+		return nil, err
+	}
+	return db.GetChanges(ctx, start, streamBacklogBatchSize)
+}
+
+// writeChanges writes changes after `lastId` as messages of `types` and returns id of the last one.
+func writeChanges(ctx *gin.Context, changes []models.DomainEvent, lastId string, types []string, includeInvitees bool) string {
+	for _, change := range changes {
+		if !db.IsChangeAfter(change.Id, lastId) {
+			continue
+		}
+		lastId = change.Id
+		messageType := messageTypes[change.Type]
+		if !slices.Contains(types, messageType) {
+			continue
+		}
+		if !includeInvitees {
+			change.Event.Invitees = nil
+		}
+		data, err := json.Marshal(change.Event)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(ctx.Writer, "id: %v\nevent: %v\ndata: %s\n\n", change.Id, messageType, data)
+	}
+	return lastId
+}
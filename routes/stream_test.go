@@ -0,0 +1,158 @@
+package routes
+
+import (
+	"app/auth"
+	"app/config"
+	"app/db"
+	"app/models"
+	"app/utils"
+	"app/weberrors"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var streamedChanges = []models.DomainEvent{
+	{
+		Id:   "1682000000000-0",
+		Type: models.DomainEventCreated,
+		Event: models.EventResponseData{
+			Id:        "90a04b08-d820-4106-8ced-2cbc940728a3",
+			EventData: models.EventData{Name: "event-name", Invitees: []string{"valid-email@mail.com"}},
+		},
+	},
+	{
+		Id:    "1682000000001-0",
+		Type:  models.DomainEventDeleted,
+		Event: models.EventResponseData{Id: "90a04b08-d820-4106-8ced-2cbc940728a3"},
+	},
+}
+
+var StreamEventsTestCases = []struct {
+	description          string
+	lastEventId          string
+	query                string
+	adminToken           string
+	expectedBacklogStart string
+	expectedContains     []string
+	expectedNotContain   []string
+}{
+	{
+		description: "Stream from latest change, invitees hidden",
+		expectedContains: []string{
+			"id: 1682000000000-0\nevent: created\ndata: {\"id\":\"90a04b08-d820-4106-8ced-2cbc940728a3\",\"name\":\"event-name\"",
+			"id: 1682000000001-0\nevent: deleted\n",
+		},
+		expectedNotContain: []string{"valid-email@mail.com"},
+	},
+	{
+		description:          "Resume after Last-Event-ID, admin sees invitees",
+		lastEventId:          "1681000000000-0",
+		adminToken:           adminTokenTestString,
+		expectedBacklogStart: "1681000000000-1",
+		expectedContains:     []string{"valid-email@mail.com"},
+	},
+	{
+		description:        "Filter by types",
+		query:              "?types=deleted",
+		expectedContains:   []string{"event: deleted"},
+		expectedNotContain: []string{"event: created"},
+	},
+}
+
+// streamRecorder ends the request once the last streamed change is written
+type streamRecorder struct {
+	*httptest.ResponseRecorder
+	cancel context.CancelFunc
+}
+
+func (r streamRecorder) Write(data []byte) (int, error) {
+	n, err := r.ResponseRecorder.Write(data)
+	if strings.Contains(r.Body.String(), streamedChanges[1].Id) {
+		r.cancel()
+	}
+	return n, err
+}
+
+func TestStreamEvents(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	// the feed reader delivers the same changes as the log, a resumed client receives them once,
+	// the mock is kept for all cases as reader of the previous case may still be stopping
+	db.ReadChanges = func(c context.Context, lastId string, block time.Duration) ([]models.DomainEvent, error) {
+		if lastId == "1681999999999-0" {
+			return streamedChanges, nil
+		}
+		<-c.Done()
+		return nil, c.Err()
+	}
+	for _, testCase := range StreamEventsTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			requestContext, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			db.GetLatestChangeId = func(c context.Context) (string, error) {
+				return "1681999999999-0", nil
+			}
+			db.GetChanges = func(c context.Context, start string, limit int64) ([]models.DomainEvent, error) {
+				assert.Equal(t, testCase.expectedBacklogStart, start)
+				return streamedChanges, nil
+			}
+			app := gin.New()
+			InitApp(app, config.Default())
+			request, _ := http.NewRequestWithContext(
+				requestContext, http.MethodGet, "/events/stream"+testCase.query, nil)
+			if testCase.lastEventId != "" {
+				request.Header.Set("Last-Event-ID", testCase.lastEventId)
+			}
+			request.Header.Set(utils.API_AUTH_HEADER_KEY, testCase.adminToken)
+			recorder := httptest.NewRecorder()
+
+			app.ServeHTTP(streamRecorder{ResponseRecorder: recorder, cancel: cancel}, request)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+			for _, expected := range testCase.expectedContains {
+				assert.Contains(t, recorder.Body.String(), expected)
+			}
+			for _, unexpected := range testCase.expectedNotContain {
+				assert.NotContains(t, recorder.Body.String(), unexpected)
+			}
+			assert.Equal(t, 1, strings.Count(recorder.Body.String(), "id: "+streamedChanges[1].Id))
+		})
+	}
+	auth.AdminToken = originalToken
+}
+
+func TestStreamEventsStorageFailure(t *testing.T) {
+	db.GetLatestChangeId = func(c context.Context) (string, error) {
+		return "", db.ErrUnavailable
+	}
+	res := testClient(t).GET("/events/stream").Expect()
+	res.Status(http.StatusServiceUnavailable)
+}
+
+func TestStreamEventsInvalidLastEventId(t *testing.T) {
+	t.Run("Fail - invalid Last-Event-ID", func(t *testing.T) {
+		res := testClient(t).GET("/events/stream").
+			WithHeader("Last-Event-ID", "not-a-stream-id").
+			Expect()
+		res.Status(http.StatusBadRequest)
+	})
+}
+
+func TestStreamEventsInvalidTypes(t *testing.T) {
+	t.Run("Fail - unknown message type", func(t *testing.T) {
+		res := testClient(t).GET("/events/stream").
+			WithQuery("types", "deleted,removed").
+			Expect()
+		res.Status(http.StatusBadRequest)
+		res.JSON().Equal(expectedAppError(weberrors.ValidationError.WithMessage(
+			weberrors.InvalidTypesQueryCode, "removed", "created, updated, deleted")))
+	})
+}
@@ -0,0 +1,193 @@
+package routes
+
+import (
+	"app/db"
+	"app/models"
+	"app/utils"
+	"app/validations"
+	"app/weberrors"
+	"app/webhooks"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func initWebhookRoutes(adminGroup *gin.RouterGroup) {
+	adminGroup.POST("/webhooks", CreateWebhookHandler)
+	adminGroup.GET("/webhooks", GetWebhooksHandler)
+	adminGroup.GET("/webhooks/:id", GetWebhookHandler)
+	adminGroup.DELETE("/webhooks/:id", DeleteWebhookHandler)
+	adminGroup.GET("/webhooks/:id/deliveries", GetWebhookDeliveriesHandler)
+	adminGroup.POST("/webhooks/:id/deliveries/:deliveryId/redeliver", RedeliverWebhookHandler)
+}
+
+// secret is write-only, it is never part of the response
+func withoutSecret(subscription models.WebhookSubscription) models.WebhookSubscription {
+	subscription.Secret = ""
+	return subscription
+}
+
+// CreateWebhookHandler creates webhook subscription.
+// @Summary	Subscribes URL to event lifecycle changes
+// @Description Deliveries are signed by `X-Signature-256: sha256=<hex HMAC-SHA256 of body keyed by secret>`.
+// @Tags		Webhooks
+// @Accept json
+// @Produce json
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param subscription body models.WebhookSubscriptionData true "Subscription Data"
+// @Success	201 {object} models.WebhookSubscription
+// @Failure 400,500 {object} weberrors.AppError
+// @Router		/webhooks [post]
+func CreateWebhookHandler(ctx *gin.Context) {
+	payload := models.WebhookSubscriptionData{}
+	bindError := ctx.ShouldBind(&payload)
+	if bindError != nil {
+		if parsedErr := validations.GetBindErrors(ctx, bindError); parsedErr != nil {
+			utils.AppendContextError(ctx, parsedErr)
+			return
+		}
+		utils.AppendContextError(ctx, &weberrors.InvalidPayload)
+		return
+	}
+	subscription, err := db.CreateWebhookSubscription(ctx, payload)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	audit(ctx, models.AuditWebhookCreate, subscription.Id, nil, withoutSecret(subscription))
+	ctx.JSON(http.StatusCreated, withoutSecret(subscription))
+}
+
+// GetWebhooksHandler lists webhook subscriptions.
+// @Summary	Lists webhook subscriptions
+// @Tags		Webhooks
+// @Produce json
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Success	200 {array} models.WebhookSubscription
+// @Failure 500 {object} weberrors.AppError
+// @Router		/webhooks [get]
+func GetWebhooksHandler(ctx *gin.Context) {
+	subscriptions, err := db.GetWebhookSubscriptions(ctx)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	for i := range subscriptions {
+		subscriptions[i] = withoutSecret(subscriptions[i])
+	}
+	ctx.JSON(http.StatusOK, subscriptions)
+}
+
+// GetWebhookHandler retrieves webhook subscription.
+// @Summary	Retrieves webhook subscription
+// @Tags		Webhooks
+// @Produce json
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param id path string true "Subscription ID (uuid)"
+// @Success	200 {object} models.WebhookSubscription
+// @Failure 404,500 {object} weberrors.AppError
+// @Router		/webhooks/{id} [get]
+func GetWebhookHandler(ctx *gin.Context) {
+	subscription, ok := getWebhookSubscription(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, withoutSecret(subscription))
+}
+
+// DeleteWebhookHandler removes webhook subscription.
+// @Summary	Deletes webhook subscription
+// @Tags		Webhooks
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param id path string true "Subscription ID (uuid)"
+// @Success	204
+// @Failure 404,500 {object} weberrors.AppError
+// @Router		/webhooks/{id} [delete]
+func DeleteWebhookHandler(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if !validations.CheckUuidFormat(id) {
+		utils.AppendContextError(ctx, &weberrors.NotFound)
+		return
+	}
+	err := db.DeleteWebhookSubscription(ctx, id)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	audit(ctx, models.AuditWebhookDelete, id, nil, nil)
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetWebhookDeliveriesHandler lists deliveries of webhook subscription.
+// @Summary	Lists latest deliveries of webhook subscription, newest first
+// @Tags		Webhooks
+// @Produce json
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param id path string true "Subscription ID (uuid)"
+// @Success	200 {array} models.WebhookDelivery
+// @Failure 404,500 {object} weberrors.AppError
+// @Router		/webhooks/{id}/deliveries [get]
+func GetWebhookDeliveriesHandler(ctx *gin.Context) {
+	subscription, ok := getWebhookSubscription(ctx)
+	if !ok {
+		return
+	}
+	deliveries, err := db.GetWebhookDeliveries(ctx, subscription.Id)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+// RedeliverWebhookHandler queues delivery again.
+// @Summary	Queues webhook delivery to be sent again
+// @Tags		Webhooks
+// @Produce json
+// @Param API-AUTHENTICATION header 	string 	true "token string value"
+// @Param id path string true "Subscription ID (uuid)"
+// @Param deliveryId path string true "Delivery ID (uuid)"
+// @Success	202 {object} models.WebhookDelivery
+// @Failure 404,500 {object} weberrors.AppError
+// @Router		/webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func RedeliverWebhookHandler(ctx *gin.Context) {
+	subscription, ok := getWebhookSubscription(ctx)
+	if !ok {
+		return
+	}
+	deliveryId := ctx.Param("deliveryId")
+	if !validations.CheckUuidFormat(deliveryId) {
+		utils.AppendContextError(ctx, &weberrors.NotFound)
+		return
+	}
+	delivery, err := db.GetWebhookDelivery(ctx, deliveryId)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	if delivery.SubscriptionId != subscription.Id {
+		utils.AppendContextError(ctx, &weberrors.NotFound)
+		return
+	}
+	delivery, err = webhooks.Redeliver(ctx, delivery)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return
+	}
+	audit(ctx, models.AuditWebhookRedeliver, delivery.Id, nil, delivery)
+	ctx.JSON(http.StatusAccepted, delivery)
+}
+
+func getWebhookSubscription(ctx *gin.Context) (models.WebhookSubscription, bool) {
+	id := ctx.Param("id")
+	if !validations.CheckUuidFormat(id) {
+		utils.AppendContextError(ctx, &weberrors.NotFound)
+		return models.WebhookSubscription{}, false
+	}
+	subscription, err := db.GetWebhookSubscription(ctx, id)
+	if err != nil {
+		utils.AppendContextError(ctx, err)
+		return models.WebhookSubscription{}, false
+	}
+	return subscription, true
+}
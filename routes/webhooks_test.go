@@ -0,0 +1,257 @@
+package routes
+
+import (
+	"app/auth"
+	"app/db"
+	"app/models"
+	"app/utils"
+	"app/validations"
+	"app/weberrors"
+	"app/webhooks"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var webhookSubscriptionId = "0b1d34a7-8bcd-47f3-8923-d472510d8da4"
+var webhookDeliveryId = "90a04b08-d820-4106-8ced-2cbc940728a3"
+
+var webhookSubscriptionData = models.WebhookSubscriptionData{
+	Url:        "https://example.com/hooks/events",
+	Secret:     "a-long-random-secret",
+	EventTypes: []string{models.WebhookEventCreated},
+}
+
+var CreateWebhookTestCases = []struct {
+	description      string
+	submitedPayload  interface{}
+	dbCreateErr      error
+	expectedStatus   int
+	expectedResponse interface{}
+}{
+	{
+		description:     "Success - secret is not returned",
+		submitedPayload: webhookSubscriptionData,
+		expectedStatus:  http.StatusCreated,
+		expectedResponse: models.WebhookSubscription{
+			Id:        webhookSubscriptionId,
+			CreatedAt: time.Date(2023, 4, 20, 13, 45, 0, 0, time.UTC),
+			WebhookSubscriptionData: models.WebhookSubscriptionData{
+				Url:        webhookSubscriptionData.Url,
+				EventTypes: webhookSubscriptionData.EventTypes,
+			},
+		},
+	},
+	{
+		description: "Fail - unknown event type",
+		submitedPayload: models.WebhookSubscriptionData{
+			Url:        "https://example.com/hooks/events",
+			Secret:     "a-long-random-secret",
+			EventTypes: []string{"event.archived"},
+		},
+		expectedStatus: http.StatusBadRequest,
+		expectedResponse: expectedAppError(weberrors.ValidationError.ChangeDesc(
+			"field `eventTypes[0]` needs to be one of values: event.created event.updated event.deleted")),
+	},
+	{
+		description: "Fail - invalid url and short secret",
+		submitedPayload: models.WebhookSubscriptionData{
+			Url:        "not-an-url",
+			Secret:     "short",
+			EventTypes: []string{models.WebhookEventCreated},
+		},
+		expectedStatus: http.StatusBadRequest,
+		expectedResponse: expectedAppError(weberrors.ValidationError.ChangeDesc(
+			"field `url` is invalid, field `secret` must be longer than 16")),
+	},
+	{
+		description:      "Fail - db unexpected error",
+		submitedPayload:  webhookSubscriptionData,
+		dbCreateErr:      errors.New("redis connection error"),
+		expectedStatus:   http.StatusInternalServerError,
+		expectedResponse: expectedAppError(&weberrors.InternalError),
+	},
+}
+
+func TestCreateWebhook(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	for _, testCase := range CreateWebhookTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			db.CreateWebhookSubscription = func(c context.Context, payload models.WebhookSubscriptionData) (models.WebhookSubscription, error) {
+				assert.Equal(t, testCase.submitedPayload, payload)
+				return models.WebhookSubscription{
+					Id:                      webhookSubscriptionId,
+					CreatedAt:               time.Date(2023, 4, 20, 13, 45, 0, 0, time.UTC),
+					WebhookSubscriptionData: payload,
+				}, testCase.dbCreateErr
+			}
+			res := testClient(t).POST("/webhooks").
+				WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+				WithJSON(testCase.submitedPayload).Expect()
+			res.Header("Content-type").Contains("application/json")
+			res.Status(testCase.expectedStatus)
+			res.JSON().Equal(testCase.expectedResponse)
+		})
+	}
+	auth.AdminToken = originalToken
+}
+
+func TestGetWebhook(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	validations.CheckUuidFormat = func(inputString string) bool {
+		return inputString != "invalid-uuid"
+	}
+	t.Run("Success - secret is not returned", func(t *testing.T) {
+		db.GetWebhookSubscription = func(c context.Context, id string) (models.WebhookSubscription, error) {
+			return models.WebhookSubscription{Id: id, WebhookSubscriptionData: webhookSubscriptionData}, nil
+		}
+		res := testClient(t).GET(fmt.Sprintf("/webhooks/%v", webhookSubscriptionId)).
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			Expect()
+		res.Status(http.StatusOK)
+		res.JSON().Object().ValueEqual("id", webhookSubscriptionId)
+		res.JSON().Object().NotContainsKey("secret")
+	})
+	t.Run("Fail - invalid uuid - resource not found", func(t *testing.T) {
+		res := testClient(t).GET("/webhooks/invalid-uuid").
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			Expect()
+		res.Status(http.StatusNotFound)
+		res.JSON().Equal(expectedAppError(&weberrors.NotFound))
+	})
+	t.Run("Fail - subscription does not exist", func(t *testing.T) {
+		db.GetWebhookSubscription = func(c context.Context, id string) (models.WebhookSubscription, error) {
+			return models.WebhookSubscription{}, db.ErrNotFound
+		}
+		res := testClient(t).GET(fmt.Sprintf("/webhooks/%v", webhookSubscriptionId)).
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			Expect()
+		res.Status(http.StatusNotFound)
+		res.JSON().Equal(expectedAppError(&weberrors.NotFound))
+	})
+	auth.AdminToken = originalToken
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	validations.CheckUuidFormat = func(inputString string) bool {
+		return true
+	}
+	t.Run("Success", func(t *testing.T) {
+		db.DeleteWebhookSubscription = func(c context.Context, id string) error {
+			assert.Equal(t, webhookSubscriptionId, id)
+			return nil
+		}
+		testClient(t).DELETE(fmt.Sprintf("/webhooks/%v", webhookSubscriptionId)).
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			Expect().
+			Status(http.StatusNoContent)
+	})
+	t.Run("Fail - subscription does not exist", func(t *testing.T) {
+		db.DeleteWebhookSubscription = func(c context.Context, id string) error {
+			return db.ErrNotFound
+		}
+		testClient(t).DELETE(fmt.Sprintf("/webhooks/%v", webhookSubscriptionId)).
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+	auth.AdminToken = originalToken
+}
+
+func TestGetWebhookDeliveries(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	validations.CheckUuidFormat = func(inputString string) bool {
+		return true
+	}
+	t.Run("Success", func(t *testing.T) {
+		db.GetWebhookSubscription = func(c context.Context, id string) (models.WebhookSubscription, error) {
+			return models.WebhookSubscription{Id: id}, nil
+		}
+		db.GetWebhookDeliveries = func(c context.Context, subscriptionId string) ([]models.WebhookDelivery, error) {
+			assert.Equal(t, webhookSubscriptionId, subscriptionId)
+			return []models.WebhookDelivery{{Id: webhookDeliveryId, Status: models.WebhookDeliveryDelivered}}, nil
+		}
+		res := testClient(t).GET(fmt.Sprintf("/webhooks/%v/deliveries", webhookSubscriptionId)).
+			WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+			Expect()
+		res.Status(http.StatusOK)
+		res.JSON().Array().Element(0).Object().ValueEqual("id", webhookDeliveryId)
+	})
+	auth.AdminToken = originalToken
+}
+
+var RedeliverWebhookTestCases = []struct {
+	description            string
+	storedDelivery         models.WebhookDelivery
+	dbGetDeliveryErr       error
+	expectedRedelivered    bool
+	expectedStatus         int
+	expectedResponseStatus string
+}{
+	{
+		description: "Success",
+		storedDelivery: models.WebhookDelivery{
+			Id: webhookDeliveryId, SubscriptionId: webhookSubscriptionId, Status: models.WebhookDeliveryFailed,
+		},
+		expectedRedelivered:    true,
+		expectedStatus:         http.StatusAccepted,
+		expectedResponseStatus: models.WebhookDeliveryPending,
+	},
+	{
+		description: "Fail - delivery of other subscription",
+		storedDelivery: models.WebhookDelivery{
+			Id: webhookDeliveryId, SubscriptionId: "other-subscription-id", Status: models.WebhookDeliveryFailed,
+		},
+		expectedStatus: http.StatusNotFound,
+	},
+	{
+		description:      "Fail - delivery does not exist",
+		dbGetDeliveryErr: db.ErrNotFound,
+		expectedStatus:   http.StatusNotFound,
+	},
+}
+
+func TestRedeliverWebhook(t *testing.T) {
+	originalToken := auth.AdminToken
+	auth.AdminToken = adminTokenTestString
+	validations.CheckUuidFormat = func(inputString string) bool {
+		return true
+	}
+	for _, testCase := range RedeliverWebhookTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			redelivered := false
+			db.GetWebhookSubscription = func(c context.Context, id string) (models.WebhookSubscription, error) {
+				return models.WebhookSubscription{Id: id}, nil
+			}
+			db.GetWebhookDelivery = func(c context.Context, id string) (models.WebhookDelivery, error) {
+				assert.Equal(t, webhookDeliveryId, id)
+				return testCase.storedDelivery, testCase.dbGetDeliveryErr
+			}
+			webhooks.Redeliver = func(c context.Context, delivery models.WebhookDelivery) (models.WebhookDelivery, error) {
+				redelivered = true
+				delivery.Status = models.WebhookDeliveryPending
+				return delivery, nil
+			}
+			res := testClient(t).POST(fmt.Sprintf(
+				"/webhooks/%v/deliveries/%v/redeliver", webhookSubscriptionId, webhookDeliveryId)).
+				WithHeader(utils.API_AUTH_HEADER_KEY, adminTokenTestString).
+				Expect()
+			res.Status(testCase.expectedStatus)
+			assert.Equal(t, testCase.expectedRedelivered, redelivered)
+			if testCase.expectedResponseStatus != "" {
+				res.JSON().Object().ValueEqual("status", testCase.expectedResponseStatus)
+			}
+		})
+	}
+	auth.AdminToken = originalToken
+}
@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"app/config"
+	"app/db"
+	"app/lifecycle"
+	"app/models"
+	"app/utils"
+	"context"
+	"errors"
+	"time"
+)
+
+const JobTypeReminder = "reminder"
+
+// reminderBefore is how long before event start invitees are reminded
+var reminderBefore = time.Duration(config.Default().Scheduler.ReminderMinutes) * time.Minute
+
+func init() {
+	Register(JobTypeReminder, remindInvitees)
+}
+
+// Configure sets how long before event start reminders are sent, it applies to reminders scheduled afterwards.
+func Configure(settings config.Scheduler) {
+	reminderBefore = time.Duration(settings.ReminderMinutes) * time.Minute
+}
+
+func reminderJobId(eventId string) string {
+	return JobTypeReminder + "-" + eventId
+}
+
+// ScheduleReminder schedules a reminder `scheduler.reminderMinutes` before the event starts,
+// rescheduling replaces the previous reminder of the event.
+var ScheduleReminder = func(c context.Context, event models.EventResponseData) error {
+	startTime, err := time.Parse(utils.TIMESTAMP_LAYOUT, event.Timestamp)
+	if err != nil {
+		return err
+	}
+	runAt := startTime.Add(-reminderBefore)
+	if runAt.Before(time.Now().UTC()) {
+		return nil
+	}
+	return db.ScheduleJob(c, models.Job{
+		Id:      reminderJobId(event.Id),
+		Type:    JobTypeReminder,
+		EventId: event.Id,
+		RunAt:   runAt,
+		Status:  StatusPending,
+	})
+}
+
+var CancelReminder = func(c context.Context, eventId string) error {
+	return cancelJob(c, reminderJobId(eventId))
+}
+
+// cancelJob cancels job `id` if it is still pending, missing job is ignored.
+func cancelJob(c context.Context, id string) error {
+	job, err := db.GetJob(c, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if job.Status != StatusPending {
+		return nil
+	}
+	job.Status = StatusCancelled
+	return db.FinishJob(c, job)
+}
+
+func remindInvitees(c context.Context, job models.Job) error {
+	event, err := db.GetEvent(c, job.EventId)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if lifecycle.Current(event.Status) != lifecycle.Scheduled {
+		return nil
+	}
+	return db.EnqueueNotification(c, models.NotificationEventReminder, event)
+}
@@ -0,0 +1,134 @@
+package scheduler
+
+import (
+	"app/db"
+	"app/lifecycle"
+	"app/models"
+	"app/utils"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleReminder(t *testing.T) {
+	t.Run("Reminder is scheduled before event starts", func(t *testing.T) {
+		startTime := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+		var scheduled *models.Job
+		db.ScheduleJob = func(c context.Context, job models.Job) error {
+			scheduled = &job
+			return nil
+		}
+
+		err := ScheduleReminder(context.Background(), models.EventResponseData{
+			Id:        "event-id",
+			EventData: models.EventData{Timestamp: startTime.Format(utils.TIMESTAMP_LAYOUT)},
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, &models.Job{
+			Id:      "reminder-event-id",
+			Type:    JobTypeReminder,
+			EventId: "event-id",
+			RunAt:   startTime.Add(-reminderBefore),
+			Status:  StatusPending,
+		}, scheduled)
+	})
+
+	t.Run("Reminder of past event is not scheduled", func(t *testing.T) {
+		db.ScheduleJob = func(c context.Context, job models.Job) error {
+			assert.Fail(t, "should not schedule job")
+			return nil
+		}
+
+		err := ScheduleReminder(context.Background(), models.EventResponseData{
+			Id:        "event-id",
+			EventData: models.EventData{Timestamp: "2023-04-20T14:00:00Z"},
+		})
+
+		assert.Nil(t, err)
+	})
+}
+
+var CancelReminderTestCases = []struct {
+	description      string
+	storedJob        models.Job
+	getJobErr        error
+	expectedFinished *models.Job
+}{
+	{
+		description: "Pending reminder is cancelled",
+		storedJob:   models.Job{Id: "reminder-event-id", Status: StatusPending},
+		expectedFinished: &models.Job{
+			Id: "reminder-event-id", Status: StatusCancelled,
+		},
+	},
+	{
+		description: "Done reminder is kept",
+		storedJob:   models.Job{Id: "reminder-event-id", Status: StatusDone},
+	},
+	{
+		description: "Missing reminder is ignored",
+		getJobErr:   db.ErrNotFound,
+	},
+}
+
+func TestCancelReminder(t *testing.T) {
+	for _, testCase := range CancelReminderTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			var finished *models.Job
+			db.GetJob = func(c context.Context, id string) (models.Job, error) {
+				assert.Equal(t, "reminder-event-id", id)
+				return testCase.storedJob, testCase.getJobErr
+			}
+			db.FinishJob = func(c context.Context, job models.Job) error {
+				finished = &job
+				return nil
+			}
+
+			err := CancelReminder(context.Background(), "event-id")
+
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.expectedFinished, finished)
+		})
+	}
+}
+
+func TestRemindInvitees(t *testing.T) {
+	t.Run("Reminder notification is written to outbox", func(t *testing.T) {
+		event := models.EventResponseData{
+			Id:        "event-id",
+			EventData: models.EventData{Status: lifecycle.Scheduled},
+		}
+		var enqueuedKind string
+		db.GetEvent = func(c context.Context, id string) (models.EventResponseData, error) {
+			return event, nil
+		}
+		db.EnqueueNotification = func(c context.Context, kind string, notifiedEvent models.EventResponseData) error {
+			enqueuedKind = kind
+			assert.Equal(t, event, notifiedEvent)
+			return nil
+		}
+		assert.Nil(t, remindInvitees(context.Background(), models.Job{EventId: "event-id"}))
+		assert.Equal(t, models.NotificationEventReminder, enqueuedKind)
+	})
+
+	t.Run("Cancelled event is not reminded", func(t *testing.T) {
+		db.GetEvent = func(c context.Context, id string) (models.EventResponseData, error) {
+			return models.EventResponseData{
+				Id:        id,
+				EventData: models.EventData{Status: lifecycle.Cancelled},
+			}, nil
+		}
+		assert.Nil(t, remindInvitees(context.Background(), models.Job{EventId: "event-id"}))
+	})
+
+	t.Run("Fail - db unexpected error is retried", func(t *testing.T) {
+		db.GetEvent = func(c context.Context, id string) (models.EventResponseData, error) {
+			return models.EventResponseData{}, errors.New("redis connection error")
+		}
+		assert.Error(t, remindInvitees(context.Background(), models.Job{EventId: "event-id"}))
+	})
+}
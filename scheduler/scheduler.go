@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"app/db"
+	"app/health"
+	"app/models"
+	"app/worker"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusDone      = "done"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+const (
+	retryDelay  = 30 * time.Second
+	maxAttempts = 3
+)
+
+// Handler runs a job, returned error makes the job retry.
+type Handler func(c context.Context, job models.Job) error
+
+var handlers = map[string]Handler{}
+
+func Register(jobType string, handler Handler) {
+	handlers[jobType] = handler
+}
+
+// Run fires due jobs until `c` is done.
+func Run(c context.Context) {
+	heartbeat := &health.Heartbeat{}
+	heartbeat.Beat(time.Now())
+	health.Register("scheduler", false, 0, heartbeat.Check(worker.HeartbeatMaxAge))
+	defer health.Unregister("scheduler")
+	worker.Run(c, "scheduler", heartbeat, RunDueJobs)
+}
+
+var RunDueJobs = func(c context.Context, now time.Time) {
+	worker.ProcessDue(c, now, worker.Queue{
+		Item:    "job",
+		Due:     db.GetDueJobIds,
+		Acquire: db.AcquireJobLease,
+		Release: db.ReleaseJobLease,
+	}, runJob)
+}
+
+func runJob(c context.Context, id string, now time.Time) {
+	job, err := db.GetJob(c, id)
+	if err != nil {
+		log.Logger.Error().Msgf("error reading job `%v`: %v", id, err)
+		return
+	}
+	// job could have been finished by other replica before the lease was acquired
+	if job.Status != StatusPending || job.RunAt.After(now) {
+		return
+	}
+	handler, found := handlers[job.Type]
+	if !found {
+		job.Status = StatusFailed
+		job.LastError = fmt.Sprintf("no handler registered for job type `%v`", job.Type)
+		finishJob(c, job)
+		return
+	}
+	job.Attempts++
+	if err := handler(c, job); err != nil {
+		log.Logger.Warn().Msgf("job `%v` failed (attempt %v): %v", job.Id, job.Attempts, err)
+		job.LastError = err.Error()
+		if job.Attempts < maxAttempts {
+			job.RunAt = now.Add(retryDelay * time.Duration(job.Attempts))
+			if err := db.ScheduleJob(c, job); err != nil {
+				log.Logger.Error().Msgf("error rescheduling job `%v`: %v", job.Id, err)
+			}
+			return
+		}
+		job.Status = StatusFailed
+		finishJob(c, job)
+		return
+	}
+	job.Status = StatusDone
+	job.LastError = ""
+	finishJob(c, job)
+}
+
+func finishJob(c context.Context, job models.Job) {
+	if err := db.FinishJob(c, job); err != nil {
+		log.Logger.Error().Msgf("error finishing job `%v`: %v", job.Id, err)
+	}
+}
@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"app/db"
+	"app/models"
+	"app/worker"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2023, 4, 20, 13, 45, 0, 0, time.UTC)
+
+const testJobType = "test-job"
+
+var RunDueJobsTestCases = []struct {
+	description        string
+	leaseAcquired      bool
+	storedJob          models.Job
+	handlerErr         error
+	expectedHandled    bool
+	expectedFinished   *models.Job
+	expectedReschedule *models.Job
+}{
+	{
+		description:     "Success - job done",
+		leaseAcquired:   true,
+		storedJob:       models.Job{Id: "job-id", Type: testJobType, RunAt: now, Status: StatusPending},
+		expectedHandled: true,
+		expectedFinished: &models.Job{
+			Id: "job-id", Type: testJobType, RunAt: now, Status: StatusDone, Attempts: 1,
+		},
+	},
+	{
+		description:     "Fail - job retried with backoff",
+		leaseAcquired:   true,
+		storedJob:       models.Job{Id: "job-id", Type: testJobType, RunAt: now, Status: StatusPending},
+		handlerErr:      errors.New("any error"),
+		expectedHandled: true,
+		expectedReschedule: &models.Job{
+			Id: "job-id", Type: testJobType, RunAt: now.Add(retryDelay), Status: StatusPending,
+			Attempts: 1, LastError: "any error",
+		},
+	},
+	{
+		description:   "Fail - job failed after last attempt",
+		leaseAcquired: true,
+		storedJob: models.Job{
+			Id: "job-id", Type: testJobType, RunAt: now, Status: StatusPending, Attempts: maxAttempts - 1,
+		},
+		handlerErr:      errors.New("any error"),
+		expectedHandled: true,
+		expectedFinished: &models.Job{
+			Id: "job-id", Type: testJobType, RunAt: now, Status: StatusFailed,
+			Attempts: maxAttempts, LastError: "any error",
+		},
+	},
+	{
+		description:   "Fail - unknown job type",
+		leaseAcquired: true,
+		storedJob:     models.Job{Id: "job-id", Type: "unknown", RunAt: now, Status: StatusPending},
+		expectedFinished: &models.Job{
+			Id: "job-id", Type: "unknown", RunAt: now, Status: StatusFailed,
+			LastError: "no handler registered for job type `unknown`",
+		},
+	},
+	{
+		description:   "Skip - lease held by other replica",
+		leaseAcquired: false,
+		storedJob:     models.Job{Id: "job-id", Type: testJobType, RunAt: now, Status: StatusPending},
+	},
+	{
+		description:   "Skip - job already finished by other replica",
+		leaseAcquired: true,
+		storedJob:     models.Job{Id: "job-id", Type: testJobType, RunAt: now, Status: StatusDone},
+	},
+}
+
+func TestRunDueJobs(t *testing.T) {
+	for _, testCase := range RunDueJobsTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			handled := false
+			var finished, rescheduled *models.Job
+			Register(testJobType, func(c context.Context, job models.Job) error {
+				handled = true
+				return testCase.handlerErr
+			})
+			db.GetDueJobIds = func(c context.Context, dueAt time.Time, limit int64) ([]string, error) {
+				assert.Equal(t, now, dueAt)
+				return []string{testCase.storedJob.Id}, nil
+			}
+			db.AcquireJobLease = func(c context.Context, id string, leaseOwner string, ttl time.Duration) (bool, error) {
+				assert.Equal(t, testCase.storedJob.Id, id)
+				assert.Equal(t, worker.Owner, leaseOwner)
+				return testCase.leaseAcquired, nil
+			}
+			db.ReleaseJobLease = func(c context.Context, id string, owner string) error {
+				return nil
+			}
+			db.GetJob = func(c context.Context, id string) (models.Job, error) {
+				return testCase.storedJob, nil
+			}
+			db.FinishJob = func(c context.Context, job models.Job) error {
+				finished = &job
+				return nil
+			}
+			db.ScheduleJob = func(c context.Context, job models.Job) error {
+				rescheduled = &job
+				return nil
+			}
+
+			RunDueJobs(context.Background(), now)
+
+			assert.Equal(t, testCase.expectedHandled, handled)
+			assert.Equal(t, testCase.expectedFinished, finished)
+			assert.Equal(t, testCase.expectedReschedule, rescheduled)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"app/db"
+	"app/lifecycle"
+	"app/metrics"
+	"app/models"
+	"app/utils"
+	"context"
+	"errors"
+	"time"
+)
+
+const JobTypeTransition = "transition"
+
+func init() {
+	Register(JobTypeTransition, transitionEvent)
+}
+
+// transition points of published events, the event goes live at its start and ends `utils.EVENT_DURATION` later
+const (
+	transitionStart = "start"
+	transitionEnd   = "end"
+)
+
+func transitionJobId(eventId string, point string) string {
+	return JobTypeTransition + "-" + point + "-" + eventId
+}
+
+// ScheduleTransitions schedules jobs storing time based transitions of published `event`, reads of the event
+// resolve its status on their own, so the jobs only make the stored status, its notifications and webhooks follow.
+var ScheduleTransitions = func(c context.Context, event models.EventResponseData) error {
+	startTime, err := time.Parse(utils.TIMESTAMP_LAYOUT, event.Timestamp)
+	if err != nil {
+		return err
+	}
+	runAt := map[string]time.Time{
+		transitionStart: startTime,
+		transitionEnd:   startTime.Add(utils.EVENT_DURATION),
+	}
+	for _, point := range []string{transitionStart, transitionEnd} {
+		err := db.ScheduleJob(c, models.Job{
+			Id:      transitionJobId(event.Id, point),
+			Type:    JobTypeTransition,
+			EventId: event.Id,
+			RunAt:   runAt[point],
+			Status:  StatusPending,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var CancelTransitions = func(c context.Context, eventId string) error {
+	return errors.Join(
+		cancelJob(c, transitionJobId(eventId, transitionStart)),
+		cancelJob(c, transitionJobId(eventId, transitionEnd)),
+	)
+}
+
+// transitionEvent stores status the event reached by time, unless it was changed meanwhile,
+// e.g. cancelled, in which case the job is retried and finds nothing to do.
+func transitionEvent(c context.Context, job models.Job) error {
+	event, err := db.GetEvent(c, job.EventId)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	status := lifecycle.Resolve(event.Status, event.Timestamp, time.Now().UTC())
+	if status == lifecycle.Current(event.Status) {
+		return nil
+	}
+	storedStatus := event.Status
+	event.Status = status
+	if err := db.UpdateEventStatus(c, event.Id, storedStatus, event.EventData); err != nil {
+		return err
+	}
+	metrics.EventTransitions.WithLabelValues(status).Inc()
+	return nil
+}
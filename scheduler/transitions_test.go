@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"app/db"
+	"app/lifecycle"
+	"app/models"
+	"app/utils"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleTransitions(t *testing.T) {
+	startTime := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	scheduled := []models.Job{}
+	db.ScheduleJob = func(c context.Context, job models.Job) error {
+		scheduled = append(scheduled, job)
+		return nil
+	}
+
+	err := ScheduleTransitions(context.Background(), models.EventResponseData{
+		Id:        "event-id",
+		EventData: models.EventData{Timestamp: startTime.Format(utils.TIMESTAMP_LAYOUT)},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []models.Job{
+		{Id: "transition-start-event-id", Type: JobTypeTransition, EventId: "event-id", RunAt: startTime,
+			Status: StatusPending},
+		{Id: "transition-end-event-id", Type: JobTypeTransition, EventId: "event-id",
+			RunAt: startTime.Add(utils.EVENT_DURATION), Status: StatusPending},
+	}, scheduled)
+}
+
+func TestCancelTransitions(t *testing.T) {
+	cancelled := []string{}
+	db.GetJob = func(c context.Context, id string) (models.Job, error) {
+		return models.Job{Id: id, Status: StatusPending}, nil
+	}
+	db.FinishJob = func(c context.Context, job models.Job) error {
+		assert.Equal(t, StatusCancelled, job.Status)
+		cancelled = append(cancelled, job.Id)
+		return nil
+	}
+
+	assert.Nil(t, CancelTransitions(context.Background(), "event-id"))
+	assert.Equal(t, []string{"transition-start-event-id", "transition-end-event-id"}, cancelled)
+}
+
+var TransitionEventTestCases = []struct {
+	description          string
+	storedEvent          models.EventData
+	getEventErr          error
+	updateErr            error
+	expectedStoredStatus string
+	expectedError        bool
+}{
+	{
+		description: "Started event goes live",
+		storedEvent: models.EventData{
+			Status:    lifecycle.Scheduled,
+			Timestamp: time.Now().UTC().Add(-time.Minute).Format(utils.TIMESTAMP_LAYOUT),
+		},
+		expectedStoredStatus: lifecycle.Live,
+	},
+	{
+		description: "Past event ends",
+		storedEvent: models.EventData{
+			Status:    lifecycle.Live,
+			Timestamp: "2023-04-20T14:00:00Z",
+		},
+		expectedStoredStatus: lifecycle.Ended,
+	},
+	{
+		description: "Cancelled event is kept",
+		storedEvent: models.EventData{
+			Status:    lifecycle.Cancelled,
+			Timestamp: "2023-04-20T14:00:00Z",
+		},
+	},
+	{
+		description: "Missing event is ignored",
+		getEventErr: db.ErrNotFound,
+	},
+	{
+		description: "Fail - status changed concurrently is retried",
+		storedEvent: models.EventData{
+			Status:    lifecycle.Scheduled,
+			Timestamp: "2023-04-20T14:00:00Z",
+		},
+		updateErr:            db.ErrConflict,
+		expectedStoredStatus: lifecycle.Ended,
+		expectedError:        true,
+	},
+}
+
+func TestTransitionEvent(t *testing.T) {
+	for _, testCase := range TransitionEventTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			db.GetEvent = func(c context.Context, id string) (models.EventResponseData, error) {
+				return models.EventResponseData{Id: id, EventData: testCase.storedEvent}, testCase.getEventErr
+			}
+			storedStatus := ""
+			db.UpdateEventStatus = func(c context.Context, id string, expectedStatus string, payload models.EventData) error {
+				assert.Equal(t, "event-id", id)
+				assert.Equal(t, testCase.storedEvent.Status, expectedStatus)
+				storedStatus = payload.Status
+				return testCase.updateErr
+			}
+
+			err := transitionEvent(context.Background(), models.Job{EventId: "event-id"})
+
+			assert.Equal(t, testCase.expectedError, err != nil)
+			assert.Equal(t, testCase.expectedStoredStatus, storedStatus)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"app/health"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Server runs HTTP server together with background workers and stops them gracefully on SIGTERM or SIGINT.
+type Server struct {
+	HTTP *http.Server
+	// Workers run until context they are given is done
+	Workers []func(context.Context)
+	// Close releases resources shared by handlers and workers, e.g. Redis client, after both stopped
+	Close func() error
+	// DrainDelay is how long readiness fails before new connections are refused, so load balancers can notice
+	DrainDelay time.Duration
+	// Timeout bounds draining of in-flight requests, and then stopping of workers
+	Timeout time.Duration
+}
+
+// Run serves until `c` is done or a termination signal arrives, then shuts down in order:
+// readiness is failed, in-flight requests are drained, workers are stopped and resources closed.
+// Error is returned if the server could not start or shutdown did not finish in time.
+func (s *Server) Run(c context.Context) error {
+	c, stopSignals := signal.NotifyContext(c, syscall.SIGTERM, os.Interrupt)
+	defer stopSignals()
+
+	workersContext, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	workers := sync.WaitGroup{}
+	for _, worker := range s.Workers {
+		workers.Add(1)
+		go func(worker func(context.Context)) {
+			defer workers.Done()
+			worker(workersContext)
+		}(worker)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Logger.Info().Msgf("listening on %v", s.HTTP.Addr)
+		serveErr <- s.HTTP.ListenAndServe()
+	}()
+
+	var err error
+	select {
+	case err = <-serveErr:
+		log.Logger.Error().Msgf("server failed: %v", err)
+	case <-c.Done():
+		log.Logger.Info().Msg("shutting down")
+	}
+	return errors.Join(err, s.shutdown(stopWorkers, &workers))
+}
+
+func (s *Server) shutdown(stopWorkers context.CancelFunc, workers *sync.WaitGroup) error {
+	health.Drain()
+	time.Sleep(s.DrainDelay)
+
+	c, cancel := context.WithTimeout(context.Background(), s.Timeout)
+	defer cancel()
+	var errs []error
+	if err := s.HTTP.Shutdown(c); err != nil {
+		log.Logger.Error().Msgf("in-flight requests were not drained: %v", err)
+		errs = append(errs, err, s.HTTP.Close())
+	}
+
+	// workers finish their current batch, which may need Redis, so they get time of their own
+	stopWorkers()
+	c, cancel = context.WithTimeout(context.Background(), s.Timeout)
+	defer cancel()
+	stopped := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-c.Done():
+		log.Logger.Error().Msg("background workers did not stop in time")
+		errs = append(errs, errors.New("background workers did not stop in time"))
+	}
+
+	if s.Close != nil {
+		if err := s.Close(); err != nil {
+			log.Logger.Error().Msgf("error closing resources: %v", err)
+			errs = append(errs, err)
+		}
+	}
+	log.Logger.Info().Msg("shutdown finished")
+	return errors.Join(errs...)
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"app/health"
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func freeAddr(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+// recorder collects steps of the shutdown sequence in the order they happen
+type recorder struct {
+	mutex sync.Mutex
+	steps []string
+}
+
+func (r *recorder) record(step string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.steps = append(r.steps, step)
+}
+
+func (r *recorder) get() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]string{}, r.steps...)
+}
+
+func newTestServer(t *testing.T, steps *recorder, handler http.HandlerFunc) *Server {
+	return &Server{
+		HTTP: &http.Server{Addr: freeAddr(t), Handler: handler},
+		Workers: []func(context.Context){func(c context.Context) {
+			<-c.Done()
+			steps.record("worker stopped")
+		}},
+		Close: func() error {
+			steps.record("closed")
+			return nil
+		},
+		Timeout: time.Second,
+	}
+}
+
+func waitUntilListening(t *testing.T, addr string) {
+	assert.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestRun(t *testing.T) {
+	t.Run("In-flight request is drained before workers stop and resources close", func(t *testing.T) {
+		steps := &recorder{}
+		requestStarted := make(chan struct{})
+		releaseRequest := make(chan struct{})
+		srv := newTestServer(t, steps, func(w http.ResponseWriter, r *http.Request) {
+			close(requestStarted)
+			<-releaseRequest
+			steps.record("request finished")
+			w.WriteHeader(http.StatusOK)
+		})
+		c, stop := context.WithCancel(context.Background())
+		runErr := make(chan error, 1)
+		go func() { runErr <- srv.Run(c) }()
+		waitUntilListening(t, srv.HTTP.Addr)
+
+		responseStatus := make(chan int, 1)
+		go func() {
+			res, err := http.Get("http://" + srv.HTTP.Addr)
+			assert.Nil(t, err)
+			responseStatus <- res.StatusCode
+		}()
+		<-requestStarted
+		stop()
+
+		assert.Eventually(t, func() bool {
+			return health.Readiness(context.Background()).Status == health.StatusFailing
+		}, time.Second, 5*time.Millisecond, "should fail readiness")
+		assert.Eventually(t, func() bool {
+			_, err := net.Dial("tcp", srv.HTTP.Addr)
+			return err != nil
+		}, time.Second, 5*time.Millisecond, "should refuse new connections")
+		assert.Empty(t, steps.get(), "should wait for in-flight request")
+
+		close(releaseRequest)
+
+		assert.Equal(t, http.StatusOK, <-responseStatus)
+		assert.Nil(t, <-runErr)
+		assert.Equal(t, []string{"request finished", "worker stopped", "closed"}, steps.get())
+	})
+
+	t.Run("Termination signal starts shutdown", func(t *testing.T) {
+		steps := &recorder{}
+		srv := newTestServer(t, steps, func(w http.ResponseWriter, r *http.Request) {})
+		runErr := make(chan error, 1)
+		go func() { runErr <- srv.Run(context.Background()) }()
+		waitUntilListening(t, srv.HTTP.Addr)
+
+		assert.Nil(t, syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+
+		select {
+		case err := <-runErr:
+			assert.Nil(t, err)
+		case <-time.After(time.Second):
+			assert.Fail(t, "should stop on SIGTERM")
+		}
+		assert.Equal(t, []string{"worker stopped", "closed"}, steps.get())
+	})
+
+	t.Run("Fail - request outlives deadline", func(t *testing.T) {
+		steps := &recorder{}
+		requestStarted := make(chan struct{})
+		releaseRequest := make(chan struct{})
+		defer close(releaseRequest)
+		srv := newTestServer(t, steps, func(w http.ResponseWriter, r *http.Request) {
+			close(requestStarted)
+			<-releaseRequest
+		})
+		srv.Timeout = 50 * time.Millisecond
+		c, stop := context.WithCancel(context.Background())
+		runErr := make(chan error, 1)
+		go func() { runErr <- srv.Run(c) }()
+		waitUntilListening(t, srv.HTTP.Addr)
+		go http.Get("http://" + srv.HTTP.Addr)
+		<-requestStarted
+
+		stop()
+
+		assert.ErrorIs(t, <-runErr, context.DeadlineExceeded)
+		assert.Equal(t, []string{"worker stopped", "closed"}, steps.get(), "should still stop workers and close resources")
+	})
+
+	t.Run("Fail - server cannot start", func(t *testing.T) {
+		steps := &recorder{}
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.Nil(t, err)
+		defer listener.Close()
+		srv := newTestServer(t, steps, func(w http.ResponseWriter, r *http.Request) {})
+		srv.HTTP.Addr = listener.Addr().String()
+
+		err = srv.Run(context.Background())
+
+		assert.ErrorContains(t, err, "address already in use")
+		assert.Equal(t, []string{"worker stopped", "closed"}, steps.get())
+	})
+}
@@ -0,0 +1,266 @@
+package streaming
+
+import (
+	"app/catalog"
+	"app/config"
+	"app/models"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+var streams = config.Default().Streams
+
+// Configure sets URL templates manifests point players to.
+func Configure(settings config.Streams) {
+	streams = settings
+}
+
+// ErrNoRenditions is returned when none of the event video qualities is in the quality catalog anymore
+var ErrNoRenditions = errors.New("no renditions in the quality catalog")
+
+// segmentSeconds is the segment duration packagers are expected to use in latency mode
+var segmentSeconds = map[string]int{
+	models.LatencyStandard: 6,
+	models.LatencyLow:      2,
+	models.LatencyUltraLow: 1,
+}
+
+// subtitleBandwidth is declared for sidecar subtitle files, MPD requires bandwidth of every representation
+const subtitleBandwidth = 256
+
+var subtitleMimeTypes = map[string]string{
+	models.SubtitleWebVTT: "text/vtt",
+	models.SubtitleTTML:   "application/ttml+xml",
+}
+
+func streamURL(template string, eventId string, quality string, language string, format string) string {
+	return strings.NewReplacer(
+		"{eventId}", url.PathEscape(eventId),
+		"{quality}", url.PathEscape(quality),
+		"{language}", url.PathEscape(language),
+		"{format}", url.PathEscape(format),
+	).Replace(template)
+}
+
+// profiles returns catalog profiles of `names` in their order, skipping ones removed from the catalog
+func profiles(kind string, names []string) []models.QualityProfile {
+	found := []models.QualityProfile{}
+	for _, name := range names {
+		if profile, ok := catalog.Profile(kind, name); ok {
+			found = append(found, profile)
+		}
+	}
+	return found
+}
+
+// renditions returns video profiles, default rendition first, and audio profiles of resolved `event`
+func renditions(event *models.EventResponseData) ([]models.QualityProfile, []models.QualityProfile, error) {
+	Resolve(&event.EventData)
+	video := profiles(models.QualityVideo, event.VideoQuality)
+	if len(video) == 0 {
+		return nil, nil, ErrNoRenditions
+	}
+	return video, profiles(models.QualityAudio, event.AudioQuality), nil
+}
+
+// quoted makes `value` safe to be an HLS quoted-string attribute
+func quoted(value string) string {
+	return `"` + strings.NewReplacer(`"`, "'", "\r", "", "\n", "").Replace(value) + `"`
+}
+
+// HLSMaster returns HLS master playlist of `event`, a variant for every pair of video and audio quality.
+// Every audio quality is a rendition group with one track per language which offers it.
+func HLSMaster(event models.EventResponseData) (string, error) {
+	video, audio, err := renditions(&event)
+	if err != nil {
+		return "", err
+	}
+	config := event.Streaming
+	var playlist strings.Builder
+	playlist.WriteString("#EXTM3U\n#EXT-X-VERSION:6\n#EXT-X-INDEPENDENT-SEGMENTS\n")
+	for _, profile := range audio {
+		isDefault := "YES"
+		for _, track := range config.AudioTracks {
+			if !slices.Contains(track.Qualities, profile.Name) {
+				continue
+			}
+			fmt.Fprintf(&playlist,
+				"#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID=%s,LANGUAGE=%s,NAME=%s,DEFAULT=%s,AUTOSELECT=YES,CHANNELS=\"%d\",URI=%s\n",
+				quoted("audio-"+profile.Name), quoted(track.Language), quoted(track.Language), isDefault, profile.Channels,
+				quoted(streamURL(streams.HLSAudioURL, event.Id, profile.Name, track.Language, "")))
+			isDefault = "NO"
+		}
+	}
+	for _, subtitle := range config.Subtitles {
+		fmt.Fprintf(&playlist,
+			"#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID=\"subtitles\",LANGUAGE=%s,NAME=%s,DEFAULT=NO,AUTOSELECT=YES,URI=%s\n",
+			quoted(subtitle.Language), quoted(subtitle.Language),
+			quoted(streamURL(streams.HLSSubtitleURL, event.Id, "", subtitle.Language, subtitle.Format)))
+	}
+	subtitles := ""
+	if len(config.Subtitles) > 0 {
+		subtitles = `,SUBTITLES="subtitles"`
+	}
+	for _, videoProfile := range video {
+		variantURL := streamURL(streams.HLSVideoURL, event.Id, videoProfile.Name, "", "")
+		if len(audio) == 0 {
+			fmt.Fprintf(&playlist, "#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d,CODECS=%s%s\n%s\n",
+				videoProfile.BitrateKbps*1000, videoProfile.Width, videoProfile.Height, quoted(videoProfile.Codec),
+				subtitles, variantURL)
+			continue
+		}
+		for _, audioProfile := range audio {
+			fmt.Fprintf(&playlist, "#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d,CODECS=%s,AUDIO=%s%s\n%s\n",
+				(videoProfile.BitrateKbps+audioProfile.BitrateKbps)*1000, videoProfile.Width, videoProfile.Height,
+				quoted(videoProfile.Codec+","+audioProfile.Codec), quoted("audio-"+audioProfile.Name), subtitles,
+				variantURL)
+		}
+	}
+	return playlist.String(), nil
+}
+
+type mpd struct {
+	XMLName                    xml.Name  `xml:"MPD"`
+	Xmlns                      string    `xml:"xmlns,attr"`
+	Profiles                   string    `xml:"profiles,attr"`
+	Type                       string    `xml:"type,attr"`
+	AvailabilityStartTime      string    `xml:"availabilityStartTime,attr,omitempty"`
+	MinimumUpdatePeriod        string    `xml:"minimumUpdatePeriod,attr"`
+	MinBufferTime              string    `xml:"minBufferTime,attr"`
+	SuggestedPresentationDelay string    `xml:"suggestedPresentationDelay,attr"`
+	Period                     mpdPeriod `xml:"Period"`
+}
+
+type mpdPeriod struct {
+	Id             string             `xml:"id,attr"`
+	Start          string             `xml:"start,attr"`
+	AdaptationSets []mpdAdaptationSet `xml:"AdaptationSet"`
+}
+
+type mpdAdaptationSet struct {
+	Id               int                 `xml:"id,attr"`
+	ContentType      string              `xml:"contentType,attr"`
+	MimeType         string              `xml:"mimeType,attr"`
+	Lang             string              `xml:"lang,attr,omitempty"`
+	SegmentAlignment bool                `xml:"segmentAlignment,attr,omitempty"`
+	Role             *mpdDescriptor      `xml:"Role,omitempty"`
+	SegmentTemplate  *mpdSegmentTemplate `xml:"SegmentTemplate,omitempty"`
+	Representations  []mpdRepresentation `xml:"Representation"`
+}
+
+type mpdDescriptor struct {
+	SchemeIdUri string `xml:"schemeIdUri,attr"`
+	Value       string `xml:"value,attr"`
+}
+
+type mpdSegmentTemplate struct {
+	Timescale      int    `xml:"timescale,attr"`
+	Duration       int    `xml:"duration,attr"`
+	StartNumber    int    `xml:"startNumber,attr"`
+	Media          string `xml:"media,attr"`
+	Initialization string `xml:"initialization,attr"`
+}
+
+type mpdRepresentation struct {
+	Id                        string         `xml:"id,attr"`
+	Bandwidth                 int            `xml:"bandwidth,attr"`
+	Codecs                    string         `xml:"codecs,attr,omitempty"`
+	Width                     int            `xml:"width,attr,omitempty"`
+	Height                    int            `xml:"height,attr,omitempty"`
+	AudioSamplingRate         int            `xml:"audioSamplingRate,attr,omitempty"`
+	AudioChannelConfiguration *mpdDescriptor `xml:"AudioChannelConfiguration,omitempty"`
+	BaseURL                   string         `xml:"BaseURL"`
+}
+
+// DASHManifest returns live MPD of `event`, an adaptation set of video qualities, one per audio track
+// language and one per subtitle track. Segment duration follows latency mode of the event.
+func DASHManifest(event models.EventResponseData) (string, error) {
+	video, audio, err := renditions(&event)
+	if err != nil {
+		return "", err
+	}
+	config := event.Streaming
+	segment := segmentSeconds[config.LatencyMode]
+	segmentTemplate := &mpdSegmentTemplate{
+		Timescale:      1000,
+		Duration:       segment * 1000,
+		StartNumber:    1,
+		Media:          "$Number$.m4s",
+		Initialization: "init.mp4",
+	}
+
+	videoSet := mpdAdaptationSet{
+		Id: 0, ContentType: "video", MimeType: "video/mp4", SegmentAlignment: true, SegmentTemplate: segmentTemplate,
+	}
+	for _, profile := range video {
+		videoSet.Representations = append(videoSet.Representations, mpdRepresentation{
+			Id:        "video-" + profile.Name,
+			Bandwidth: profile.BitrateKbps * 1000,
+			Codecs:    profile.Codec,
+			Width:     profile.Width,
+			Height:    profile.Height,
+			BaseURL:   streamURL(streams.DASHVideoURL, event.Id, profile.Name, "", ""),
+		})
+	}
+	sets := []mpdAdaptationSet{videoSet}
+
+	for i, track := range config.AudioTracks {
+		audioSet := mpdAdaptationSet{
+			Id: len(sets), ContentType: "audio", MimeType: "audio/mp4", Lang: track.Language, SegmentAlignment: true,
+			SegmentTemplate: segmentTemplate,
+		}
+		if i == 0 {
+			audioSet.Role = &mpdDescriptor{SchemeIdUri: "urn:mpeg:dash:role:2011", Value: "main"}
+		}
+		for _, profile := range audio {
+			if !slices.Contains(track.Qualities, profile.Name) {
+				continue
+			}
+			audioSet.Representations = append(audioSet.Representations, mpdRepresentation{
+				Id:                fmt.Sprintf("audio-%d-%s", i, profile.Name),
+				Bandwidth:         profile.BitrateKbps * 1000,
+				Codecs:            profile.Codec,
+				AudioSamplingRate: profile.SampleRateHz,
+				AudioChannelConfiguration: &mpdDescriptor{
+					SchemeIdUri: "urn:mpeg:dash:23003:3:audio_channel_configuration:2011",
+					Value:       fmt.Sprint(profile.Channels),
+				},
+				BaseURL: streamURL(streams.DASHAudioURL, event.Id, profile.Name, track.Language, ""),
+			})
+		}
+		if len(audioSet.Representations) > 0 {
+			sets = append(sets, audioSet)
+		}
+	}
+
+	for i, subtitle := range config.Subtitles {
+		sets = append(sets, mpdAdaptationSet{
+			Id: len(sets), ContentType: "text", MimeType: subtitleMimeTypes[subtitle.Format], Lang: subtitle.Language,
+			Representations: []mpdRepresentation{{
+				Id:        fmt.Sprintf("subtitles-%d", i),
+				Bandwidth: subtitleBandwidth,
+				BaseURL:   streamURL(streams.DASHSubtitleURL, event.Id, "", subtitle.Language, subtitle.Format),
+			}},
+		})
+	}
+
+	manifest, err := xml.MarshalIndent(mpd{
+		Xmlns:                      "urn:mpeg:dash:schema:mpd:2011",
+		Profiles:                   "urn:mpeg:dash:profile:isoff-live:2011",
+		Type:                       "dynamic",
+		AvailabilityStartTime:      event.Timestamp,
+		MinimumUpdatePeriod:        fmt.Sprintf("PT%dS", segment),
+		MinBufferTime:              fmt.Sprintf("PT%dS", segment),
+		SuggestedPresentationDelay: fmt.Sprintf("PT%dS", 3*segment),
+		Period:                     mpdPeriod{Id: "0", Start: "PT0S", AdaptationSets: sets},
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(manifest) + "\n", nil
+}
@@ -0,0 +1,141 @@
+package streaming
+
+import (
+	"app/catalog"
+	"app/config"
+	"app/models"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var manifestEvent = models.EventResponseData{
+	Id: "90a04b08-d820-4106-8ced-2cbc940728a3",
+	EventData: models.EventData{
+		Name:      "event-name",
+		Timestamp: "2023-04-20T14:00:00Z",
+		Languages: []string{"English", "French"},
+		Streaming: &models.StreamingConfig{
+			DefaultRendition: "1080p",
+			Ladder:           []string{"720p", "1080p"},
+			AudioTracks: []models.AudioTrack{
+				{Language: "English", Qualities: []string{"Low", "Mid"}},
+				{Language: "French", Qualities: []string{"Mid"}},
+			},
+			Subtitles:   []models.SubtitleTrack{{Language: "German"}},
+			LatencyMode: models.LatencyLow,
+		},
+	},
+}
+
+func TestHLSMaster(t *testing.T) {
+	playlist, err := HLSMaster(manifestEvent)
+	assert.Nil(t, err)
+	assert.Equal(t, `#EXTM3U
+#EXT-X-VERSION:6
+#EXT-X-INDEPENDENT-SEGMENTS
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="audio-Low",LANGUAGE="English",NAME="English",DEFAULT=YES,AUTOSELECT=YES,CHANNELS="2",URI="http://localhost:8080/live/90a04b08-d820-4106-8ced-2cbc940728a3/hls/audio/English/Low.m3u8"
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="audio-Mid",LANGUAGE="English",NAME="English",DEFAULT=YES,AUTOSELECT=YES,CHANNELS="2",URI="http://localhost:8080/live/90a04b08-d820-4106-8ced-2cbc940728a3/hls/audio/English/Mid.m3u8"
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="audio-Mid",LANGUAGE="French",NAME="French",DEFAULT=NO,AUTOSELECT=YES,CHANNELS="2",URI="http://localhost:8080/live/90a04b08-d820-4106-8ced-2cbc940728a3/hls/audio/French/Mid.m3u8"
+#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="subtitles",LANGUAGE="German",NAME="German",DEFAULT=NO,AUTOSELECT=YES,URI="http://localhost:8080/live/90a04b08-d820-4106-8ced-2cbc940728a3/hls/subtitles/webvtt/German.m3u8"
+#EXT-X-STREAM-INF:BANDWIDTH=6064000,RESOLUTION=1920x1080,CODECS="avc1.640028,mp4a.40.2",AUDIO="audio-Low",SUBTITLES="subtitles"
+http://localhost:8080/live/90a04b08-d820-4106-8ced-2cbc940728a3/hls/video/1080p.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=6128000,RESOLUTION=1920x1080,CODECS="avc1.640028,mp4a.40.2",AUDIO="audio-Mid",SUBTITLES="subtitles"
+http://localhost:8080/live/90a04b08-d820-4106-8ced-2cbc940728a3/hls/video/1080p.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=3064000,RESOLUTION=1280x720,CODECS="avc1.64001f,mp4a.40.2",AUDIO="audio-Low",SUBTITLES="subtitles"
+http://localhost:8080/live/90a04b08-d820-4106-8ced-2cbc940728a3/hls/video/720p.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=3128000,RESOLUTION=1280x720,CODECS="avc1.64001f,mp4a.40.2",AUDIO="audio-Mid",SUBTITLES="subtitles"
+http://localhost:8080/live/90a04b08-d820-4106-8ced-2cbc940728a3/hls/video/720p.m3u8
+`, playlist)
+}
+
+func TestHLSMasterWithoutAudio(t *testing.T) {
+	playlist, err := HLSMaster(models.EventResponseData{
+		Id:        "event-id",
+		EventData: models.EventData{VideoQuality: []string{"720p"}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, `#EXTM3U
+#EXT-X-VERSION:6
+#EXT-X-INDEPENDENT-SEGMENTS
+#EXT-X-STREAM-INF:BANDWIDTH=3000000,RESOLUTION=1280x720,CODECS="avc1.64001f"
+http://localhost:8080/live/event-id/hls/video/720p.m3u8
+`, playlist)
+}
+
+func TestDASHManifest(t *testing.T) {
+	manifest, err := DASHManifest(manifestEvent)
+	assert.Nil(t, err)
+
+	parsed := mpd{}
+	assert.Nil(t, xml.Unmarshal([]byte(manifest), &parsed))
+	assert.Equal(t, "dynamic", parsed.Type)
+	assert.Equal(t, "2023-04-20T14:00:00Z", parsed.AvailabilityStartTime)
+	assert.Equal(t, "PT2S", parsed.MinBufferTime)
+
+	sets := parsed.Period.AdaptationSets
+	assert.Len(t, sets, 4)
+	assert.Equal(t, "video", sets[0].ContentType)
+	assert.Equal(t, 2000, sets[0].SegmentTemplate.Duration)
+	assert.Equal(t, []mpdRepresentation{
+		{
+			Id: "video-1080p", Bandwidth: 6000000, Codecs: "avc1.640028", Width: 1920, Height: 1080,
+			BaseURL: "http://localhost:8080/live/90a04b08-d820-4106-8ced-2cbc940728a3/dash/video/1080p/",
+		},
+		{
+			Id: "video-720p", Bandwidth: 3000000, Codecs: "avc1.64001f", Width: 1280, Height: 720,
+			BaseURL: "http://localhost:8080/live/90a04b08-d820-4106-8ced-2cbc940728a3/dash/video/720p/",
+		},
+	}, sets[0].Representations)
+
+	assert.Equal(t, "English", sets[1].Lang)
+	assert.Equal(t, "main", sets[1].Role.Value)
+	assert.Len(t, sets[1].Representations, 2)
+	assert.Equal(t, "French", sets[2].Lang)
+	assert.Nil(t, sets[2].Role)
+	assert.Len(t, sets[2].Representations, 1)
+	assert.Equal(t, 128000, sets[2].Representations[0].Bandwidth)
+
+	assert.Equal(t, "text", sets[3].ContentType)
+	assert.Equal(t, "text/vtt", sets[3].MimeType)
+	assert.Equal(t, "http://localhost:8080/live/90a04b08-d820-4106-8ced-2cbc940728a3/dash/subtitles/webvtt/German",
+		sets[3].Representations[0].BaseURL)
+}
+
+func TestDASHManifestSegmentDuration(t *testing.T) {
+	for latency, seconds := range segmentSeconds {
+		event := manifestEvent
+		streaming := *event.Streaming
+		streaming.LatencyMode = latency
+		event.Streaming = &streaming
+
+		manifest, err := DASHManifest(event)
+		assert.Nil(t, err)
+		parsed := mpd{}
+		assert.Nil(t, xml.Unmarshal([]byte(manifest), &parsed))
+		assert.Equal(t, seconds*1000, parsed.Period.AdaptationSets[0].SegmentTemplate.Duration, latency)
+	}
+}
+
+func TestManifestsWithoutRenditions(t *testing.T) {
+	catalog.Set(catalog.Profiles(models.QualityAudio), map[string]models.TenantQualities{})
+	defer catalog.Set(catalog.DefaultProfiles, map[string]models.TenantQualities{})
+
+	_, err := HLSMaster(manifestEvent)
+	assert.ErrorIs(t, err, ErrNoRenditions)
+	_, err = DASHManifest(manifestEvent)
+	assert.ErrorIs(t, err, ErrNoRenditions)
+}
+
+func TestConfigure(t *testing.T) {
+	Configure(config.Streams{HLSVideoURL: "https://cdn.example.com/{eventId}/{quality}/index.m3u8"})
+	defer Configure(config.Default().Streams)
+
+	playlist, err := HLSMaster(models.EventResponseData{
+		Id:        "event id",
+		EventData: models.EventData{VideoQuality: []string{"720p"}},
+	})
+	assert.Nil(t, err)
+	assert.Contains(t, playlist, "\nhttps://cdn.example.com/event%20id/720p/index.m3u8\n")
+}
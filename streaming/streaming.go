@@ -0,0 +1,59 @@
+package streaming
+
+import (
+	"app/models"
+
+	"golang.org/x/exp/slices"
+)
+
+// Resolve makes streaming config of `event` and its quality lists describe the same renditions.
+// Events without streaming config, e.g. ones created before it existed, get one derived from the lists,
+// otherwise the lists are derived from it, the default rendition being the first video quality.
+func Resolve(event *models.EventData) {
+	if event.Streaming == nil {
+		event.Streaming = &models.StreamingConfig{
+			Ladder:    append([]string{}, event.VideoQuality...),
+			Subtitles: []models.SubtitleTrack{},
+		}
+		if len(event.VideoQuality) > 0 {
+			event.Streaming.DefaultRendition = event.VideoQuality[0]
+		}
+	}
+	config := event.Streaming
+	if config.LatencyMode == "" {
+		config.LatencyMode = models.LatencyStandard
+	}
+	if len(config.AudioTracks) == 0 {
+		config.AudioTracks = []models.AudioTrack{}
+		for _, language := range event.Languages {
+			config.AudioTracks = append(config.AudioTracks,
+				models.AudioTrack{Language: language, Qualities: append([]string{}, event.AudioQuality...)})
+		}
+	}
+	if config.Subtitles == nil {
+		config.Subtitles = []models.SubtitleTrack{}
+	}
+	for i := range config.Subtitles {
+		if config.Subtitles[i].Format == "" {
+			config.Subtitles[i].Format = models.SubtitleWebVTT
+		}
+	}
+
+	event.VideoQuality = []string{}
+	if config.DefaultRendition != "" {
+		event.VideoQuality = append(event.VideoQuality, config.DefaultRendition)
+	}
+	for _, quality := range config.Ladder {
+		if quality != config.DefaultRendition {
+			event.VideoQuality = append(event.VideoQuality, quality)
+		}
+	}
+	event.AudioQuality = []string{}
+	for _, track := range config.AudioTracks {
+		for _, quality := range track.Qualities {
+			if !slices.Contains(event.AudioQuality, quality) {
+				event.AudioQuality = append(event.AudioQuality, quality)
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package streaming
+
+import (
+	"app/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ResolveTestCases = []struct {
+	description string
+	event       models.EventData
+	expected    models.EventData
+}{
+	{
+		description: "Config derived from quality lists",
+		event: models.EventData{
+			Languages:    []string{"English", "French"},
+			VideoQuality: []string{"1080p", "720p"},
+			AudioQuality: []string{"Mid"},
+		},
+		expected: models.EventData{
+			Languages:    []string{"English", "French"},
+			VideoQuality: []string{"1080p", "720p"},
+			AudioQuality: []string{"Mid"},
+			Streaming: &models.StreamingConfig{
+				DefaultRendition: "1080p",
+				Ladder:           []string{"1080p", "720p"},
+				AudioTracks: []models.AudioTrack{
+					{Language: "English", Qualities: []string{"Mid"}},
+					{Language: "French", Qualities: []string{"Mid"}},
+				},
+				Subtitles:   []models.SubtitleTrack{},
+				LatencyMode: models.LatencyStandard,
+			},
+		},
+	},
+	{
+		description: "Quality lists derived from config",
+		event: models.EventData{
+			Languages:    []string{"English", "French"},
+			VideoQuality: []string{"2160p"},
+			AudioQuality: []string{"Low"},
+			Streaming: &models.StreamingConfig{
+				DefaultRendition: "1080p",
+				Ladder:           []string{"720p", "1080p", "1440p"},
+				AudioTracks: []models.AudioTrack{
+					{Language: "French", Qualities: []string{"Mid", "High"}},
+					{Language: "English", Qualities: []string{"High", "Low"}},
+				},
+				Subtitles:   []models.SubtitleTrack{{Language: "German"}, {Language: "English", Format: models.SubtitleTTML}},
+				LatencyMode: models.LatencyLow,
+			},
+		},
+		expected: models.EventData{
+			Languages:    []string{"English", "French"},
+			VideoQuality: []string{"1080p", "720p", "1440p"},
+			AudioQuality: []string{"Mid", "High", "Low"},
+			Streaming: &models.StreamingConfig{
+				DefaultRendition: "1080p",
+				Ladder:           []string{"720p", "1080p", "1440p"},
+				AudioTracks: []models.AudioTrack{
+					{Language: "French", Qualities: []string{"Mid", "High"}},
+					{Language: "English", Qualities: []string{"High", "Low"}},
+				},
+				Subtitles: []models.SubtitleTrack{
+					{Language: "German", Format: models.SubtitleWebVTT},
+					{Language: "English", Format: models.SubtitleTTML},
+				},
+				LatencyMode: models.LatencyLow,
+			},
+		},
+	},
+	{
+		description: "Config without audio tracks gets one per language",
+		event: models.EventData{
+			Languages:    []string{"English"},
+			AudioQuality: []string{"Low"},
+			Streaming:    &models.StreamingConfig{DefaultRendition: "720p", Ladder: []string{"720p"}},
+		},
+		expected: models.EventData{
+			Languages:    []string{"English"},
+			VideoQuality: []string{"720p"},
+			AudioQuality: []string{"Low"},
+			Streaming: &models.StreamingConfig{
+				DefaultRendition: "720p",
+				Ladder:           []string{"720p"},
+				AudioTracks:      []models.AudioTrack{{Language: "English", Qualities: []string{"Low"}}},
+				Subtitles:        []models.SubtitleTrack{},
+				LatencyMode:      models.LatencyStandard,
+			},
+		},
+	},
+}
+
+func TestResolve(t *testing.T) {
+	for _, testCase := range ResolveTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			event := testCase.event
+			Resolve(&event)
+			assert.Equal(t, testCase.expected, event)
+		})
+	}
+}
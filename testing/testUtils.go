@@ -1,13 +1,20 @@
 package testing
 
 import (
+	"app/logging"
 	"net/http"
 	"testing"
 
 	"github.com/gavv/httpexpect"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
+// CorrelationId is sent with every request of test client
+const CorrelationId = "test-correlation-id"
+
 func GetTestClient(testSuite *testing.T, appEngine *gin.Engine) *httpexpect.Expect {
 	e := httpexpect.WithConfig(httpexpect.Config{
 		Client: &http.Client{
@@ -19,5 +26,14 @@ func GetTestClient(testSuite *testing.T, appEngine *gin.Engine) *httpexpect.Expe
 			httpexpect.NewDebugPrinter(testSuite, true),
 		},
 	})
-	return e
+	return e.Builder(func(request *httpexpect.Request) {
+		request.WithHeader(logging.CorrelationIdHeader, CorrelationId)
+	})
+}
+
+// UseInMemoryExporter records spans synchronously in memory.
+func UseInMemoryExporter() *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	return exporter
 }
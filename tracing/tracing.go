@@ -0,0 +1,106 @@
+package tracing
+
+import (
+	"app/config"
+	"app/utils"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// W3C `traceparent` & `tracestate` are used for incoming and outgoing requests
+var propagator = propagation.TraceContext{}
+
+func init() {
+	otel.SetTextMapPropagator(propagator)
+}
+
+// Tracer returns tracer of the global provider, so spans go to whichever exporter is set up at the time.
+func Tracer() trace.Tracer {
+	return otel.Tracer(utils.APP_NAME)
+}
+
+// Init sets up exporter selected by `settings` (none, otlp or stdout), spans are tagged with `build` version.
+// OTLP exporter is configured by standard OTEL_EXPORTER_OTLP_* variables.
+// Returned function flushes pending spans.
+func Init(c context.Context, settings config.Tracing, build config.Build) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch name := settings.Exporter; name {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(c)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return nil, fmt.Errorf("unknown traces exporter `%v`", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName(utils.APP_NAME),
+			semconv.ServiceVersion(build.CommitTag),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// InjectHeaders adds `traceparent` of the span in `c` to outgoing request headers.
+func InjectHeaders(c context.Context, header http.Header) {
+	propagator.Inject(c, propagation.HeaderCarrier(header))
+}
+
+// Middleware continues trace from `traceparent` request header and wraps the handler in a span,
+// span is available through `Request.Context()` (and gin context with ContextWithFallback).
+func Middleware() gin.HandlerFunc {
+	return func(gctx *gin.Context) {
+		c := propagator.Extract(gctx.Request.Context(), propagation.HeaderCarrier(gctx.Request.Header))
+		route := gctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		c, span := Tracer().Start(c, gctx.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPMethod(gctx.Request.Method),
+				semconv.HTTPRoute(route),
+				semconv.HTTPTarget(gctx.Request.URL.Path),
+			))
+		defer span.End()
+		gctx.Request = gctx.Request.WithContext(c)
+
+		gctx.Next()
+
+		status := gctx.Writer.Status()
+		span.SetAttributes(semconv.HTTPStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		for _, err := range gctx.Errors {
+			span.RecordError(err.Err)
+		}
+	}
+}
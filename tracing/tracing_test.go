@@ -0,0 +1,92 @@
+package tracing
+
+import (
+	"app/config"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	testFuncs "app/testing"
+)
+
+const incomingTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestMiddleware(t *testing.T) {
+	spans := testFuncs.UseInMemoryExporter()
+	app := gin.New()
+	app.Use(Middleware())
+	var handlerSpan trace.SpanContext
+	app.GET("/event/:id", func(ctx *gin.Context) {
+		handlerSpan = trace.SpanContextFromContext(ctx.Request.Context())
+		ctx.Status(http.StatusOK)
+	})
+	app.GET("/failure", func(ctx *gin.Context) {
+		ctx.Error(errors.New("redis connection error"))
+		ctx.Status(http.StatusInternalServerError)
+	})
+
+	t.Run("Continues incoming trace in span named by route template", func(t *testing.T) {
+		spans.Reset()
+		request := httptest.NewRequest(http.MethodGet, "/event/90a04b08-d820-4106-8ced-2cbc940728a3", nil)
+		request.Header.Set("traceparent", incomingTraceparent)
+		app.ServeHTTP(httptest.NewRecorder(), request)
+
+		assert.Len(t, spans.GetSpans(), 1)
+		span := spans.GetSpans()[0]
+		assert.Equal(t, "GET /event/:id", span.Name)
+		assert.Equal(t, trace.SpanKindServer, span.SpanKind)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext.TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", span.Parent.SpanID().String())
+		assert.Equal(t, span.SpanContext, handlerSpan, "handler should see its span in request context")
+	})
+
+	t.Run("Starts new trace without traceparent", func(t *testing.T) {
+		spans.Reset()
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/event/id", nil))
+
+		assert.Len(t, spans.GetSpans(), 1)
+		assert.False(t, spans.GetSpans()[0].Parent.IsValid())
+	})
+
+	t.Run("Server errors mark span as failed", func(t *testing.T) {
+		spans.Reset()
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/failure", nil))
+
+		span := spans.GetSpans()[0]
+		assert.Equal(t, codes.Error, span.Status.Code)
+		assert.Len(t, span.Events, 1, "should record context errors")
+	})
+}
+
+func TestInjectHeaders(t *testing.T) {
+	spans := testFuncs.UseInMemoryExporter()
+	c, span := Tracer().Start(context.Background(), "outgoing")
+	span.End()
+	header := http.Header{}
+
+	InjectHeaders(c, header)
+
+	assert.Equal(t, "00-"+spans.GetSpans()[0].SpanContext.TraceID().String()+"-"+
+		spans.GetSpans()[0].SpanContext.SpanID().String()+"-01", header.Get("traceparent"))
+}
+
+func TestInit(t *testing.T) {
+	for _, exporter := range []string{ExporterNone, ExporterStdout} {
+		t.Run("Exporter "+exporter, func(t *testing.T) {
+			shutdown, err := Init(context.Background(), config.Tracing{Exporter: exporter}, config.Build{CommitTag: "v1"})
+			assert.Nil(t, err)
+			assert.Nil(t, shutdown(context.Background()))
+		})
+	}
+	t.Run("Unknown exporter", func(t *testing.T) {
+		_, err := Init(context.Background(), config.Tracing{Exporter: "zipkin"}, config.Build{})
+		assert.EqualError(t, err, "unknown traces exporter `zipkin`")
+	})
+}
@@ -1,8 +1,30 @@
 package utils
 
-var DEFAULT_RESOLUTION string = "720p"
-var ALLOWED_RESOLUTION = []string{"720p", "1080p", "1440p", "2160p"}
-var DEVAULT_AUDIO string = "Low"
-var ALLOWED_AUDIO = []string{"Low", "Mid", "High"}
+import (
+	"app/config"
+	"sync/atomic"
+	"time"
+)
+
 var APP_NAME string = "event_handler"
 var API_AUTH_HEADER_KEY = "API-AUTHENTICATION"
+var TIMESTAMP_LAYOUT = "2006-01-02T15:04:05Z"
+var EVENT_DURATION = 2 * time.Hour
+
+// qualities are replaced as a whole on configuration reload, so readers never see default video of one
+// configuration with default audio of another
+var qualities atomic.Pointer[config.Qualities]
+
+func init() {
+	SetQualities(config.Default().Qualities)
+}
+
+// Qualities returns default qualities of event streams.
+func Qualities() config.Qualities {
+	return *qualities.Load()
+}
+
+// SetQualities replaces default qualities, `settings` are validated already.
+func SetQualities(settings config.Qualities) {
+	qualities.Store(&settings)
+}
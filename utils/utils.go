@@ -5,6 +5,7 @@ import (
 	"os"
 	"regexp"
 
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
 	"github.com/gin-gonic/gin"
@@ -20,9 +21,25 @@ func GetEnvOrDefault(key, fallback string) string {
 	return value
 }
 
+// AppendContextError attaches `err` to be reported to client as public error of the request.
 var AppendContextError = func(context *gin.Context, err error) {
-	parsedErr := context.Error(err)
-	log.Logger.Info().Err(parsedErr).Msg("appended context error")
+	parsedErr := context.Error(err).SetType(gin.ErrorTypePublic)
+	requestLogger(context).Info().Err(parsedErr).Msg("appended context error")
+}
+
+// requestLogger returns logger the logging middleware attached to the request, global logger if there is none.
+func requestLogger(context *gin.Context) *zerolog.Logger {
+	if context.Request != nil {
+		if logger := zerolog.Ctx(context.Request.Context()); logger.GetLevel() != zerolog.Disabled {
+			return logger
+		}
+	}
+	return &log.Logger
+}
+
+// AppendPrivateContextError attaches `err` to be logged with the request only, it does not change the response.
+var AppendPrivateContextError = func(context *gin.Context, err error) {
+	_ = context.Error(err).SetType(gin.ErrorTypePrivate)
 }
 
 var GetJsonStringFromStruct = func(data interface{}) (string, error) {
@@ -1,11 +1,14 @@
 package utils
 
 import (
+	"app/config"
+	"bytes"
 	"errors"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,6 +18,27 @@ func TestAppendContextError(t *testing.T) {
 		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
 		AppendContextError(ctx, err)
 		assert.Equal(t, ctx.Errors[0].Err, err)
+		assert.True(t, ctx.Errors[0].IsType(gin.ErrorTypePublic))
+	})
+	t.Run("Check if error is logged with logger of the request", func(t *testing.T) {
+		var output bytes.Buffer
+		requestLogger := zerolog.New(&output).With().Str("correlation_id", "request-1").Logger()
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest("GET", "/", nil)
+		ctx.Request = ctx.Request.WithContext(requestLogger.WithContext(ctx.Request.Context()))
+		AppendContextError(ctx, errors.New("any error"))
+		assert.Contains(t, output.String(), `"correlation_id":"request-1"`)
+		assert.Contains(t, output.String(), "any error")
+	})
+}
+
+func TestAppendPrivateContextError(t *testing.T) {
+	t.Run("Check if error is appended to context as private", func(t *testing.T) {
+		err := errors.New("any error")
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		AppendPrivateContextError(ctx, err)
+		assert.Equal(t, ctx.Errors[0].Err, err)
+		assert.Equal(t, gin.ErrorTypePrivate, ctx.Errors[0].Type)
 	})
 }
 
@@ -33,3 +57,15 @@ func TestGetJsonStringFromStruct(t *testing.T) {
 		assert.Equal(t, jsonString, `{"FieldA":"a","FieldB":1,"FieldC":[2,3]}`)
 	})
 }
+
+func TestSetQualities(t *testing.T) {
+	defer SetQualities(config.Default().Qualities)
+	qualities := config.Qualities{
+		DefaultResolution: "1080p",
+		DefaultAudio:      "High",
+	}
+
+	SetQualities(qualities)
+
+	assert.Equal(t, qualities, Qualities())
+}
@@ -1,6 +1,10 @@
 package validations
 
 import (
+	"app/models"
+	"reflect"
+	"strings"
+
 	"github.com/rs/zerolog/log"
 
 	"github.com/gin-gonic/gin/binding"
@@ -13,12 +17,28 @@ var BindCustomValidators = func() {
 		Function    validator.Func
 	}{
 		{"checkEventName", CheckEventNameValid},
-		{"checkVideoQuality", CheckVideoQuality},
-		{"checkAudioQuality", CheckAudioQuality},
 		{"checkEmail", CheckEmailValid},
 		{"checkTimeFieldFormat", CheckTimeFieldFormat},
+		{"checkLanguage", CheckLanguage},
+	}
+	// validations which depend on the request, e.g. on its tenant
+	customCtxValidations := []struct {
+		FunctionTag string
+		Function    validator.FuncCtx
+	}{
+		{"checkVideoQuality", CheckVideoQuality},
+		{"checkAudioQuality", CheckAudioQuality},
 	}
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		// problem details name fields as clients send them, e.g. `date` rather than `Timestamp`,
+		// struct-level errors pass their path as struct field name, which AppError descriptions keep using
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				return field.Name
+			}
+			return name
+		})
 		for _, validationDeclaration := range customValidations {
 			err := v.RegisterValidation(
 				validationDeclaration.FunctionTag,
@@ -32,5 +52,19 @@ var BindCustomValidators = func() {
 					)
 			}
 		}
+		v.RegisterStructValidation(CheckStreamingConfig, models.EventData{})
+		for _, validationDeclaration := range customCtxValidations {
+			err := v.RegisterValidationCtx(
+				validationDeclaration.FunctionTag,
+				validationDeclaration.Function,
+			)
+			if err != nil {
+				log.Logger.Error().
+					Msgf("Failed to register custom validation `%v` - %v",
+						validationDeclaration.FunctionTag,
+						err,
+					)
+			}
+		}
 	}
 }
@@ -0,0 +1,113 @@
+package validations
+
+import (
+	"app/models"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/exp/slices"
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// languageNames maps lower case English and native names of languages with display names, e.g. `english`
+// or `français`, to their tags, so clients sending names keep working.
+var languageNames = func() map[string]language.Tag {
+	names := map[string]language.Tag{}
+	for _, tag := range display.Supported.Tags() {
+		for _, name := range []string{display.English.Tags().Name(tag), display.Self.Name(tag)} {
+			if name == "" {
+				continue
+			}
+			if _, ok := names[strings.ToLower(name)]; !ok {
+				names[strings.ToLower(name)] = tag
+			}
+		}
+	}
+	return names
+}()
+
+// displayLocales are locales language names are returned in, English is the one used when none matches.
+var displayLocales = language.NewMatcher(append([]language.Tag{language.English}, display.Supported.Tags()...))
+
+// CanonicalLanguage returns canonical BCP 47 tag of `value`, a tag in any case, e.g. `EN-us`,
+// or English or native name of a language, e.g. `French`. It returns false for unknown languages.
+func CanonicalLanguage(value string) (string, bool) {
+	tag, err := language.Parse(strings.TrimSpace(value))
+	if err != nil {
+		var ok bool
+		if tag, ok = languageNames[strings.ToLower(strings.TrimSpace(value))]; !ok {
+			return "", false
+		}
+	}
+	if tag == language.Und {
+		return "", false
+	}
+	return tag.String(), true
+}
+
+// CheckLanguage accepts a language or list of languages CanonicalLanguage knows.
+var CheckLanguage validator.Func = func(fl validator.FieldLevel) bool {
+	values := []string{}
+	switch value := fl.Field().Interface().(type) {
+	case string:
+		values = append(values, value)
+	case []string:
+		values = value
+	}
+	for _, value := range values {
+		if _, ok := CanonicalLanguage(value); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizedLanguage returns canonical tag of `value`, unknown values are kept as they are,
+// so events stored before languages were validated can still be read.
+func normalizedLanguage(value string) string {
+	if tag, ok := CanonicalLanguage(value); ok {
+		return tag
+	}
+	return value
+}
+
+// NormalizeLanguages replaces languages of `event` and its streaming config with canonical tags,
+// dropping languages which turn out to be the same, e.g. `English` and `en`.
+func NormalizeLanguages(event *models.EventData) {
+	if event.Languages != nil {
+		languages := []string{}
+		for _, value := range event.Languages {
+			if tag := normalizedLanguage(value); !slices.Contains(languages, tag) {
+				languages = append(languages, tag)
+			}
+		}
+		event.Languages = languages
+	}
+	if event.Streaming == nil {
+		return
+	}
+	for i := range event.Streaming.AudioTracks {
+		event.Streaming.AudioTracks[i].Language = normalizedLanguage(event.Streaming.AudioTracks[i].Language)
+	}
+	for i := range event.Streaming.Subtitles {
+		event.Streaming.Subtitles[i].Language = normalizedLanguage(event.Streaming.Subtitles[i].Language)
+	}
+}
+
+// LanguageNames returns names of `tags` in locale best matching `acceptLanguage` header, English by default.
+func LanguageNames(tags []string, acceptLanguage string) map[string]string {
+	locale, _ := language.MatchStrings(displayLocales, acceptLanguage)
+	namer := display.Tags(locale)
+	names := map[string]string{}
+	for _, value := range tags {
+		tag, err := language.Parse(value)
+		if err != nil {
+			continue
+		}
+		if name := namer.Name(tag); name != "" {
+			names[value] = name
+		}
+	}
+	return names
+}
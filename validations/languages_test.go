@@ -0,0 +1,70 @@
+package validations
+
+import (
+	"app/models"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+var CanonicalLanguageTestCases = []struct {
+	value       string
+	expectedTag string
+	expectedOk  bool
+}{
+	{"en", "en", true},
+	{"EN-us", "en-US", true},
+	{"zh-hant", "zh-Hant", true},
+	{"iw", "he", true},
+	{"English", "en", true},
+	{" french ", "fr", true},
+	{"Deutsch", "de", true},
+	{"und", "", false},
+	{"xx", "", false},
+	{"Klingonese", "", false},
+	{"", "", false},
+}
+
+func TestCanonicalLanguage(t *testing.T) {
+	for _, testCase := range CanonicalLanguageTestCases {
+		t.Run(testCase.value, func(t *testing.T) {
+			tag, ok := CanonicalLanguage(testCase.value)
+			assert.Equal(t, testCase.expectedTag, tag)
+			assert.Equal(t, testCase.expectedOk, ok)
+		})
+	}
+}
+
+func TestCheckLanguage(t *testing.T) {
+	validate := validator.New()
+	validate.RegisterValidation("checkLanguage", CheckLanguage)
+
+	assert.Nil(t, validate.Var([]string{"en", "French", "pt-BR"}, "checkLanguage"))
+	assert.Error(t, validate.Var([]string{"en", "Elvish"}, "checkLanguage"))
+	assert.Nil(t, validate.Var("es-419", "checkLanguage"))
+	assert.Error(t, validate.Var("es_419_x", "checkLanguage"))
+}
+
+func TestNormalizeLanguages(t *testing.T) {
+	event := models.EventData{
+		Languages: []string{"English", "en", "fr-ca", "Old Elvish"},
+		Streaming: &models.StreamingConfig{
+			AudioTracks: []models.AudioTrack{{Language: "English"}},
+			Subtitles:   []models.SubtitleTrack{{Language: "FR-CA"}},
+		},
+	}
+	NormalizeLanguages(&event)
+
+	assert.Equal(t, []string{"en", "fr-CA", "Old Elvish"}, event.Languages, "unknown values should be kept")
+	assert.Equal(t, "en", event.Streaming.AudioTracks[0].Language)
+	assert.Equal(t, "fr-CA", event.Streaming.Subtitles[0].Language)
+}
+
+func TestLanguageNames(t *testing.T) {
+	assert.Equal(t, map[string]string{"en": "English", "fr-CA": "Canadian French"},
+		LanguageNames([]string{"en", "fr-CA", "Old Elvish"}, ""))
+	assert.Equal(t, map[string]string{"en": "Englisch", "fr": "Französisch"},
+		LanguageNames([]string{"en", "fr"}, "de-AT,de;q=0.9,en;q=0.5"))
+	assert.Equal(t, map[string]string{"en": "English"}, LanguageNames([]string{"en"}, "tlh"))
+}
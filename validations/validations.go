@@ -1,49 +1,127 @@
 package validations
 
 import (
+	"app/catalog"
+	lg "app/logging"
+	"app/models"
 	"app/utils"
+	"app/weberrors"
+	"context"
 	"errors"
+	"fmt"
 	"net/mail"
 	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"golang.org/x/exp/slices"
 )
 
-var GetBindErrors = func(submitErrors error) error {
+// GetBindErrors returns validation errors of the payload, nil if it could not be decoded at all.
+var GetBindErrors = func(c context.Context, submitErrors error) error {
+	if submitErrors == nil {
+		return nil
+	}
 	var verrs validator.ValidationErrors
 	if errors.As(submitErrors, &verrs) {
+		lg.WithContext(c).Debug().Msgf("payload failed validation: %v", verrs)
 		return verrs
 	}
+	lg.WithContext(c).Warn().Msgf("malformed payload: %v", submitErrors)
 	return nil
 }
 
-var CheckAudioQuality validator.Func = func(fl validator.FieldLevel) bool {
-	list := fl.Field().Interface().([]string)
-	if len(list) == 0 {
+// checkQualities returns validation of a quality list, profiles of `kind` which tenant of the request
+// has enabled in the catalog are allowed.
+func checkQualities(kind string) validator.FuncCtx {
+	return func(c context.Context, fl validator.FieldLevel) bool {
+		list := fl.Field().Interface().([]string)
+		if len(list) == 0 {
+			return true
+		}
+		allowed := catalog.Allowed(c, kind)
+		for _, v := range list {
+			if !slices.Contains(allowed, v) {
+				return false
+			}
+		}
 		return true
 	}
-	for _, v := range list {
+}
 
-		if !slices.Contains(utils.ALLOWED_AUDIO, v) {
-			return false
-		}
+var CheckAudioQuality = checkQualities(models.QualityAudio)
+
+var CheckVideoQuality = checkQualities(models.QualityVideo)
+
+// AllowedValues lists values `tag` allows for tenant of request `c`, nil for tags without a fixed set.
+func AllowedValues(c context.Context, tag string) []string {
+	switch tag {
+	case "checkVideoQuality":
+		return catalog.Allowed(c, models.QualityVideo)
+	case "checkAudioQuality":
+		return catalog.Allowed(c, models.QualityAudio)
 	}
-	return true
+	return nil
 }
 
-var CheckVideoQuality validator.Func = func(fl validator.FieldLevel) bool {
-	list := fl.Field().Interface().([]string)
-	if len(list) == 0 {
-		return true
+// Middleware lets validation error messages list values allowed for the request.
+func Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Request = ctx.Request.WithContext(weberrors.WithAllowedValues(ctx.Request.Context(), AllowedValues))
+		ctx.Next()
 	}
-	for _, v := range list {
-		if !slices.Contains(utils.ALLOWED_RESOLUTION, v) {
-			return false
+}
+
+// ValidateForTenant validates `obj` against settings of tenant of request `c`, binding has checked it
+// already against settings shared by all tenants.
+var ValidateForTenant = func(c context.Context, obj any) error {
+	if lg.Tenant(c) == "" {
+		return nil
+	}
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		return v.StructCtx(c, obj)
+	}
+	return nil
+}
+
+// CheckStreamingConfig validates fields of streaming config which depend on other fields of the event.
+var CheckStreamingConfig validator.StructLevelFunc = func(sl validator.StructLevel) {
+	event := sl.Current().Interface().(models.EventData)
+	if event.Streaming == nil {
+		return
+	}
+	if !slices.Contains(event.Streaming.Ladder, event.Streaming.DefaultRendition) {
+		sl.ReportError(event.Streaming.DefaultRendition, "streaming.defaultRendition", "streaming.defaultRendition",
+			"inField", "streaming.ladder")
+	}
+	languages := []string{}
+	for _, language := range event.Languages {
+		languages = append(languages, normalizedLanguage(language))
+	}
+	trackLanguages := []string{}
+	for i, track := range event.Streaming.AudioTracks {
+		language := normalizedLanguage(track.Language)
+		path := fmt.Sprintf("streaming.audioTracks[%d].language", i)
+		if !slices.Contains(languages, language) {
+			sl.ReportError(track.Language, path, path, "inField", "languages")
+		}
+		// e.g. `English` and `en`, which `unique` cannot tell
+		if slices.Contains(trackLanguages, language) {
+			sl.ReportError(track.Language, path, path, "unique", "")
 		}
+		trackLanguages = append(trackLanguages, language)
+	}
+	subtitleLanguages := []string{}
+	for i, subtitle := range event.Streaming.Subtitles {
+		language := normalizedLanguage(subtitle.Language)
+		path := fmt.Sprintf("streaming.subtitles[%d].language", i)
+		if slices.Contains(subtitleLanguages, language) {
+			sl.ReportError(subtitle.Language, path, path, "unique", "")
+		}
+		subtitleLanguages = append(subtitleLanguages, language)
 	}
-	return true
 }
 
 var CheckUuidFormat = func(inputString string) bool {
@@ -54,6 +132,7 @@ var CheckUuidFormat = func(inputString string) bool {
 var CheckEventNameValid validator.Func = func(fl validator.FieldLevel) bool {
 	return utils.EventNameRegex.MatchString(fl.Field().String())
 }
+
 var CheckEmailValid validator.Func = func(fl validator.FieldLevel) bool {
 	emailList := fl.Field().Interface().([]string)
 	var err error
@@ -66,9 +145,7 @@ var CheckEmailValid validator.Func = func(fl validator.FieldLevel) bool {
 	return true
 }
 
-const customTimeLayout = "2006-01-02T15:04:05Z"
-
 var CheckTimeFieldFormat validator.Func = func(fl validator.FieldLevel) bool {
-	_, err := time.Parse(customTimeLayout, fl.Field().String())
+	_, err := time.Parse(utils.TIMESTAMP_LAYOUT, fl.Field().String())
 	return err == nil
 }
@@ -1,6 +1,10 @@
 package validations
 
 import (
+	"app/catalog"
+	lg "app/logging"
+	"app/models"
+	"context"
 	"testing"
 
 	"github.com/go-playground/validator/v10"
@@ -38,7 +42,7 @@ func TestGetBindErrors(t *testing.T) {
 			})
 			err := validate.Var(inputStruct{a: ""}, "tag")
 
-			resp := GetBindErrors(err)
+			resp := GetBindErrors(context.Background(), err)
 
 			if testCase.matchResp {
 				assert.Equal(t, resp, err)
@@ -63,9 +67,9 @@ func TestCheckVideoQuality(t *testing.T) {
 	for _, testCase := range CheckVideoQualityTestCases {
 		t.Run(testCase.description, func(t *testing.T) {
 			validate := validator.New()
-			validate.RegisterValidation("checkVideoQuality", CheckVideoQuality)
+			validate.RegisterValidationCtx("checkVideoQuality", CheckVideoQuality)
 			t.Run(testCase.description, func(t *testing.T) {
-				err := validate.Var(testCase.submitList, "checkVideoQuality")
+				err := validate.VarCtx(context.Background(), testCase.submitList, "checkVideoQuality")
 				if testCase.expectedResp {
 					assert.Nil(t, err)
 				} else {
@@ -90,9 +94,9 @@ func TestCheckAudioQuality(t *testing.T) {
 	for _, testCase := range CheckAudioQualityTestCases {
 		t.Run(testCase.description, func(t *testing.T) {
 			validate := validator.New()
-			validate.RegisterValidation("checkAudioQuality", CheckAudioQuality)
+			validate.RegisterValidationCtx("checkAudioQuality", CheckAudioQuality)
 			t.Run(testCase.description, func(t *testing.T) {
-				err := validate.Var(testCase.submitList, "checkAudioQuality")
+				err := validate.VarCtx(context.Background(), testCase.submitList, "checkAudioQuality")
 				if testCase.expectedResp {
 					assert.Nil(t, err)
 				} else {
@@ -103,6 +107,93 @@ func TestCheckAudioQuality(t *testing.T) {
 	}
 }
 
+func TestCheckQualitiesOfTenant(t *testing.T) {
+	defer catalog.Set(catalog.DefaultProfiles, map[string]models.TenantQualities{})
+	catalog.Set(catalog.DefaultProfiles, map[string]models.TenantQualities{
+		"tenant-1": {Video: []string{"720p"}, Audio: []string{"Low", "Mid"}},
+	})
+	validate := validator.New()
+	validate.RegisterValidationCtx("checkVideoQuality", CheckVideoQuality)
+	validate.RegisterValidationCtx("checkAudioQuality", CheckAudioQuality)
+	c := lg.WithTenant(context.Background(), "tenant-1")
+
+	assert.Nil(t, validate.VarCtx(c, []string{"720p"}, "checkVideoQuality"))
+	assert.Error(t, validate.VarCtx(c, []string{"720p", "1080p"}, "checkVideoQuality"), "should allow enabled set only")
+	assert.Nil(t, validate.VarCtx(c, []string{"Mid"}, "checkAudioQuality"))
+	assert.Nil(t, validate.VarCtx(lg.WithTenant(context.Background(), "tenant-2"), []string{"2160p"}, "checkVideoQuality"),
+		"tenant without enabled set should use whole catalog")
+}
+
+var CheckStreamingConfigTestCases = []struct {
+	description    string
+	streaming      *models.StreamingConfig
+	expectedErrors []string
+}{
+	{"Pass - without streaming config", nil, nil},
+	{
+		"Pass - default in ladder, tracks in languages",
+		&models.StreamingConfig{
+			DefaultRendition: "1080p",
+			Ladder:           []string{"720p", "1080p"},
+			AudioTracks:      []models.AudioTrack{{Language: "French", Qualities: []string{"Low"}}},
+		},
+		nil,
+	},
+	{
+		"Fail - default not in ladder, track language not in languages",
+		&models.StreamingConfig{
+			DefaultRendition: "2160p",
+			Ladder:           []string{"720p", "1080p"},
+			AudioTracks: []models.AudioTrack{
+				{Language: "English", Qualities: []string{"Low"}},
+				{Language: "German", Qualities: []string{"Low"}},
+			},
+		},
+		[]string{"inField streaming.defaultRendition", "inField streaming.audioTracks[1].language"},
+	},
+	{
+		"Pass - track languages compared as tags",
+		&models.StreamingConfig{
+			DefaultRendition: "720p",
+			Ladder:           []string{"720p"},
+			AudioTracks:      []models.AudioTrack{{Language: "en", Qualities: []string{"Low"}}},
+		},
+		nil,
+	},
+	{
+		"Fail - same language named twice",
+		&models.StreamingConfig{
+			DefaultRendition: "720p",
+			Ladder:           []string{"720p"},
+			AudioTracks: []models.AudioTrack{
+				{Language: "fr", Qualities: []string{"Low"}},
+				{Language: "French", Qualities: []string{"Mid"}},
+			},
+			Subtitles: []models.SubtitleTrack{{Language: "en-us"}, {Language: "en-US"}},
+		},
+		[]string{"unique streaming.audioTracks[1].language", "unique streaming.subtitles[1].language"},
+	},
+}
+
+func TestCheckStreamingConfig(t *testing.T) {
+	validate := validator.New()
+	validate.RegisterStructValidation(CheckStreamingConfig, models.EventData{})
+	for _, testCase := range CheckStreamingConfigTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := validate.Struct(models.EventData{Languages: []string{"English", "French"}, Streaming: testCase.streaming})
+			if testCase.expectedErrors == nil {
+				assert.Nil(t, err)
+				return
+			}
+			errors := []string{}
+			for _, fieldError := range err.(validator.ValidationErrors) {
+				errors = append(errors, fieldError.Tag()+" "+fieldError.Field())
+			}
+			assert.Equal(t, testCase.expectedErrors, errors)
+		})
+	}
+}
+
 var CheckUuidFormatTestCases = []struct {
 	description  string
 	submitId     string
@@ -5,38 +5,87 @@ import (
 )
 
 type AppError struct {
-	ErrorName   string `json:"error"`
+	ErrorName string `json:"error"`
+	// stable code of the error, clients may localize descriptions by it
+	ErrorCode   string `json:"errorCode" example:"not_found"`
 	Description string `json:"description"`
+	// id of the request, to be quoted in error reports
+	CorrelationId string `json:"correlationId,omitempty" example:"0b8a1f5e-4c1d-4f0e-9a57-1e6f4d7e2c11"`
+	// other errors of the request, when it failed for more reasons
+	AdditionalErrors []AppError `json:"additionalErrors,omitempty"`
 }
 
 type AppErrorWithCode struct {
 	Code int `json:"code"`
 	AppError
+	// message is catalog key of the description, empty when it cannot be translated, args are its arguments
+	message string
+	args    []any
 }
 
 const ValidationErrorName = "ValidationError"
 const InternalServerError = "InternalServerError"
 const PayloadError = "PayloadError"
 const NotFoundError = "NotFoundError"
+const ConflictError = "ConflictError"
+const ServiceUnavailableError = "ServiceUnavailableError"
+
+// error codes, also keys of their descriptions in message catalogs
+const (
+	ValidationErrorCode          = "validation_error"
+	InvalidPayloadCode           = "invalid_payload"
+	NotFoundCode                 = "not_found"
+	RouteNotFoundCode            = "route_not_found"
+	InternalErrorCode            = "internal_error"
+	ServiceUnavailableCode       = "service_unavailable"
+	ConcurrentUpdateCode         = "concurrent_update"
+	InvalidStateTransitionCode   = "invalid_state_transition"
+	EventNotLiveCode             = "event_not_live"
+	QualityProfileExistsCode     = "quality_profile_exists"
+	NoRenditionsCode             = "no_renditions"
+	InvalidEventAccessCode       = "invalid_event_access"
+	InvalidAdminTokenCode        = "invalid_admin_token"
+	StatusTransitionCode         = "status_transition_not_allowed"
+	NotInviteeCode               = "not_invitee"
+	ImmutableQualityFieldsCode   = "immutable_quality_fields"
+	UnknownQualityCode           = "unknown_quality"
+	InvalidLimitCode             = "invalid_limit"
+	InvalidTimestampQueryCode    = "invalid_timestamp_query"
+	InvalidSinceQueryCode        = "invalid_since_query"
+	InvalidLastEventIdHeaderCode = "invalid_last_event_id"
+	InvalidTypesQueryCode        = "invalid_types_query"
+	InvalidTenantCode            = "invalid_tenant"
+)
 
 const FieldErrorDescription = "Field `%v` %v"
 const InvalidJsonPayloadDesc = "Invalid JSON payload."
 const ResourceNotFoundErrorDesc = "The requested resource could not be found."
 const RouteNotFoundErrorDesc = "Route does not exist."
 const InternalServerDesc = "Internal Server Error."
+const ServiceUnavailableDesc = "Service is temporarily unavailable, retry later."
+const ConcurrentUpdateDesc = "Resource was changed by another request, retry the request."
+const InvalidStateTransitionDesc = "Requested state transition is not allowed."
+const EventNotLiveDesc = "Event is not live."
+const QualityProfileExistsDesc = "Quality profile already exists."
+const NoRenditionsDesc = "None of event video qualities is in the quality catalog."
+const InvalidEventAccessDesc = "Invalid event access token."
+const InvalidAdminTokenDesc = "Invalid admin token."
 
 var RouteNotFoundError = AppErrorWithCode{
 	Code: http.StatusNotFound,
 	AppError: AppError{
 		ErrorName:   NotFoundError,
+		ErrorCode:   RouteNotFoundCode,
 		Description: RouteNotFoundErrorDesc,
 	},
+	message: RouteNotFoundCode,
 }
 
 var ValidationError = AppErrorWithCode{
 	Code: http.StatusBadRequest,
 	AppError: AppError{
 		ErrorName:   ValidationErrorName,
+		ErrorCode:   ValidationErrorCode,
 		Description: FieldErrorDescription,
 	},
 }
@@ -45,22 +94,108 @@ var InvalidPayload = AppErrorWithCode{
 	Code: http.StatusBadRequest,
 	AppError: AppError{
 		ErrorName:   PayloadError,
+		ErrorCode:   InvalidPayloadCode,
 		Description: InvalidJsonPayloadDesc,
 	},
+	message: InvalidPayloadCode,
 }
 
 var NotFound = AppErrorWithCode{
 	Code: http.StatusNotFound,
 	AppError: AppError{
 		ErrorName:   NotFoundError,
+		ErrorCode:   NotFoundCode,
 		Description: ResourceNotFoundErrorDesc,
 	},
+	message: NotFoundCode,
 }
 
 var InternalError = AppErrorWithCode{
 	Code: http.StatusInternalServerError,
 	AppError: AppError{
 		ErrorName:   InternalServerError,
+		ErrorCode:   InternalErrorCode,
 		Description: InternalServerDesc,
 	},
+	message: InternalErrorCode,
+}
+
+var ServiceUnavailable = AppErrorWithCode{
+	Code: http.StatusServiceUnavailable,
+	AppError: AppError{
+		ErrorName:   ServiceUnavailableError,
+		ErrorCode:   ServiceUnavailableCode,
+		Description: ServiceUnavailableDesc,
+	},
+	message: ServiceUnavailableCode,
+}
+
+var ConcurrentUpdate = AppErrorWithCode{
+	Code: http.StatusConflict,
+	AppError: AppError{
+		ErrorName:   ConflictError,
+		ErrorCode:   ConcurrentUpdateCode,
+		Description: ConcurrentUpdateDesc,
+	},
+	message: ConcurrentUpdateCode,
+}
+
+var InvalidStateTransition = AppErrorWithCode{
+	Code: http.StatusConflict,
+	AppError: AppError{
+		ErrorName:   ConflictError,
+		ErrorCode:   InvalidStateTransitionCode,
+		Description: InvalidStateTransitionDesc,
+	},
+	message: InvalidStateTransitionCode,
+}
+
+var EventNotLive = AppErrorWithCode{
+	Code: http.StatusConflict,
+	AppError: AppError{
+		ErrorName:   ConflictError,
+		ErrorCode:   EventNotLiveCode,
+		Description: EventNotLiveDesc,
+	},
+	message: EventNotLiveCode,
+}
+
+var QualityProfileExists = AppErrorWithCode{
+	Code: http.StatusConflict,
+	AppError: AppError{
+		ErrorName:   ConflictError,
+		ErrorCode:   QualityProfileExistsCode,
+		Description: QualityProfileExistsDesc,
+	},
+	message: QualityProfileExistsCode,
+}
+
+var NoRenditions = AppErrorWithCode{
+	Code: http.StatusConflict,
+	AppError: AppError{
+		ErrorName:   ConflictError,
+		ErrorCode:   NoRenditionsCode,
+		Description: NoRenditionsDesc,
+	},
+	message: NoRenditionsCode,
+}
+
+var InvalidEventAccess = AppErrorWithCode{
+	Code: http.StatusUnauthorized,
+	AppError: AppError{
+		ErrorName:   http.StatusText(http.StatusUnauthorized),
+		ErrorCode:   InvalidEventAccessCode,
+		Description: InvalidEventAccessDesc,
+	},
+	message: InvalidEventAccessCode,
+}
+
+var InvalidAdminToken = AppErrorWithCode{
+	Code: http.StatusUnauthorized,
+	AppError: AppError{
+		ErrorName:   http.StatusText(http.StatusUnauthorized),
+		ErrorCode:   InvalidAdminTokenCode,
+		Description: InvalidAdminTokenDesc,
+	},
+	message: InvalidAdminTokenCode,
 }
@@ -2,72 +2,110 @@ package weberrors
 
 import (
 	"app/logging"
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"strings"
+	"sort"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"golang.org/x/exp/slices"
 )
 
 func JSONAppErrorReporter() gin.HandlerFunc {
-	return customJsonAppErrorReporter(gin.ErrorTypeAny)
+	return customJsonAppErrorReporter(gin.ErrorTypePublic)
 }
 
+// ParseAppError returns AppError reported to client for `err`, see FromError.
 func ParseAppError(err error) AppError {
 	if err == nil {
 		return AppError{}
 	}
-	e := strings.SplitN(err.Error(), "-", 2)
-	if len(e) < 2 {
-		panic("`error` has to be `AppError` type")
+	return FromError(err).AppError
+}
+
+// statusPrecedence orders statuses of errors reported together, the first listed one is status of the response.
+// Server errors take precedence over client errors, statuses not listed here come last of their class.
+var statusPrecedence = []int{
+	http.StatusInternalServerError,
+	http.StatusServiceUnavailable,
+	http.StatusUnauthorized,
+	http.StatusForbidden,
+	http.StatusNotFound,
+	http.StatusConflict,
+	http.StatusBadRequest,
+}
+
+func precedence(status int) int {
+	rank := slices.Index(statusPrecedence, status)
+	if rank < 0 {
+		rank = len(statusPrecedence)
 	}
-	appError := AppError{
-		ErrorName:   e[0],
-		Description: e[1],
+	if status < http.StatusInternalServerError {
+		rank += len(statusPrecedence) + 1
 	}
-	return appError
+	return rank
 }
 
+// reportedError is error as reported to client, with failed validations when it is validation error
+type reportedError struct {
+	status   int
+	appError AppError
+	fields   []FieldProblem
+}
+
+func reportError(c context.Context, err error) reportedError {
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		return reportedError{
+			status: http.StatusBadRequest,
+			appError: AppError{
+				ErrorName:   ValidationErrorName,
+				ErrorCode:   ValidationErrorCode,
+				Description: GetErrorText(c, verrs),
+			},
+			fields: fieldProblems(c, verrs),
+		}
+	}
+	appError := FromError(err)
+	return reportedError{status: appError.Code, appError: appError.Localize(c)}
+}
+
+// customJsonAppErrorReporter reports errors of `errType` in one response, other errors of the request, e.g. private
+// ones attached by utils.AppendPrivateContextError, are only logged. Errors of requests which response was written
+// already are only logged too.
 func customJsonAppErrorReporter(errType gin.ErrorType) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		ctx.Next()
-		detectedErrors := ctx.Errors.ByType(errType)
+		detectedErrors := ctx.Errors
 
 		if len(detectedErrors) == 0 {
 			return
 		}
 
 		logger := logging.WithContext(ctx)
-		err := detectedErrors[0].Err
-		var parsedError *AppError
-		var errorCode int
-
-		switch err := err.(type) {
-		case validator.ValidationErrors:
-			logger.Error().Err(err).Msg("Request validation error occurred")
-			ctx.JSON(
-				http.StatusBadRequest,
-				AppError{
-					ErrorName:   ValidationErrorName,
-					Description: GetErrorText(err),
-				})
-			return
-		case *AppErrorWithCode:
-			logger.Error().Err(err).Msg(fmt.Sprintf("%v error occurred", err.ErrorName))
-			errorCode = err.Code
-			parsedError = &AppError{
-				ErrorName:   err.ErrorName,
-				Description: err.Description,
-			}
-		default:
-			logger.Error().Err(err).Msg("Unexpected error occurred")
-			errorCode = http.StatusInternalServerError
-			parsedError = &AppError{
-				ErrorName:   InternalServerError,
-				Description: "Internal Server Error.",
+		c := localeOf(ctx)
+		reported := []reportedError{}
+		for _, detected := range detectedErrors {
+			if !detected.IsType(errType) {
+				logger.Error().Err(detected.Err).Msg("Private error occurred")
+				continue
 			}
+			e := reportError(c, detected.Err)
+			logger.Error().Err(detected.Err).Msg(fmt.Sprintf("%v error occurred", e.appError.ErrorName))
+			reported = append(reported, e)
+		}
+		if len(reported) == 0 {
+			return
+		}
+		if ctx.Writer.Written() {
+			logger.Warn().Msg(fmt.Sprintf("Response was written already, %v errors are not reported", len(reported)))
+			return
 		}
-		ctx.JSON(errorCode, parsedError)
+		sort.SliceStable(reported, func(i, j int) bool {
+			return precedence(reported[i].status) < precedence(reported[j].status)
+		})
+		respond(ctx, c, reported)
 	}
 }
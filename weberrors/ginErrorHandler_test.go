@@ -1,6 +1,9 @@
 package weberrors
 
 import (
+	"app/logging"
+	"app/metrics"
+	"app/utils"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -8,9 +11,14 @@ import (
 
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -41,7 +49,7 @@ func TestJsonAppErrorReporter(t *testing.T) {
 		errorResponse := func(c *gin.Context) {
 			bindEntity := ValidationCheckStruct{}
 			if err := c.ShouldBindJSON(&bindEntity); err != nil {
-				c.Error(err)
+				c.Error(err).SetType(gin.ErrorTypePublic)
 				c.Abort()
 			}
 		}
@@ -66,10 +74,11 @@ func TestJsonAppErrorReporter(t *testing.T) {
 
 	t.Run("case AppErrorWithCode (NotFound)", func(t *testing.T) {
 		errorResponse := func(c *gin.Context) {
-			c.Error(&NotFound)
+			c.Error(&NotFound).SetType(gin.ErrorTypePublic)
 		}
 		expectedError := ParseAppError(&NotFound)
 		r.GET("/not-found", errorResponse)
+		countBefore := testutil.ToFloat64(metrics.AppErrors.WithLabelValues(NotFoundError))
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequestWithContext(context.Background(), "GET", "/not-found", nil)
@@ -79,11 +88,13 @@ func TestJsonAppErrorReporter(t *testing.T) {
 		decodedError := AppError{}
 		json.NewDecoder(w.Body).Decode(&decodedError)
 		assert.Equal(t, expectedError, decodedError)
+		assert.Equal(t, countBefore+1, testutil.ToFloat64(metrics.AppErrors.WithLabelValues(NotFoundError)),
+			"should count response by error name")
 	})
 
 	t.Run("case default", func(t *testing.T) {
 		errorResponse := func(c *gin.Context) {
-			c.Error(errors.New("Unexpected error."))
+			c.Error(errors.New("Unexpected error.")).SetType(gin.ErrorTypePublic)
 		}
 		expectedError := ParseAppError(InternalError.ChangeDesc("Internal Server Error"))
 		r.GET("/default-error", errorResponse)
@@ -98,4 +109,172 @@ func TestJsonAppErrorReporter(t *testing.T) {
 		assert.Equal(t, expectedError, decodedError)
 	})
 
+	t.Run("correlation id of the request", func(t *testing.T) {
+		r := gin.New()
+		r.Use(logging.Middleware())
+		r.Use(JSONAppErrorReporter())
+		r.GET("/not-found", func(c *gin.Context) {
+			c.Error(&NotFound).SetType(gin.ErrorTypePublic)
+		})
+		expectedError := ParseAppError(&NotFound)
+		expectedError.CorrelationId = "upstream-request-1"
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), "GET", "/not-found", nil)
+		req.Header.Set(logging.RequestIdHeader, "upstream-request-1")
+		r.ServeHTTP(w, req)
+
+		decodedError := AppError{}
+		json.NewDecoder(w.Body).Decode(&decodedError)
+		assert.Equal(t, expectedError, decodedError)
+		assert.Equal(t, "upstream-request-1", w.Header().Get(logging.CorrelationIdHeader))
+	})
+
+	t.Run("error is logged with request logger", func(t *testing.T) {
+		r := gin.New()
+		r.Use(logging.Middleware())
+		r.Use(JSONAppErrorReporter())
+		r.GET("/event/:id", func(c *gin.Context) {
+			c.Error(&NotFound).SetType(gin.ErrorTypePublic)
+		})
+		var output bytes.Buffer
+		log.Logger = log.Output(&output)
+		defer func() { log.Logger = log.Output(os.Stderr) }()
+
+		req, _ := http.NewRequestWithContext(context.Background(), "GET", "/event/event-id", nil)
+		req.Header.Set(logging.RequestIdHeader, "upstream-request-1")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+
+		var errorLog string
+		for _, line := range strings.Split(output.String(), "\n") {
+			if strings.Contains(line, "NotFoundError error occurred") {
+				errorLog = line
+			}
+		}
+		assert.Contains(t, errorLog, `"correlation_id":"upstream-request-1"`)
+		assert.Contains(t, errorLog, `"route":"/event/:id"`)
+	})
+	t.Run("description in locale of Accept-Language", func(t *testing.T) {
+		r.GET("/localized-not-found", func(c *gin.Context) {
+			c.Error(&NotFound).SetType(gin.ErrorTypePublic)
+		})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), "GET", "/localized-not-found", nil)
+		req.Header.Set("Accept-Language", "fr-CA,fr;q=0.9")
+		r.ServeHTTP(w, req)
+
+		decodedError := AppError{}
+		json.NewDecoder(w.Body).Decode(&decodedError)
+		assert.Equal(t, NotFoundCode, decodedError.ErrorCode)
+		assert.Equal(t, "La ressource demandée est introuvable.", decodedError.Description)
+		assert.Equal(t, "fr", w.Header().Get("Content-Language"))
+	})
+}
+
+func TestJsonAppErrorReporterAggregation(t *testing.T) {
+	r := gin.New()
+	r.Use(JSONAppErrorReporter())
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequestWithContext(context.Background(), "GET", path, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("every public error is reported, status by precedence", func(t *testing.T) {
+		r.GET("/many-errors", func(c *gin.Context) {
+			c.Error(&InvalidAdminToken).SetType(gin.ErrorTypePublic)
+			c.Error(&NotFound).SetType(gin.ErrorTypePublic)
+			c.Error(errors.New("unexpected")).SetType(gin.ErrorTypePublic)
+		})
+
+		w := serve("/many-errors")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		decodedError := AppError{}
+		json.NewDecoder(w.Body).Decode(&decodedError)
+		expectedError := InternalError.AppError
+		expectedError.AdditionalErrors = []AppError{InvalidAdminToken.AppError, NotFound.AppError}
+		assert.Equal(t, expectedError, decodedError)
+	})
+
+	t.Run("client errors in order of precedence", func(t *testing.T) {
+		r.GET("/client-errors", func(c *gin.Context) {
+			c.Error(&QualityProfileExists).SetType(gin.ErrorTypePublic)
+			c.Error(&NotFound).SetType(gin.ErrorTypePublic)
+		})
+
+		w := serve("/client-errors")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		decodedError := AppError{}
+		json.NewDecoder(w.Body).Decode(&decodedError)
+		assert.Equal(t, NotFoundCode, decodedError.ErrorCode)
+		assert.Equal(t, []AppError{QualityProfileExists.AppError}, decodedError.AdditionalErrors)
+	})
+
+	t.Run("private errors are not reported", func(t *testing.T) {
+		r.GET("/private-error", func(c *gin.Context) {
+			utils.AppendPrivateContextError(c, errors.New("reminder not cancelled"))
+			c.Error(&NotFound).SetType(gin.ErrorTypePublic)
+		})
+		r.GET("/private-error-only", func(c *gin.Context) {
+			utils.AppendPrivateContextError(c, errors.New("reminder not cancelled"))
+			c.Status(http.StatusNoContent)
+		})
+
+		w := serve("/private-error")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		decodedError := AppError{}
+		json.NewDecoder(w.Body).Decode(&decodedError)
+		assert.Equal(t, NotFound.AppError, decodedError)
+
+		w = serve("/private-error-only")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Body.String())
+
+		r.GET("/untyped-error", func(c *gin.Context) {
+			c.Error(errors.New("gin marks it private"))
+			c.Status(http.StatusNoContent)
+		})
+		w = serve("/untyped-error")
+		assert.Equal(t, http.StatusNoContent, w.Code, "only public errors should be reported")
+	})
+
+	t.Run("written response is kept", func(t *testing.T) {
+		r.GET("/written", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"status": "ok"})
+			c.Error(&InternalError).SetType(gin.ErrorTypePublic)
+		})
+		countBefore := testutil.ToFloat64(metrics.AppErrors.WithLabelValues(InternalServerError))
+
+		w := serve("/written")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+		assert.Equal(t, countBefore, testutil.ToFloat64(metrics.AppErrors.WithLabelValues(InternalServerError)))
+	})
+}
+
+func TestPrecedence(t *testing.T) {
+	statuses := []int{
+		http.StatusBadRequest,
+		http.StatusTeapot,
+		http.StatusBadGateway,
+		http.StatusNotFound,
+		http.StatusUnauthorized,
+		http.StatusInternalServerError,
+	}
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return precedence(statuses[i]) < precedence(statuses[j])
+	})
+	assert.Equal(t, []int{
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusUnauthorized,
+		http.StatusNotFound,
+		http.StatusBadRequest,
+		http.StatusTeapot,
+	}, statuses)
 }
@@ -0,0 +1,34 @@
+package weberrors
+
+import (
+	"errors"
+)
+
+// DomainError maps error of another package, possibly wrapped, to error reported to client.
+type DomainError struct {
+	Err      error
+	AppError *AppErrorWithCode
+}
+
+var domainErrors = []DomainError{}
+
+// MapErrors adds `mappings` used by FromError, the first one matching by errors.Is is used,
+// they are meant to be added at startup by packages which know the domain errors.
+func MapErrors(mappings ...DomainError) {
+	domainErrors = append(domainErrors, mappings...)
+}
+
+// FromError returns error reported to client for `err`, which is either AppErrorWithCode itself,
+// error mapped by MapErrors or InternalError for any other error.
+func FromError(err error) *AppErrorWithCode {
+	var appError *AppErrorWithCode
+	if errors.As(err, &appError) {
+		return appError
+	}
+	for _, domainError := range domainErrors {
+		if errors.Is(err, domainError.Err) {
+			return domainError.AppError
+		}
+	}
+	return &InternalError
+}
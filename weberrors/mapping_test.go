@@ -0,0 +1,47 @@
+package weberrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errMissing = errors.New("missing")
+
+func mapTestErrors(t *testing.T) {
+	original := domainErrors
+	MapErrors(DomainError{Err: errMissing, AppError: &NotFound})
+	t.Cleanup(func() { domainErrors = original })
+}
+
+func TestFromError(t *testing.T) {
+	mapTestErrors(t)
+	testCases := []struct {
+		description string
+		err         error
+		expected    *AppErrorWithCode
+	}{
+		{description: "AppErrorWithCode", err: &QualityProfileExists, expected: &QualityProfileExists},
+		{description: "Wrapped AppErrorWithCode", err: fmt.Errorf("creating profile: %w", &QualityProfileExists),
+			expected: &QualityProfileExists},
+		{description: "Mapped error", err: errMissing, expected: &NotFound},
+		{description: "Wrapped mapped error", err: fmt.Errorf("get event: %w", errMissing), expected: &NotFound},
+		{description: "Unknown error", err: errors.New("unexpected"), expected: &InternalError},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, FromError(testCase.err))
+		})
+	}
+}
+
+func TestParseAppError(t *testing.T) {
+	mapTestErrors(t)
+	assert.Equal(t, AppError{}, ParseAppError(nil))
+	assert.Equal(t, NotFound.AppError, ParseAppError(fmt.Errorf("get event: %w", errMissing)))
+	assert.NotPanics(t, func() {
+		assert.Equal(t, InternalError.AppError, ParseAppError(errors.New("no dash in message")))
+	})
+}
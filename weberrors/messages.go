@@ -0,0 +1,117 @@
+package weberrors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// catalogs hold messages of every supported locale, keyed by error code, `validation.<tag>` for validation
+// errors, `field.<name>` for field names and `value.<value>` for parameters. Messages missing in a locale
+// are taken from English.
+var catalogs = map[language.Tag]map[string]string{
+	language.English: messagesEn,
+	language.German:  messagesDe,
+	language.French:  messagesFr,
+}
+
+var supportedLocales = []language.Tag{language.English, language.German, language.French}
+
+var localeMatcher = language.NewMatcher(supportedLocales)
+
+type localeKey struct{}
+
+// WithLocale returns `c` carrying supported locale best matching `acceptLanguage` header, English by default.
+func WithLocale(c context.Context, acceptLanguage string) context.Context {
+	locale := language.English
+	if tags, _, err := language.ParseAcceptLanguage(acceptLanguage); err == nil && len(tags) > 0 {
+		if _, index, confidence := localeMatcher.Match(tags...); confidence != language.No {
+			locale = supportedLocales[index]
+		}
+	}
+	return context.WithValue(c, localeKey{}, locale)
+}
+
+// Locale returns locale of messages of request `c`.
+func Locale(c context.Context) language.Tag {
+	if locale, ok := c.Value(localeKey{}).(language.Tag); ok {
+		return locale
+	}
+	return language.English
+}
+
+func lookup(locale language.Tag, key string) (string, bool) {
+	if message, ok := catalogs[locale][key]; ok {
+		return message, true
+	}
+	message, ok := catalogs[language.English][key]
+	return message, ok
+}
+
+// Field is a message argument translated as field name, e.g. `invitees`.
+type Field string
+
+// Param is a message argument translated as value, e.g. event status.
+type Param string
+
+// fieldName translates field `name` as reported by validator, e.g. `Invitees`, or as sent by client.
+func fieldName(locale language.Tag, name string) string {
+	if name == "" {
+		return name
+	}
+	name = strings.ToLower(name[0:1]) + name[1:]
+	if translated, ok := lookup(locale, "field."+name); ok {
+		return translated
+	}
+	return name
+}
+
+// translate formats message `key` in `locale`, translating Field and Param arguments.
+func translate(locale language.Tag, key string, args ...any) (string, bool) {
+	message, ok := lookup(locale, key)
+	if !ok {
+		return "", false
+	}
+	translated := make([]any, len(args))
+	for i, arg := range args {
+		switch arg := arg.(type) {
+		case Field:
+			translated[i] = fieldName(locale, string(arg))
+		case Param:
+			translated[i] = string(arg)
+			if value, ok := lookup(locale, "value."+string(arg)); ok {
+				translated[i] = value
+			}
+		default:
+			translated[i] = arg
+		}
+	}
+	return fmt.Sprintf(message, translated...), true
+}
+
+// WithMessage returns copy of `e` with error code `code` and description of that code, formatted with `args`.
+// The description is translated to locale of the request when the error is reported.
+func (e *AppErrorWithCode) WithMessage(code string, args ...any) error {
+	var err = *e
+	err.ErrorCode = code
+	err.message = code
+	err.args = args
+	if description, ok := translate(language.English, code, args...); ok {
+		err.Description = description
+	}
+	return &err
+}
+
+// Localize returns `e` as reported to client, its description in locale of request `c`.
+func (e *AppErrorWithCode) Localize(c context.Context) AppError {
+	appError := e.AppError
+	if e.message == "" {
+		return appError
+	}
+	if description, ok := translate(Locale(c), e.message, e.args...); ok {
+		appError.Description = description
+	}
+	return appError
+}
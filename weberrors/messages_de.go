@@ -0,0 +1,68 @@
+package weberrors
+
+var messagesDe = map[string]string{
+	InvalidPayloadCode:         "Ungültiger JSON-Inhalt.",
+	NotFoundCode:               "Die angeforderte Ressource wurde nicht gefunden.",
+	RouteNotFoundCode:          "Route existiert nicht.",
+	InternalErrorCode:          "Interner Serverfehler.",
+	ServiceUnavailableCode:     "Dienst ist vorübergehend nicht verfügbar, bitte später erneut versuchen.",
+	ConcurrentUpdateCode:       "Ressource wurde von einer anderen Anfrage geändert, bitte Anfrage wiederholen.",
+	InvalidStateTransitionCode: "Angeforderter Statuswechsel ist nicht erlaubt.",
+	EventNotLiveCode:           "Veranstaltung ist nicht live.",
+	QualityProfileExistsCode:   "Qualitätsprofil existiert bereits.",
+	NoRenditionsCode:           "Keine Videoqualität der Veranstaltung ist im Qualitätskatalog.",
+	InvalidEventAccessCode:     "Ungültiges Zugriffstoken der Veranstaltung.",
+	InvalidAdminTokenCode:      "Ungültiges Admin-Token.",
+
+	StatusTransitionCode:         "Veranstaltung mit Status `%v` kann nicht %v werden.",
+	NotInviteeCode:               "Feld `%v` ist kein Gast der Veranstaltung.",
+	ImmutableQualityFieldsCode:   "Felder `%v` und `%v` können nicht geändert werden.",
+	UnknownQualityCode:           "Feld `%v` enthält unbekannte Qualität `%v`.",
+	InvalidLimitCode:             "Abfrage `limit` muss zwischen 1 und %v liegen.",
+	InvalidTimestampQueryCode:    "Abfrage `%v` muss ein RFC-3339-Zeitstempel sein.",
+	InvalidSinceQueryCode:        "Abfrage `since` muss eine Änderungs-ID oder ein RFC-3339-Zeitstempel sein.",
+	InvalidLastEventIdHeaderCode: "Header `Last-Event-ID` ist ungültig.",
+	InvalidTypesQueryCode:        "Abfrage `types` enthält unbekannten Typ `%v` (erlaubte Werte: %v).",
+	InvalidTenantCode:            "Pfad `tenant` ist keine gültige Mandanten-ID.",
+
+	"validation.required":              "Feld `%s` ist erforderlich",
+	"validation.len":                   "Feld `%s` muss die Länge %s haben",
+	"validation.max":                   "Feld `%s` darf nicht länger als %s sein",
+	"validation.min":                   "Feld `%s` muss länger als %s sein",
+	"validation.unique":                "Feld `%s` enthält doppelte Werte",
+	"validation.required_if":           "Feld `%s` ist erforderlich, wenn %s",
+	"validation.required_if.condition": "`%s` %s ist",
+	"validation.inField":               "Feld `%s` muss einer der Werte von `%s` sein",
+	"validation.oneof":                 "Feld `%s` muss einer dieser Werte sein: %s",
+	"validation.checkLanguage":         "Feld `%s` enthält eine unbekannte Sprache (BCP-47-Tags verwenden, z. B. en, fr-CA)",
+	"validation.checkEmail":            "Feld `%s` enthält eine ungültige E-Mail-Adresse",
+	"validation.checkVideoQuality":     "Feld `%s` enthält eine ungültige Auflösung (erlaubte Werte: %s)",
+	"validation.checkAudioQuality":     "Feld `%s` enthält eine ungültige Audioqualität (erlaubte Werte: %s)",
+	"validation.checkEventName":        "Feld `%s` enthält ungültige Zeichen (nur A-Za-z0-9 _- verwenden)",
+	"validation.checkTimeFieldFormat":  "Feld `Datum` hat nicht das richtige Format (YYYY-MM-DDTHH:MM:SSZ verwenden)",
+	"validation.invalid":               "Feld `%s` ist ungültig",
+
+	"field.name":         "Name",
+	"field.timestamp":    "Datum",
+	"field.languages":    "Sprachen",
+	"field.invitees":     "Gäste",
+	"field.description":  "Beschreibung",
+	"field.videoQuality": "Videoqualität",
+	"field.audioQuality": "Audioqualität",
+	"field.latencyMode":  "Latenzmodus",
+	"field.email":        "E-Mail",
+	"field.kind":         "Art",
+	"field.width":        "Breite",
+	"field.height":       "Höhe",
+
+	"value.publish": "veröffentlicht",
+	"value.start":   "gestartet",
+	"value.end":     "beendet",
+	"value.cancel":  "abgesagt",
+
+	"value.draft":     "Entwurf",
+	"value.scheduled": "geplant",
+	"value.live":      "live",
+	"value.ended":     "beendet",
+	"value.cancelled": "abgesagt",
+}
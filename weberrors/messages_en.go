@@ -0,0 +1,44 @@
+package weberrors
+
+var messagesEn = map[string]string{
+	InvalidPayloadCode:         InvalidJsonPayloadDesc,
+	NotFoundCode:               ResourceNotFoundErrorDesc,
+	RouteNotFoundCode:          RouteNotFoundErrorDesc,
+	InternalErrorCode:          InternalServerDesc,
+	ServiceUnavailableCode:     ServiceUnavailableDesc,
+	ConcurrentUpdateCode:       ConcurrentUpdateDesc,
+	InvalidStateTransitionCode: InvalidStateTransitionDesc,
+	EventNotLiveCode:           EventNotLiveDesc,
+	QualityProfileExistsCode:   QualityProfileExistsDesc,
+	NoRenditionsCode:           NoRenditionsDesc,
+	InvalidEventAccessCode:     InvalidEventAccessDesc,
+	InvalidAdminTokenCode:      InvalidAdminTokenDesc,
+
+	StatusTransitionCode:         "Event with status `%v` cannot %v.",
+	NotInviteeCode:               "Field `%v` is not an invitee of the event.",
+	ImmutableQualityFieldsCode:   "Fields `%v` and `%v` cannot be changed.",
+	UnknownQualityCode:           "Field `%v` contains unknown quality `%v`.",
+	InvalidLimitCode:             "Query `limit` must be between 1 and %v.",
+	InvalidTimestampQueryCode:    "Query `%v` must be RFC 3339 timestamp.",
+	InvalidSinceQueryCode:        "Query `since` must be change id or RFC 3339 timestamp.",
+	InvalidLastEventIdHeaderCode: "Header `Last-Event-ID` is invalid.",
+	InvalidTypesQueryCode:        "Query `types` contains unknown type `%v` (allowed values: %v).",
+	InvalidTenantCode:            "Path `tenant` is not a valid tenant id.",
+
+	"validation.required":              "field `%s` is required",
+	"validation.len":                   "field `%s` must be of length %s",
+	"validation.max":                   "field `%s` cannot be longer than %s",
+	"validation.min":                   "field `%s` must be longer than %s",
+	"validation.unique":                "field `%s` contains duplicate values",
+	"validation.required_if":           "field `%s` is required when %s",
+	"validation.required_if.condition": "`%s` is %s",
+	"validation.inField":               "field `%s` must be one of values of `%s`",
+	"validation.oneof":                 "field `%s` needs to be one of values: %s",
+	"validation.checkLanguage":         "field `%s` contains unknown language (use BCP 47 tags, e.g. en, fr-CA)",
+	"validation.checkEmail":            "field `%s` contains invalid email address",
+	"validation.checkVideoQuality":     "field `%s` contains invalid resolution (allowed values: %s)",
+	"validation.checkAudioQuality":     "field `%s` contains invalid audio quality (allowed values: %s)",
+	"validation.checkEventName":        "field `%s` contains invalid characters (use A-Za-z0-9 _- only)",
+	"validation.checkTimeFieldFormat":  "field `date` does not have correct format (use YYYY-MM-DDTHH:MM:SSZ)",
+	"validation.invalid":               "field `%s` is invalid",
+}
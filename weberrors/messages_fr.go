@@ -0,0 +1,68 @@
+package weberrors
+
+var messagesFr = map[string]string{
+	InvalidPayloadCode:         "Contenu JSON invalide.",
+	NotFoundCode:               "La ressource demandée est introuvable.",
+	RouteNotFoundCode:          "La route n'existe pas.",
+	InternalErrorCode:          "Erreur interne du serveur.",
+	ServiceUnavailableCode:     "Le service est temporairement indisponible, réessayez plus tard.",
+	ConcurrentUpdateCode:       "La ressource a été modifiée par une autre requête, réessayez la requête.",
+	InvalidStateTransitionCode: "Le changement d'état demandé n'est pas autorisé.",
+	EventNotLiveCode:           "L'événement n'est pas en direct.",
+	QualityProfileExistsCode:   "Le profil de qualité existe déjà.",
+	NoRenditionsCode:           "Aucune qualité vidéo de l'événement n'est dans le catalogue des qualités.",
+	InvalidEventAccessCode:     "Jeton d'accès à l'événement invalide.",
+	InvalidAdminTokenCode:      "Jeton d'administration invalide.",
+
+	StatusTransitionCode:         "Un événement au statut `%v` ne peut pas être %v.",
+	NotInviteeCode:               "Le champ `%v` n'est pas un invité de l'événement.",
+	ImmutableQualityFieldsCode:   "Les champs `%v` et `%v` ne peuvent pas être modifiés.",
+	UnknownQualityCode:           "Le champ `%v` contient la qualité inconnue `%v`.",
+	InvalidLimitCode:             "Le paramètre `limit` doit être compris entre 1 et %v.",
+	InvalidTimestampQueryCode:    "Le paramètre `%v` doit être un horodatage RFC 3339.",
+	InvalidSinceQueryCode:        "Le paramètre `since` doit être un identifiant de modification ou un horodatage RFC 3339.",
+	InvalidLastEventIdHeaderCode: "L'en-tête `Last-Event-ID` est invalide.",
+	InvalidTypesQueryCode:        "Le paramètre `types` contient le type inconnu `%v` (valeurs autorisées : %v).",
+	InvalidTenantCode:            "Le chemin `tenant` n'est pas un identifiant de locataire valide.",
+
+	"validation.required":              "le champ `%s` est obligatoire",
+	"validation.len":                   "le champ `%s` doit avoir une longueur de %s",
+	"validation.max":                   "le champ `%s` ne peut pas dépasser %s",
+	"validation.min":                   "le champ `%s` doit dépasser %s",
+	"validation.unique":                "le champ `%s` contient des valeurs en double",
+	"validation.required_if":           "le champ `%s` est obligatoire lorsque %s",
+	"validation.required_if.condition": "`%s` vaut %s",
+	"validation.inField":               "le champ `%s` doit être l'une des valeurs de `%s`",
+	"validation.oneof":                 "le champ `%s` doit être l'une des valeurs : %s",
+	"validation.checkLanguage":         "le champ `%s` contient une langue inconnue (utilisez des balises BCP 47, p. ex. en, fr-CA)",
+	"validation.checkEmail":            "le champ `%s` contient une adresse e-mail invalide",
+	"validation.checkVideoQuality":     "le champ `%s` contient une résolution invalide (valeurs autorisées : %s)",
+	"validation.checkAudioQuality":     "le champ `%s` contient une qualité audio invalide (valeurs autorisées : %s)",
+	"validation.checkEventName":        "le champ `%s` contient des caractères invalides (utilisez uniquement A-Za-z0-9 _-)",
+	"validation.checkTimeFieldFormat":  "le champ `date` n'a pas le bon format (utilisez YYYY-MM-DDTHH:MM:SSZ)",
+	"validation.invalid":               "le champ `%s` est invalide",
+
+	"field.name":         "nom",
+	"field.timestamp":    "date",
+	"field.languages":    "langues",
+	"field.invitees":     "invités",
+	"field.description":  "description",
+	"field.videoQuality": "qualité vidéo",
+	"field.audioQuality": "qualité audio",
+	"field.latencyMode":  "mode de latence",
+	"field.email":        "e-mail",
+	"field.kind":         "type",
+	"field.width":        "largeur",
+	"field.height":       "hauteur",
+
+	"value.publish": "publié",
+	"value.start":   "démarré",
+	"value.end":     "terminé",
+	"value.cancel":  "annulé",
+
+	"value.draft":     "brouillon",
+	"value.scheduled": "planifié",
+	"value.live":      "en direct",
+	"value.ended":     "terminé",
+	"value.cancelled": "annulé",
+}
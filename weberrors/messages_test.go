@@ -0,0 +1,86 @@
+package weberrors
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+var WithLocaleTestCases = []struct {
+	acceptLanguage string
+	expectedLocale language.Tag
+}{
+	{"", language.English},
+	{"de", language.German},
+	{"de-CH, en;q=0.5", language.German},
+	{"ja, fr;q=0.8, de;q=0.5", language.French},
+	{"ja", language.English},
+	{"not a header", language.English},
+}
+
+func TestWithLocale(t *testing.T) {
+	for _, testCase := range WithLocaleTestCases {
+		t.Run(testCase.acceptLanguage, func(t *testing.T) {
+			c := WithLocale(context.Background(), testCase.acceptLanguage)
+			assert.Equal(t, testCase.expectedLocale, Locale(c))
+		})
+	}
+	assert.Equal(t, language.English, Locale(context.Background()), "should default to English")
+}
+
+func TestCatalogsHaveEveryMessage(t *testing.T) {
+	for locale, messages := range catalogs {
+		for key, english := range messagesEn {
+			message, ok := messages[key]
+			assert.True(t, ok, "%v misses `%v`", locale, key)
+			assert.Equal(t, strings.Count(english, "%"), strings.Count(message, "%"),
+				"%v has other arguments in `%v`", locale, key)
+		}
+	}
+}
+
+func TestLocalizedValidationErrors(t *testing.T) {
+	type TestEvent struct {
+		Invitees []string `validate:"required"`
+		Kind     string
+		Width    int    `validate:"required_if=Kind video"`
+		Url      string `validate:"url"`
+	}
+	verrs := validator.New().Struct(TestEvent{Kind: "video", Url: "no"}).(validator.ValidationErrors)
+
+	assert.Equal(t, "Feld `Gäste` ist erforderlich, Feld `Breite` ist erforderlich, wenn `Art` video ist, "+
+		"Feld `url` ist ungültig.", GetErrorText(WithLocale(context.Background(), "de"), verrs))
+	assert.Equal(t, "Le champ `invités` est obligatoire, le champ `largeur` est obligatoire lorsque `type` vaut video, "+
+		"le champ `url` est invalide.", GetErrorText(WithLocale(context.Background(), "fr"), verrs))
+	assert.Equal(t, "Field `invitees` is required, field `width` is required when `kind` is video, "+
+		"field `url` is invalid.", GetErrorText(context.Background(), verrs))
+}
+
+func TestWithMessage(t *testing.T) {
+	err := InvalidStateTransition.WithMessage(StatusTransitionCode, Param("draft"), Param("end")).(*AppErrorWithCode)
+
+	assert.Equal(t, StatusTransitionCode, err.ErrorCode)
+	assert.Equal(t, "Event with status `draft` cannot end.", err.Description)
+	assert.Equal(t, "Veranstaltung mit Status `Entwurf` kann nicht beendet werden.",
+		err.Localize(WithLocale(context.Background(), "de")).Description)
+	assert.Equal(t, "Un événement au statut `brouillon` ne peut pas être terminé.",
+		err.Localize(WithLocale(context.Background(), "fr")).Description)
+	assert.Equal(t, InvalidStateTransitionDesc, InvalidStateTransition.Description, "should not change the definition")
+}
+
+func TestLocalize(t *testing.T) {
+	french := WithLocale(context.Background(), "fr")
+
+	assert.Equal(t, AppError{
+		ErrorName:   NotFoundError,
+		ErrorCode:   NotFoundCode,
+		Description: "La ressource demandée est introuvable.",
+	}, NotFound.Localize(french))
+	custom := NotFound.ChangeDesc("event is gone").(*AppErrorWithCode)
+	assert.Equal(t, "Event is gone.", custom.Localize(french).Description,
+		"description without catalog message should be kept")
+}
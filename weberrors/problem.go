@@ -0,0 +1,153 @@
+package weberrors
+
+import (
+	"app/logging"
+	"app/metrics"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypePrefix is followed by error code in `type` of problem details
+const ProblemTypePrefix = "urn:event-handler:problem:"
+
+// Problem is RFC 7807 problem details of an error, returned instead of AppError to clients which accept
+// application/problem+json.
+type Problem struct {
+	Type   string `json:"type" example:"urn:event-handler:problem:validation_error"`
+	Title  string `json:"title" example:"Bad Request"`
+	Status int    `json:"status" example:"400"`
+	Detail string `json:"detail" example:"Field 'name' is required."`
+	// path of the request
+	Instance      string `json:"instance" example:"/event"`
+	ErrorCode     string `json:"errorCode" example:"validation_error"`
+	CorrelationId string `json:"correlationId,omitempty" example:"0b8a1f5e-4c1d-4f0e-9a57-1e6f4d7e2c11"`
+	// failed validations, one per field
+	Errors []FieldProblem `json:"errors,omitempty"`
+	// other errors of the request, when it failed for more reasons
+	AdditionalErrors []AppError `json:"additionalErrors,omitempty"`
+}
+
+type FieldProblem struct {
+	// path of the field in the payload, with indexes of list items
+	Field string `json:"field" example:"invitees[2]"`
+	// failed validation
+	Tag     string `json:"tag" example:"checkEmail"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message" example:"Field 'invitees[2]' contains invalid email address"`
+}
+
+func acceptsProblem(ctx *gin.Context) bool {
+	return strings.Contains(ctx.GetHeader("Accept"), ProblemContentType)
+}
+
+// fieldPath returns path of `e` in the payload, e.g. `invitees[2]` or `streaming.audioTracks[0].language`
+func fieldPath(e validator.FieldError) string {
+	_, path, found := strings.Cut(e.Namespace(), ".")
+	if !found {
+		path = e.Field()
+	}
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		if segment != "" {
+			segments[i] = strings.ToLower(segment[0:1]) + segment[1:]
+		}
+	}
+	return strings.Join(segments, ".")
+}
+
+// invalidItems returns indexes of items of list `e` which fail its validation on their own, lists validated
+// as a whole, e.g. by `unique` or `max`, have none.
+func invalidItems(c context.Context, e validator.FieldError) []int {
+	items, isList := e.Value().([]string)
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !isList || !ok {
+		return nil
+	}
+	tag := e.Tag()
+	if e.Param() != "" {
+		tag += "=" + e.Param()
+	}
+	indexes := []int{}
+	for i, item := range items {
+		if v.VarCtx(c, []string{item}, tag) != nil {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// fieldProblems lists failed validations of `verrs`, failed list items one by one.
+func fieldProblems(c context.Context, verrs validator.ValidationErrors) []FieldProblem {
+	problems := []FieldProblem{}
+	for _, e := range verrs {
+		paths := []string{fieldPath(e)}
+		if indexes := invalidItems(c, e); len(indexes) > 0 {
+			paths = []string{}
+			for _, i := range indexes {
+				paths = append(paths, fmt.Sprintf("%v[%d]", fieldPath(e), i))
+			}
+		}
+		for _, path := range paths {
+			problems = append(problems, FieldProblem{
+				Field:   path,
+				Tag:     e.Tag(),
+				Param:   e.Param(),
+				Message: Capitalize(validationErrorText(c, e, path)),
+			})
+		}
+	}
+	return problems
+}
+
+// localeOf returns context of the request carrying its message locale.
+func localeOf(ctx *gin.Context) context.Context {
+	return WithLocale(ctx, ctx.GetHeader("Accept-Language"))
+}
+
+// respond writes `errs` of request `c`, the first one is the primary error giving the status, the others are
+// listed as additional errors. They are written as problem details when client accepts them.
+func respond(ctx *gin.Context, c context.Context, errs []reportedError) {
+	primary := errs[0]
+	appError := primary.appError
+	appError.CorrelationId = logging.CorrelationId(ctx)
+	fields := primary.fields
+	for _, e := range errs[1:] {
+		appError.AdditionalErrors = append(appError.AdditionalErrors, e.appError)
+		fields = append(fields, e.fields...)
+	}
+	for _, e := range errs {
+		metrics.AppErrors.WithLabelValues(e.appError.ErrorName).Inc()
+	}
+	ctx.Header("Content-Language", Locale(c).String())
+	if !acceptsProblem(ctx) {
+		ctx.JSON(primary.status, appError)
+		return
+	}
+	ctx.Header("Content-Type", ProblemContentType)
+	ctx.JSON(primary.status, Problem{
+		Type:             ProblemTypePrefix + appError.ErrorCode,
+		Title:            http.StatusText(primary.status),
+		Status:           primary.status,
+		Detail:           appError.Description,
+		Instance:         ctx.Request.URL.Path,
+		ErrorCode:        appError.ErrorCode,
+		CorrelationId:    appError.CorrelationId,
+		Errors:           fields,
+		AdditionalErrors: appError.AdditionalErrors,
+	})
+}
+
+// Abort stops handling of the request with `err`, for middlewares which reject requests before handlers run.
+func Abort(ctx *gin.Context, err *AppErrorWithCode) {
+	ctx.Abort()
+	c := localeOf(ctx)
+	respond(ctx, c, []reportedError{{status: err.Code, appError: err.Localize(c)}})
+}
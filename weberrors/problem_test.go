@@ -0,0 +1,106 @@
+package weberrors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+type ProblemCheckStruct struct {
+	Name     string   `json:"name" validate:"required"`
+	Invitees []string `json:"invitees" validate:"dive,email"`
+}
+
+func serveProblem(r *gin.Engine, path string, accept string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(context.Background(), "GET", path, nil)
+	req.Header.Set("Accept", accept)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestProblemDetails(t *testing.T) {
+	r := gin.New()
+	r.Use(JSONAppErrorReporter())
+	r.GET("/validation-error", func(c *gin.Context) {
+		c.Error(validator.New().Struct(ProblemCheckStruct{Invitees: []string{"a@b.com", "c@d.com", "invalid"}})).SetType(gin.ErrorTypePublic)
+	})
+	r.GET("/not-found", func(c *gin.Context) {
+		c.Error(&NotFound).SetType(gin.ErrorTypePublic)
+	})
+
+	t.Run("validation errors listed per field", func(t *testing.T) {
+		w := serveProblem(r, "/validation-error", "application/problem+json, application/json;q=0.9")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+		problem := Problem{}
+		json.NewDecoder(w.Body).Decode(&problem)
+		assert.Equal(t, Problem{
+			Type:      ProblemTypePrefix + ValidationErrorCode,
+			Title:     "Bad Request",
+			Status:    http.StatusBadRequest,
+			Detail:    "Field `name` is required, field `invitees[2]` is invalid.",
+			Instance:  "/validation-error",
+			ErrorCode: ValidationErrorCode,
+			Errors: []FieldProblem{
+				{Field: "name", Tag: "required", Message: "Field `name` is required"},
+				{Field: "invitees[2]", Tag: "email", Message: "Field `invitees[2]` is invalid"},
+			},
+		}, problem)
+	})
+
+	t.Run("app error without field errors", func(t *testing.T) {
+		w := serveProblem(r, "/not-found", ProblemContentType)
+
+		problem := Problem{}
+		json.NewDecoder(w.Body).Decode(&problem)
+		assert.Equal(t, Problem{
+			Type:      ProblemTypePrefix + NotFoundCode,
+			Title:     "Not Found",
+			Status:    http.StatusNotFound,
+			Detail:    ResourceNotFoundErrorDesc,
+			Instance:  "/not-found",
+			ErrorCode: NotFoundCode,
+		}, problem)
+	})
+
+	t.Run("legacy clients get AppError", func(t *testing.T) {
+		w := serveProblem(r, "/not-found", "application/json")
+
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		var body bytes.Buffer
+		body.ReadFrom(w.Body)
+		appError := AppError{}
+		json.Unmarshal(body.Bytes(), &appError)
+		assert.Equal(t, NotFound.AppError, appError)
+		assert.NotContains(t, body.String(), `"type"`)
+	})
+}
+
+func TestAbort(t *testing.T) {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		Abort(c, &InvalidAdminToken)
+	})
+	handled := false
+	r.GET("/admin", func(c *gin.Context) {
+		handled = true
+	})
+
+	w := serveProblem(r, "/admin", ProblemContentType)
+
+	assert.False(t, handled)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	problem := Problem{}
+	json.NewDecoder(w.Body).Decode(&problem)
+	assert.Equal(t, InvalidAdminTokenCode, problem.ErrorCode)
+	assert.Equal(t, InvalidAdminTokenDesc, problem.Detail)
+}
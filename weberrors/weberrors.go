@@ -1,7 +1,7 @@
 package weberrors
 
 import (
-	"app/utils"
+	"context"
 	"fmt"
 	"strings"
 
@@ -26,50 +26,71 @@ func (e *AppErrorWithCode) ChangeDesc(newDescription string) error {
 	}
 
 	err.AppError.Description = newDescription
+	err.message = ""
 	return &err
 }
 
-var ValidationErrorToText = func(e validator.FieldError) string {
-	field := e.Field()
-	field = strings.ToLower(field[0:1]) + field[1:]
+type allowedValuesKey struct{}
+
+// WithAllowedValues returns `c` carrying lookup of values validation tags allow for the request,
+// e.g. qualities enabled for its tenant, they are listed in error messages.
+func WithAllowedValues(c context.Context, allowed func(c context.Context, tag string) []string) context.Context {
+	return context.WithValue(c, allowedValuesKey{}, allowed)
+}
+
+func allowedValues(c context.Context, tag string) string {
+	allowed, ok := c.Value(allowedValuesKey{}).(func(c context.Context, tag string) []string)
+	if !ok {
+		return ""
+	}
+	return strings.Join(allowed(c, tag), ", ")
+}
+
+// ValidationErrorToText describes `e` to the client in locale of request `c`, allowed values are the ones
+// looked up in `c`, see WithAllowedValues.
+var ValidationErrorToText = func(c context.Context, e validator.FieldError) string {
+	return validationErrorText(c, e, e.StructField())
+}
+
+// validationErrorText describes `e` of field `name`, which is struct field name in AppError descriptions
+// and path in the payload in problem details.
+func validationErrorText(c context.Context, e validator.FieldError, name string) string {
+	locale := Locale(c)
+	field := fieldName(locale, name)
+	args := []any{field}
 	switch e.Tag() {
-	case "required":
-		return fmt.Sprintf("field `%s` is required", field)
-	case "len":
-		return fmt.Sprintf("field `%s` must be of length %s", field, e.Param())
-	case "max":
-		return fmt.Sprintf("field `%s` cannot be longer than %s", field, e.Param())
-	case "min":
-		return fmt.Sprintf("field `%s` must be longer than %s", field, e.Param())
-	case "unique":
-		return fmt.Sprintf("field `%s` contains duplicate values", field)
-	case "oneof":
-		return fmt.Sprintf("field `%s` needs to be one of values: %s", field, e.Param())
-	case "checkEmail":
-		return fmt.Sprintf("field `%s` contains invalid email address", field)
-	case "checkVideoQuality":
-		return fmt.Sprintf(
-			"field `%s` contains invalid resolution (allowed values: %v)",
-			field, strings.Join(utils.ALLOWED_RESOLUTION, ", "),
-		)
-	case "checkAudioQuality":
-		return fmt.Sprintf("field `%s` contains invalid resolution (allowed values: %v)",
-			field, strings.Join(utils.ALLOWED_AUDIO, ", "),
-		)
-	case "checkEventName":
-		return fmt.Sprintf(
-			"field `%s` contains invalid characters (use A-Za-z0-9 _- only)", field)
+	case "len", "max", "min", "oneof":
+		args = append(args, e.Param())
+	case "required_if":
+		args = append(args, requiredIfCondition(locale, e.Param()))
+	case "inField":
+		args = append(args, fieldName(locale, e.Param()))
+	case "checkVideoQuality", "checkAudioQuality":
+		args = append(args, allowedValues(c, e.Tag()))
 	case "checkTimeFieldFormat":
-		return "field `date` does not have correct format (use YYYY-MM-DDTHH:MM:SSZ)"
+		args = nil
 	}
+	if message, ok := translate(locale, "validation."+e.Tag(), args...); ok {
+		return message
+	}
+	message, _ := translate(locale, "validation.invalid", field)
+	return message
+}
 
-	return fmt.Sprintf("field `%s` is invalid", field)
+// requiredIfCondition turns `required_if` param, e.g. `Kind video`, into `kind` is video
+func requiredIfCondition(locale language.Tag, param string) string {
+	name, value, _ := strings.Cut(param, " ")
+	if name == "" {
+		return param
+	}
+	condition, _ := translate(locale, "validation.required_if.condition", Field(name), value)
+	return condition
 }
 
-func GetErrorText(verrs validator.ValidationErrors) string {
+func GetErrorText(c context.Context, verrs validator.ValidationErrors) string {
 	l := []string{}
 	for i, e := range verrs {
-		msg := ValidationErrorToText(e)
+		msg := ValidationErrorToText(c, e)
 
 		if i == 0 {
 			msg = Capitalize(msg)
@@ -1,6 +1,7 @@
 package weberrors
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -40,7 +41,7 @@ func TestGetErrorText(t *testing.T) {
 	t.Run("without errors, should return empty message", func(t *testing.T) {
 		input := []validator.FieldError{}
 
-		r := GetErrorText(input)
+		r := GetErrorText(context.Background(), input)
 
 		assert.Equal(t, "", r)
 	})
@@ -55,7 +56,7 @@ func TestGetErrorText(t *testing.T) {
 		verrs := errs.(validator.ValidationErrors)
 		assert.NotEmpty(t, verrs)
 
-		r := GetErrorText(verrs)
+		r := GetErrorText(context.Background(), verrs)
 
 		assert.Equal(t, "Field `required` is required.", r)
 	})
@@ -71,12 +72,68 @@ func TestGetErrorText(t *testing.T) {
 		verrs := errs.(validator.ValidationErrors)
 		assert.NotEmpty(t, verrs)
 
-		r := GetErrorText(verrs)
+		r := GetErrorText(context.Background(), verrs)
 
 		assert.Equal(t, "Field `required` is required, field `name` must be longer than 1.", r)
 	})
 }
 
+func TestValidationErrorToText(t *testing.T) {
+	t.Run("required_if names the condition", func(t *testing.T) {
+		type TestRequiredIf struct {
+			Kind  string
+			Width int `validate:"required_if=Kind video"`
+		}
+		errs := validator.New().Struct(TestRequiredIf{Kind: "video"})
+		verrs := errs.(validator.ValidationErrors)
+
+		assert.Equal(t, "field `width` is required when `kind` is video", ValidationErrorToText(context.Background(), verrs[0]))
+	})
+
+	t.Run("unknown language suggests BCP 47 tags", func(t *testing.T) {
+		type TestLanguages struct {
+			Languages []string `validate:"checkLanguage"`
+		}
+		validate := validator.New()
+		validate.RegisterValidation("checkLanguage", func(fl validator.FieldLevel) bool { return false })
+		verrs := validate.Struct(TestLanguages{Languages: []string{"Elvish"}}).(validator.ValidationErrors)
+
+		assert.Equal(t, "field `languages` contains unknown language (use BCP 47 tags, e.g. en, fr-CA)",
+			ValidationErrorToText(context.Background(), verrs[0]))
+	})
+
+	t.Run("qualities list values enabled for tenant", func(t *testing.T) {
+		c := WithAllowedValues(context.Background(), func(c context.Context, tag string) []string {
+			assert.Equal(t, "checkVideoQuality", tag)
+			return []string{"720p", "1080p"}
+		})
+		type TestQualities struct {
+			VideoQuality []string `validate:"checkVideoQuality"`
+		}
+		validate := validator.New()
+		validate.RegisterValidation("checkVideoQuality", func(fl validator.FieldLevel) bool { return false })
+		verrs := validate.Struct(TestQualities{VideoQuality: []string{"8k"}}).(validator.ValidationErrors)
+
+		assert.Equal(t, "field `videoQuality` contains invalid resolution (allowed values: 720p, 1080p)",
+			ValidationErrorToText(c, verrs[0]))
+	})
+
+	t.Run("audio qualities are described as audio quality", func(t *testing.T) {
+		c := WithAllowedValues(context.Background(), func(c context.Context, tag string) []string {
+			return []string{"Low", "Mid"}
+		})
+		type TestQualities struct {
+			AudioQuality []string `validate:"checkAudioQuality"`
+		}
+		validate := validator.New()
+		validate.RegisterValidation("checkAudioQuality", func(fl validator.FieldLevel) bool { return false })
+		verrs := validate.Struct(TestQualities{AudioQuality: []string{"Loud"}}).(validator.ValidationErrors)
+
+		assert.Equal(t, "field `audioQuality` contains invalid audio quality (allowed values: Low, Mid)",
+			ValidationErrorToText(c, verrs[0]))
+	})
+}
+
 func TestCapitalize(t *testing.T) {
 	t.Run("with whitespace, should pass through", func(t *testing.T) {
 		assert.Equal(t, "", Capitalize(""))
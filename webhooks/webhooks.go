@@ -0,0 +1,168 @@
+package webhooks
+
+import (
+	"app/db"
+	"app/health"
+	"app/models"
+	"app/tracing"
+	"app/worker"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	SignatureHeader = "X-Signature-256"
+	EventTypeHeader = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+const (
+	maxAttempts = 5
+	// maxQueueLag is how long past due time deliveries may wait before webhooks are reported unhealthy
+	maxQueueLag = 5 * time.Minute
+)
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// Sign returns value of `X-Signature-256` header, hex encoded HMAC-SHA256 of body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks `signature` in constant time, meant for receivers.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// Run sends queued deliveries until `c` is done.
+func Run(c context.Context) {
+	heartbeat := &health.Heartbeat{}
+	heartbeat.Beat(time.Now())
+	health.Register("webhooks", false, 0, worker.Check(heartbeat, deliveryQueue(), maxQueueLag))
+	defer health.Unregister("webhooks")
+	worker.Run(c, "webhook dispatcher", heartbeat, DeliverDueWebhooks)
+}
+
+// deliveryQueue is built on every use, so that replaced storage functions take effect.
+func deliveryQueue() worker.Queue {
+	return worker.Queue{
+		Item:    "webhook delivery",
+		Due:     db.GetDueWebhookDeliveryIds,
+		Acquire: db.AcquireWebhookDeliveryLease,
+		Release: db.ReleaseWebhookDeliveryLease,
+	}
+}
+
+var DeliverDueWebhooks = func(c context.Context, now time.Time) {
+	worker.ProcessDue(c, now, deliveryQueue(), deliver)
+}
+
+func deliver(c context.Context, id string, now time.Time) {
+	delivery, err := db.GetWebhookDelivery(c, id)
+	if err != nil {
+		log.Logger.Error().Msgf("error reading webhook delivery `%v`: %v", id, err)
+		return
+	}
+	if delivery.Status != models.WebhookDeliveryPending {
+		return
+	}
+	subscription, err := db.GetWebhookSubscription(c, delivery.SubscriptionId)
+	if err != nil {
+		if !errors.Is(err, db.ErrNotFound) {
+			log.Logger.Error().Msgf("error reading webhook subscription `%v`: %v", delivery.SubscriptionId, err)
+			return
+		}
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = "subscription was deleted"
+		saveDelivery(c, delivery, now)
+		return
+	}
+	delivery.Attempts++
+	delivery.ResponseStatus, err = post(c, subscription, delivery)
+	if err == nil {
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		saveDelivery(c, delivery, now)
+		return
+	}
+	log.Logger.Warn().Msgf("webhook delivery `%v` failed (attempt %v): %v", id, delivery.Attempts, err)
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= maxAttempts {
+		delivery.Status = models.WebhookDeliveryFailed
+	}
+	saveDelivery(c, delivery, now.Add(worker.Backoff(delivery.Attempts)))
+}
+
+func post(c context.Context, subscription models.WebhookSubscription, delivery models.WebhookDelivery) (int, error) {
+	c, span := tracing.Tracer().Start(c, "POST webhook",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("webhook.delivery_id", delivery.Id),
+			attribute.String("webhook.event_type", delivery.EventType),
+		))
+	defer span.End()
+	status, err := send(c, subscription, delivery)
+	span.SetAttributes(semconv.HTTPStatusCode(status))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return status, err
+}
+
+func send(c context.Context, subscription models.WebhookSubscription, delivery models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	request, err := http.NewRequestWithContext(c, http.MethodPost, subscription.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	tracing.InjectHeaders(c, request.Header)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventTypeHeader, delivery.EventType)
+	request.Header.Set(DeliveryHeader, delivery.Id)
+	request.Header.Set(SignatureHeader, Sign(subscription.Secret, body))
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return response.StatusCode, fmt.Errorf("receiver responded with status %v", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
+
+func saveDelivery(c context.Context, delivery models.WebhookDelivery, sendAt time.Time) {
+	if err := db.SaveWebhookDelivery(c, delivery, sendAt); err != nil {
+		log.Logger.Error().Msgf("error saving webhook delivery `%v`: %v", delivery.Id, err)
+	}
+}
+
+// Redeliver queues delivery to be sent again with fresh attempts.
+var Redeliver = func(c context.Context, delivery models.WebhookDelivery) (models.WebhookDelivery, error) {
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.LastError = ""
+	delivery.ResponseStatus = 0
+	delivery.DeliveredAt = nil
+	err := db.SaveWebhookDelivery(c, delivery, time.Now().UTC())
+	return delivery, err
+}
@@ -0,0 +1,183 @@
+package webhooks
+
+import (
+	"app/db"
+	"app/models"
+	"app/worker"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	testFuncs "app/testing"
+)
+
+func TestSign(t *testing.T) {
+	t.Run("Signature is verifiable by secret holder only", func(t *testing.T) {
+		body := []byte(`{"type":"event.created"}`)
+		signature := Sign("secret", body)
+
+		assert.Equal(t, "sha256=", signature[:7])
+		assert.True(t, Verify("secret", body, signature))
+		assert.False(t, Verify("other-secret", body, signature))
+		assert.False(t, Verify("secret", []byte(`{"type":"event.deleted"}`), signature))
+	})
+}
+
+var now = time.Date(2023, 4, 20, 13, 45, 0, 0, time.UTC)
+
+var DeliverDueWebhooksTestCases = []struct {
+	description            string
+	receiverStatus         int
+	storedAttempts         int
+	expectedStatus         string
+	expectedAttempts       int
+	expectedResponseStatus int
+	expectedSendAt         time.Time
+}{
+	{
+		description:            "Success - delivered",
+		receiverStatus:         http.StatusOK,
+		expectedStatus:         models.WebhookDeliveryDelivered,
+		expectedAttempts:       1,
+		expectedResponseStatus: http.StatusOK,
+		expectedSendAt:         now,
+	},
+	{
+		description:            "Fail - retried with backoff",
+		receiverStatus:         http.StatusInternalServerError,
+		storedAttempts:         1,
+		expectedStatus:         models.WebhookDeliveryPending,
+		expectedAttempts:       2,
+		expectedResponseStatus: http.StatusInternalServerError,
+		expectedSendAt:         now.Add(2 * worker.BaseBackoff),
+	},
+	{
+		description:            "Fail - failed after last attempt",
+		receiverStatus:         http.StatusInternalServerError,
+		storedAttempts:         maxAttempts - 1,
+		expectedStatus:         models.WebhookDeliveryFailed,
+		expectedAttempts:       maxAttempts,
+		expectedResponseStatus: http.StatusInternalServerError,
+		expectedSendAt:         now.Add(worker.Backoff(maxAttempts)),
+	},
+}
+
+func TestDeliverDueWebhooks(t *testing.T) {
+	for _, testCase := range DeliverDueWebhooksTestCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			var receivedBody []byte
+			var receivedHeaders http.Header
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				receivedBody, _ = io.ReadAll(r.Body)
+				receivedHeaders = r.Header
+				w.WriteHeader(testCase.receiverStatus)
+			}))
+			defer receiver.Close()
+			subscription := models.WebhookSubscription{
+				Id: "subscription-id",
+				WebhookSubscriptionData: models.WebhookSubscriptionData{
+					Url:    receiver.URL,
+					Secret: "a-long-random-secret",
+				},
+			}
+			stored := models.WebhookDelivery{
+				Id:             "delivery-id",
+				SubscriptionId: subscription.Id,
+				EventType:      models.WebhookEventCreated,
+				Payload:        `{"id":"delivery-id","type":"event.created"}`,
+				Status:         models.WebhookDeliveryPending,
+				Attempts:       testCase.storedAttempts,
+			}
+			var saved models.WebhookDelivery
+			var sendAt time.Time
+
+			db.GetDueWebhookDeliveryIds = func(c context.Context, dueAt time.Time, limit int64) ([]string, error) {
+				return []string{stored.Id}, nil
+			}
+			db.AcquireWebhookDeliveryLease = func(c context.Context, id string, leaseOwner string, ttl time.Duration) (bool, error) {
+				return true, nil
+			}
+			db.ReleaseWebhookDeliveryLease = func(c context.Context, id string, owner string) error {
+				return nil
+			}
+			db.GetWebhookDelivery = func(c context.Context, id string) (models.WebhookDelivery, error) {
+				return stored, nil
+			}
+			db.GetWebhookSubscription = func(c context.Context, id string) (models.WebhookSubscription, error) {
+				assert.Equal(t, subscription.Id, id)
+				return subscription, nil
+			}
+			db.SaveWebhookDelivery = func(c context.Context, delivery models.WebhookDelivery, at time.Time) error {
+				saved, sendAt = delivery, at
+				return nil
+			}
+
+			spans := testFuncs.UseInMemoryExporter()
+
+			DeliverDueWebhooks(context.Background(), now)
+
+			assert.Equal(t, stored.Payload, string(receivedBody))
+			assert.Len(t, spans.GetSpans(), 1)
+			assert.Contains(t, receivedHeaders.Get("traceparent"), spans.GetSpans()[0].SpanContext.SpanID().String(),
+				"should propagate delivery span to receiver")
+			assert.Equal(t, models.WebhookEventCreated, receivedHeaders.Get(EventTypeHeader))
+			assert.Equal(t, stored.Id, receivedHeaders.Get(DeliveryHeader))
+			assert.True(t, Verify(subscription.Secret, receivedBody, receivedHeaders.Get(SignatureHeader)))
+			assert.Equal(t, testCase.expectedStatus, saved.Status)
+			assert.Equal(t, testCase.expectedAttempts, saved.Attempts)
+			assert.Equal(t, testCase.expectedResponseStatus, saved.ResponseStatus)
+			assert.Equal(t, testCase.expectedSendAt, sendAt)
+		})
+	}
+}
+
+func TestDeliverToDeletedSubscription(t *testing.T) {
+	t.Run("Delivery of deleted subscription fails", func(t *testing.T) {
+		var saved models.WebhookDelivery
+		db.GetWebhookDelivery = func(c context.Context, id string) (models.WebhookDelivery, error) {
+			return models.WebhookDelivery{Id: id, Status: models.WebhookDeliveryPending}, nil
+		}
+		db.GetWebhookSubscription = func(c context.Context, id string) (models.WebhookSubscription, error) {
+			return models.WebhookSubscription{}, db.ErrNotFound
+		}
+		db.SaveWebhookDelivery = func(c context.Context, delivery models.WebhookDelivery, at time.Time) error {
+			saved = delivery
+			return nil
+		}
+
+		deliver(context.Background(), "delivery-id", now)
+
+		assert.Equal(t, models.WebhookDeliveryFailed, saved.Status)
+		assert.Equal(t, "subscription was deleted", saved.LastError)
+	})
+}
+
+func TestRedeliver(t *testing.T) {
+	t.Run("Failed delivery is queued with fresh attempts", func(t *testing.T) {
+		var saved models.WebhookDelivery
+		db.SaveWebhookDelivery = func(c context.Context, delivery models.WebhookDelivery, at time.Time) error {
+			saved = delivery
+			return nil
+		}
+
+		delivery, err := Redeliver(context.Background(), models.WebhookDelivery{
+			Id:             "delivery-id",
+			Status:         models.WebhookDeliveryFailed,
+			Attempts:       maxAttempts,
+			ResponseStatus: http.StatusInternalServerError,
+			LastError:      "receiver responded with status 500",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, models.WebhookDelivery{
+			Id:     "delivery-id",
+			Status: models.WebhookDeliveryPending,
+		}, delivery)
+		assert.Equal(t, delivery, saved)
+	})
+}
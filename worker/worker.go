@@ -0,0 +1,102 @@
+package worker
+
+import (
+	"app/health"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	PollInterval = time.Second
+	LeaseTTL     = 30 * time.Second
+	BatchSize    = 100
+	BaseBackoff  = 10 * time.Second
+	MaxBackoff   = time.Hour
+	// HeartbeatMaxAge is how long a batch may run before the worker is reported unhealthy
+	HeartbeatMaxAge = 30 * PollInterval
+)
+
+// Owner identifies this replica when acquiring leases
+var Owner = uuid.NewString()
+
+// Queue is a queue of items due at given time, processed under leases so that only one replica
+// processes an item at a time.
+type Queue struct {
+	// Item names queued items in logs, e.g. `job`
+	Item    string
+	Due     func(c context.Context, now time.Time, limit int64) ([]string, error)
+	Acquire func(c context.Context, id string, owner string, ttl time.Duration) (bool, error)
+	// Release frees lease of `owner`, lease taken over by another replica after it expired is kept
+	Release func(c context.Context, id string, owner string) error
+}
+
+// Run calls `tick` every PollInterval until `c` is done, `heartbeat` beats after every tick.
+func Run(c context.Context, name string, heartbeat *health.Heartbeat, tick func(c context.Context, now time.Time)) {
+	log.Logger.Info().Msgf("%v started", name)
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Done():
+			log.Logger.Info().Msgf("%v stopped", name)
+			return
+		case now := <-ticker.C:
+			tick(c, now.UTC())
+			heartbeat.Beat(time.Now())
+		}
+	}
+}
+
+// ProcessDue processes at most BatchSize items of `queue` due at `now`, items leased by other replicas are skipped.
+func ProcessDue(c context.Context, now time.Time, queue Queue, process func(c context.Context, id string, now time.Time)) {
+	ids, err := queue.Due(c, now, BatchSize)
+	if err != nil {
+		log.Logger.Error().Msgf("error reading due %vs: %v", queue.Item, err)
+		return
+	}
+	for _, id := range ids {
+		acquired, err := queue.Acquire(c, id, Owner, LeaseTTL)
+		if err != nil {
+			log.Logger.Error().Msgf("error acquiring lease of %v `%v`: %v", queue.Item, id, err)
+			continue
+		}
+		if !acquired {
+			continue
+		}
+		process(c, id, now)
+		if err := queue.Release(c, id, Owner); err != nil {
+			log.Logger.Error().Msgf("error releasing lease of %v `%v`: %v", queue.Item, id, err)
+		}
+	}
+}
+
+// Check fails if the worker beating `heartbeat` stopped running or items of `queue` wait more than `maxLag` past their due time.
+func Check(heartbeat *health.Heartbeat, queue Queue, maxLag time.Duration) health.Check {
+	running := heartbeat.Check(HeartbeatMaxAge)
+	return func(c context.Context) error {
+		if err := running(c); err != nil {
+			return err
+		}
+		overdue, err := queue.Due(c, time.Now().UTC().Add(-maxLag), 1)
+		if err != nil {
+			return err
+		}
+		if len(overdue) > 0 {
+			return fmt.Errorf("%v queue is overdue by more than %v", queue.Item, maxLag)
+		}
+		return nil
+	}
+}
+
+// Backoff doubles the delay with every attempt, starting at BaseBackoff.
+func Backoff(attempts int) time.Duration {
+	delay := BaseBackoff << (attempts - 1)
+	if delay > MaxBackoff || delay <= 0 {
+		return MaxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"app/health"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessDue(t *testing.T) {
+	now := time.Date(2023, 4, 20, 14, 0, 0, 0, time.UTC)
+	processed, released := []string{}, []string{}
+	queue := Queue{
+		Item: "job",
+		Due: func(c context.Context, dueAt time.Time, limit int64) ([]string, error) {
+			assert.Equal(t, now, dueAt)
+			assert.Equal(t, int64(BatchSize), limit)
+			return []string{"leased", "free", "failing"}, nil
+		},
+		Acquire: func(c context.Context, id string, owner string, ttl time.Duration) (bool, error) {
+			assert.Equal(t, Owner, owner)
+			assert.Equal(t, LeaseTTL, ttl)
+			if id == "failing" {
+				return false, errors.New("redis connection error")
+			}
+			return id == "free", nil
+		},
+		Release: func(c context.Context, id string, owner string) error {
+			assert.Equal(t, Owner, owner)
+			released = append(released, id)
+			return nil
+		},
+	}
+
+	ProcessDue(context.Background(), now, queue, func(c context.Context, id string, processedAt time.Time) {
+		assert.Equal(t, now, processedAt)
+		processed = append(processed, id)
+	})
+
+	assert.Equal(t, []string{"free"}, processed)
+	assert.Equal(t, []string{"free"}, released)
+}
+
+func TestCheck(t *testing.T) {
+	heartbeat := &health.Heartbeat{}
+	overdueIds := []string{}
+	check := Check(heartbeat, Queue{
+		Item: "notification",
+		Due: func(c context.Context, now time.Time, limit int64) ([]string, error) {
+			assert.WithinDuration(t, time.Now().UTC().Add(-5*time.Minute), now, time.Second)
+			return overdueIds, nil
+		},
+	}, 5*time.Minute)
+
+	assert.ErrorContains(t, check(context.Background()), "last run", "should fail when worker does not run")
+
+	heartbeat.Beat(time.Now())
+	assert.Nil(t, check(context.Background()))
+
+	overdueIds = []string{"notification-id"}
+	assert.EqualError(t, check(context.Background()), "notification queue is overdue by more than 5m0s")
+}
+
+func TestBackoff(t *testing.T) {
+	t.Run("Backoff is capped", func(t *testing.T) {
+		assert.Equal(t, BaseBackoff, Backoff(1))
+		assert.Equal(t, 2*BaseBackoff, Backoff(2))
+		assert.Equal(t, MaxBackoff, Backoff(20))
+		assert.Equal(t, MaxBackoff, Backoff(100))
+	})
+}